@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"errors"
+	"expvar"
 	"log/slog"
 	"net/http"
 	"os"
@@ -18,7 +19,10 @@ import (
 	accountHandler "github.com/mthpedrosa/financial-exchange-challenge/internal/account/adapters/api"
 	accountRepo "github.com/mthpedrosa/financial-exchange-challenge/internal/account/adapters/repository"
 	accountApp "github.com/mthpedrosa/financial-exchange-challenge/internal/account/app"
-	"github.com/mthpedrosa/financial-exchange-challenge/internal/db"
+	database "github.com/mthpedrosa/financial-exchange-challenge/internal/db"
+	feeHandler "github.com/mthpedrosa/financial-exchange-challenge/internal/fee/adapters/api"
+	feeRepo "github.com/mthpedrosa/financial-exchange-challenge/internal/fee/adapters/repository"
+	feeApp "github.com/mthpedrosa/financial-exchange-challenge/internal/fee/app"
 	instrumentHandler "github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/adapters/api"
 	instrumentRepo "github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/adapters/repository"
 	instrumentApp "github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/app"
@@ -30,9 +34,16 @@ import (
 
 	_ "github.com/mthpedrosa/financial-exchange-challenge/docs"
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/logger"
+	matchingHandler "github.com/mthpedrosa/financial-exchange-challenge/internal/matching/adapters/api"
+	matchingJournal "github.com/mthpedrosa/financial-exchange-challenge/internal/matching/adapters/journal"
+	matchingQueue "github.com/mthpedrosa/financial-exchange-challenge/internal/matching/adapters/queue"
+	matchingApp "github.com/mthpedrosa/financial-exchange-challenge/internal/matching/app"
 	orderHandler "github.com/mthpedrosa/financial-exchange-challenge/internal/order/adapters/api"
 	orderRepo "github.com/mthpedrosa/financial-exchange-challenge/internal/order/adapters/repository"
 	orderApp "github.com/mthpedrosa/financial-exchange-challenge/internal/order/app"
+	tradeHandler "github.com/mthpedrosa/financial-exchange-challenge/internal/trade/adapters/api"
+	tradeRepo "github.com/mthpedrosa/financial-exchange-challenge/internal/trade/adapters/repository"
+	tradeApp "github.com/mthpedrosa/financial-exchange-challenge/internal/trade/app"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
@@ -55,7 +66,7 @@ func main() {
 	slog.SetDefault(log)
 
 	// migrations
-	db.RunMigrations(cfg.DatabaseURL)
+	database.RunMigrations(cfg.DatabaseURL)
 
 	// postgres connection
 	db, err := pgxpool.New(context.Background(), cfg.DatabaseURL)
@@ -93,6 +104,35 @@ func main() {
 		os.Exit(1)
 	}
 
+	// messages the engine failed to process, kept for inspection
+	deadLetterQueue, err := rabbitChannel.QueueDeclare(
+		"orders.dead", // queue name
+		true,          // durable
+		false,         // delete when unused
+		false,         // exclusive
+		false,         // no-wait
+		nil,           // arguments
+	)
+	if err != nil {
+		slog.Error("Unable to declare RabbitMQ queue", "error", err)
+		os.Exit(1)
+	}
+
+	// engine events such as circuit breaker pauses, routed by event type
+	err = rabbitChannel.ExchangeDeclare(
+		"engine.events", // exchange name
+		"topic",         // kind
+		true,            // durable
+		false,           // auto-deleted
+		false,           // internal
+		false,           // no-wait
+		nil,             // arguments
+	)
+	if err != nil {
+		slog.Error("Unable to declare RabbitMQ exchange", "error", err)
+		os.Exit(1)
+	}
+
 	// check connection
 	if err := db.Ping(context.Background()); err != nil {
 		slog.Error("Unable to ping database", "error", err)
@@ -100,33 +140,108 @@ func main() {
 	}
 	slog.Info("Successfully connected to the database")
 
+	txManager := database.NewTxManager(db)
+
 	// repository
 	accountRepository := accountRepo.NewAccountRepository(db)
 	instrumentRepository := instrumentRepo.NewInstrumentRepository(db)
 	balanceRepository := balanceRepo.NewBalanceRepository(db)
 	orderQueueRepository := orderRepo.NewOrderQueueRepository(rabbitChannel, queue.Name)
 	orderRepository := orderRepo.NewOrderRepository(db)
+	orderListRepository := orderRepo.NewOrderListRepository(db)
+	tradeRepository := tradeRepo.NewTradeRepository(db)
+	feeRepository := feeRepo.NewFeeRepository(db)
 
 	// application
 	accountApp := accountApp.NewAccountApp(accountRepository)
-	instrumentApp := instrumentApp.NewInstrumentApp(instrumentRepository)
+	instrumentApp := instrumentApp.NewInstrumentApp(instrumentRepository, orderQueueRepository, txManager)
+	settlementApp := balanceApp.NewSettlementApp(balanceRepository, tradeRepository, txManager, cfg.FeeAccountID)
 	balanceApp := balanceApp.NewBalanceApp(balanceRepository, accountRepository)
 	orderApp := orderApp.NewOrderApp(
 		orderRepository,
+		orderListRepository,
 		accountRepository,
 		instrumentRepository,
 		balanceRepository,
+		tradeRepository,
 		orderQueueRepository,
+		txManager,
 	)
+	tradeApp := tradeApp.NewTradeApp(tradeRepository)
+	feeService := feeApp.NewFeeApp(
+		feeRepository,
+		accountRepository,
+		instrumentRepository,
+		tradeRepository,
+		txManager,
+		cfg.FeeReferenceAsset,
+	)
+
+	// matching engine
+	consumerChannel, err := rabbitConn.Channel()
+	if err != nil {
+		slog.Error("Unable to open RabbitMQ consumer channel", "error", err)
+		os.Exit(1)
+	}
+	defer consumerChannel.Close()
+
+	engineJournal, err := matchingJournal.NewFileJournal(cfg.JournalPath)
+	if err != nil {
+		slog.Error("Unable to open engine journal", "path", cfg.JournalPath, "error", err)
+		os.Exit(1)
+	}
+	defer engineJournal.Close()
+
+	matchingEngine := matchingApp.NewEngine(
+		orderRepository,
+		orderListRepository,
+		settlementApp,
+		balanceRepository,
+		instrumentRepository,
+		tradeRepository,
+		feeService,
+		matchingQueue.NewEventPublisher(rabbitChannel, "engine.events"),
+		engineJournal,
+		txManager,
+	)
+	orderConsumer := matchingQueue.NewOrderConsumer(consumerChannel, queue.Name, deadLetterQueue.Name, matchingEngine)
+
+	// rebuild the books before any message is consumed or request served;
+	// unacknowledged messages are redelivered once the consumer starts
+	if err := matchingEngine.Recover(context.Background()); err != nil {
+		slog.Error("Unable to recover order books", "error", err)
+		os.Exit(1)
+	}
+
+	consumerCtx, stopConsumer := context.WithCancel(context.Background())
+	defer stopConsumer()
+
+	go func() {
+		if err := orderConsumer.Start(consumerCtx); err != nil {
+			slog.Error("Order consumer stopped", "error", err)
+		}
+	}()
+
+	expiryWorker := matchingApp.NewExpiryWorker(matchingEngine, time.Second)
+	go expiryWorker.Run(consumerCtx)
+
+	auctionWorker := matchingApp.NewAuctionWorker(matchingEngine, time.Second)
+	go auctionWorker.Run(consumerCtx)
+
+	tierWorker := feeApp.NewTierWorker(feeService)
+	go tierWorker.Run(consumerCtx)
 
 	// handler
 	accountHandler := accountHandler.NewAccountHandler(accountApp)
 	instrumentHandler := instrumentHandler.NewInstrumentHandler(instrumentApp)
 	balanceHandler := balanceHandler.NewBalanceHandler(balanceApp)
 	orderHandler := orderHandler.NewOrderHandler(orderApp)
+	tradeHandler := tradeHandler.NewTradeHandler(tradeApp)
+	bookHandler := matchingHandler.NewBookHandler(matchingEngine)
+	feeHandler := feeHandler.NewFeeHandler(feeService)
 
 	// setup server
-	server := setupServer(cfg, accountHandler, instrumentHandler, balanceHandler, orderHandler)
+	server := setupServer(cfg, accountHandler, instrumentHandler, balanceHandler, orderHandler, tradeHandler, bookHandler, feeHandler)
 
 	// graceful Shutdown
 	go func() {
@@ -143,6 +258,9 @@ func main() {
 
 	slog.Warn("Shutting down server...")
 
+	// stop consuming before closing connections
+	stopConsumer()
+
 	// shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -154,7 +272,7 @@ func main() {
 	slog.Info("Server shut down gracefully")
 }
 
-func setupServer(cfg config.Config, accountHandler accountHandler.Account, instrumentHandler instrumentHandler.Instrument, balanceHandler balanceHandler.Balance, orderHandler orderHandler.Order) *echo.Echo {
+func setupServer(cfg config.Config, accountHandler accountHandler.Account, instrumentHandler instrumentHandler.Instrument, balanceHandler balanceHandler.Balance, orderHandler orderHandler.Order, tradeHandler tradeHandler.Trade, bookHandler matchingHandler.Book, feeHandler feeHandler.Fee) *echo.Echo {
 	server := echo.New()
 
 	// cors
@@ -171,6 +289,9 @@ func setupServer(cfg config.Config, accountHandler accountHandler.Account, instr
 		return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
 	})
 
+	// expvar metrics, such as the engine journal failures
+	server.GET("/debug/vars", echo.WrapHandler(expvar.Handler()))
+
 	// swagger
 	server.GET("/swagger/*", echoSwagger.WrapHandler)
 
@@ -180,6 +301,11 @@ func setupServer(cfg config.Config, accountHandler accountHandler.Account, instr
 	instrumentHandler.RegisterRoutes(v1.Group("/instruments"))
 	balanceHandler.RegisterRoutes(v1.Group("/balances"))
 	orderHandler.RegisterRoutes(v1.Group("/orders"))
+	orderHandler.RegisterListRoutes(v1.Group("/order-lists"))
+	tradeHandler.RegisterRoutes(v1.Group("/trades"))
+	bookHandler.RegisterRoutes(v1.Group("/book"))
+	feeHandler.RegisterRoutes(v1.Group("/fees"))
+	feeHandler.RegisterAccountRoutes(v1.Group("/accounts"))
 
 	return server
 }
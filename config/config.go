@@ -16,6 +16,13 @@ type Config struct {
 	DatabaseURL string `mapstructure:"DATABASE_URL" validate:"required"`
 	RabbitURL   string `mapstructure:"RABBITMQ_URL"   validate:"required"`
 	JWTSecret   string `mapstructure:"JWT_SECRET"     validate:"required"`
+	// FeeAccountID is the house account credited with every trading fee.
+	FeeAccountID string `mapstructure:"FEE_ACCOUNT_ID" validate:"required,uuid"`
+	// FeeReferenceAsset is the quote asset 30-day volumes are measured in.
+	FeeReferenceAsset string `mapstructure:"FEE_REFERENCE_ASSET" validate:"required"`
+	// JournalPath is the file the matching engine journals its inputs and
+	// outputs to.
+	JournalPath string `mapstructure:"JOURNAL_PATH" validate:"required"`
 }
 
 func LoadConfig() Config {
@@ -32,6 +39,10 @@ func LoadConfig() Config {
 		RabbitURL:   os.Getenv("RABBITMQ_URL"),
 		JWTSecret:   os.Getenv("JWT_SECRET"),
 		AppName:     getEnv("APP_NAME", "Exchange API"),
+		// the house account seeded by the migrations
+		FeeAccountID:      getEnv("FEE_ACCOUNT_ID", "00000000-0000-0000-0000-000000000001"),
+		FeeReferenceAsset: getEnv("FEE_REFERENCE_ASSET", "USDT"),
+		JournalPath:       getEnv("JOURNAL_PATH", "data/engine.journal"),
 	}
 
 	validate := validator.New()
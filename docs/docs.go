@@ -249,6 +249,44 @@ const docTemplate = `{
                 }
             }
         },
+        "/v1/accounts/{id}/fee-tier": {
+            "get": {
+                "description": "Retorna o nível de taxa atual da conta, o volume de 30 dias no ativo de referência e o progresso até o próximo nível, conforme o último recálculo noturno.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "accounts"
+                ],
+                "summary": "Busca o nível de taxa de uma conta",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_fee_domain_dto.AccountTierDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/v1/balances": {
             "post": {
                 "description": "Cria um novo balance para uma conta e asset",
@@ -303,6 +341,7 @@ const docTemplate = `{
         },
         "/v1/balances/account/{account_id}": {
             "get": {
+                "description": "Retorna o total, o disponível e o valor bloqueado por ordens abertas de cada asset",
                 "produces": [
                     "application/json"
                 ],
@@ -498,26 +537,28 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/instruments": {
+        "/v1/book/{instrument_id}": {
             "get": {
+                "description": "Níveis de preço agregados, do melhor para o pior. Ordens iceberg mostram apenas a parte visível. Durante um leilão, indicative_price e indicative_quantity trazem o cruzamento indicativo.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "instruments"
+                    "book"
                 ],
-                "summary": "Lista instrumentos financeiros",
+                "summary": "Retorna o livro de ofertas de um instrumento",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Base Asset",
-                        "name": "base_asset",
-                        "in": "query"
+                        "description": "Instrument ID",
+                        "name": "instrument_id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Quote Asset",
-                        "name": "quote_asset",
+                        "type": "integer",
+                        "description": "Número máximo de níveis por lado",
+                        "name": "depth",
                         "in": "query"
                     }
                 ],
@@ -525,42 +566,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.InstrumentListDTO"
-                            }
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Cria um novo instrumento financeiro",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "instruments"
-                ],
-                "summary": "Cria um novo instrumento",
-                "parameters": [
-                    {
-                        "description": "Instrument",
-                        "name": "instrument",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.CreateInstrumentRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.CreateInstrumentResponse"
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_matching_domain_dto.BookDTO"
                         }
                     },
                     "400": {
@@ -571,33 +577,24 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
-                    },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
                     }
                 }
             }
         },
-        "/v1/instruments/{id}": {
+        "/v1/fees/accounts/{account_id}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "instruments"
+                    "fees"
                 ],
-                "summary": "Busca um instrumento por ID",
+                "summary": "Busca as taxas personalizadas de uma conta",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Instrument ID",
-                        "name": "id",
+                        "description": "Account ID",
+                        "name": "account_id",
                         "in": "path",
                         "required": true
                     }
@@ -606,7 +603,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.InstrumentDTO"
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_fee_domain_dto.OverrideDTO"
                         }
                     },
                     "404": {
@@ -621,6 +618,7 @@ const docTemplate = `{
                 }
             },
             "put": {
+                "description": "Substitui as taxas maker e/ou taker dos instrumentos para a conta. Um lado omitido segue a taxa do instrumento.",
                 "consumes": [
                     "application/json"
                 ],
@@ -628,24 +626,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "instruments"
+                    "fees"
                 ],
-                "summary": "Atualiza um instrumento",
+                "summary": "Define taxas personalizadas para uma conta",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Instrument ID",
-                        "name": "id",
+                        "description": "Account ID",
+                        "name": "account_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Instrument",
-                        "name": "instrument",
+                        "description": "Fee override",
+                        "name": "override",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.CreateInstrumentRequest"
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_fee_domain_dto.UpdateOverrideRequest"
                         }
                     }
                 ],
@@ -653,7 +651,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.InstrumentDTO"
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_fee_domain_dto.OverrideDTO"
                         }
                     },
                     "400": {
@@ -673,15 +671,6 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
-                    },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
                     }
                 }
             },
@@ -690,14 +679,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "instruments"
+                    "fees"
                 ],
-                "summary": "Deleta um instrumento",
+                "summary": "Remove as taxas personalizadas de uma conta",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Instrument ID",
-                        "name": "id",
+                        "description": "Account ID",
+                        "name": "account_id",
                         "in": "path",
                         "required": true
                     }
@@ -718,29 +707,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/orders": {
+        "/v1/fees/instruments/{instrument_id}": {
             "get": {
+                "description": "Retorna as taxas maker e taker do instrumento em basis points. Instrumentos sem tabela de taxas cobram zero.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "fees"
+                ],
+                "summary": "Busca as taxas de um instrumento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Instrument ID",
+                        "name": "instrument_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Lista todas as ordens",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.OrderDTO"
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_fee_domain_dto.ScheduleDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
             },
-            "post": {
-                "description": "Cria uma nova ordem e envia para a fila",
+            "put": {
+                "description": "Define as taxas maker e taker do instrumento em basis points (0 a 10000)",
                 "consumes": [
                     "application/json"
                 ],
@@ -748,25 +753,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "fees"
                 ],
-                "summary": "Cria uma nova ordem",
+                "summary": "Define as taxas de um instrumento",
                 "parameters": [
                     {
-                        "description": "Order",
-                        "name": "order",
+                        "type": "string",
+                        "description": "Instrument ID",
+                        "name": "instrument_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fee schedule",
+                        "name": "schedule",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.CreateOrderRequest"
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_fee_domain_dto.UpdateScheduleRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.OrderDTO"
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_fee_domain_dto.ScheduleDTO"
                         }
                     },
                     "400": {
@@ -778,8 +790,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -790,87 +802,72 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/orders/instrument/{instrumentID}": {
+        "/v1/fees/tiers": {
             "get": {
+                "description": "Lista os níveis de taxa por volume de 30 dias. As taxas de cada nível limitam as taxas dos instrumentos; taxa nula não limita.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
-                ],
-                "summary": "Busca as orders por um Intrument",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Instrument ID",
-                        "name": "instrument_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Asset",
-                        "name": "asset",
-                        "in": "path",
-                        "required": true
-                    }
+                    "fees"
                 ],
+                "summary": "Lista os níveis de taxa",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.OrderDTO"
+                                "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_fee_domain_dto.TierDTO"
                             }
                         }
-                    },
-                    "404": {
-                        "description": "instrumentID required",
-                        "schema": {
-                            "type": "message"
-                        }
                     }
                 }
             }
         },
-        "/v1/orders/{id}": {
+        "/v1/instruments": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "instruments"
                 ],
-                "summary": "Busca uma ordem por ID",
+                "summary": "Lista instrumentos financeiros",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Order ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Base Asset",
+                        "name": "base_asset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quote Asset",
+                        "name": "quote_asset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.OrderDTO"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.InstrumentListDTO"
                             }
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
+                "description": "Cria um novo instrumento financeiro com regras de negociação opcionais: tick_size, lot_size, min_quantity, max_quantity e min_notional, e proteção de preço opcional: reference_price, price_band_percent e o circuit breaker (circuit_breaker_percent, circuit_breaker_window_seconds, circuit_breaker_auction_seconds). O status inicial é TRADING, a menos que seja informado PRE_OPEN ou POST_ONLY.",
                 "consumes": [
                     "application/json"
                 ],
@@ -878,30 +875,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "instruments"
                 ],
-                "summary": "Atualiza uma ordem",
+                "summary": "Cria um novo instrumento",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Order ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Order",
-                        "name": "order",
+                        "description": "Instrument",
+                        "name": "instrument",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.CreateOrderRequest"
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.CreateInstrumentRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.CreateInstrumentResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -912,6 +905,142 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/instruments/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "instruments"
+                ],
+                "summary": "Busca um instrumento por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Instrument ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.InstrumentDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Substitui os ativos e as regras de negociação do instrumento; uma regra omitida deixa de ser aplicada",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "instruments"
+                ],
+                "summary": "Atualiza um instrumento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Instrument ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Instrument",
+                        "name": "instrument",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.CreateInstrumentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.InstrumentDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove o instrumento definitivamente. Para instrumentos que já tiveram ordens use /delist.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "instruments"
+                ],
+                "summary": "Deleta um instrumento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Instrument ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -924,27 +1053,52 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/orders/{id}/cancel": {
+        "/v1/instruments/{id}/auction": {
             "post": {
+                "description": "Coloca o instrumento em AUCTION por duration_seconds: ordens limit GTC ou GTD são acumuladas sem casar e o preço e o volume indicativos aparecem em /v1/book. Ao final, o livro é cruzado a um único preço que maximiza o volume executado e o instrumento volta a TRADING.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "instruments"
                 ],
-                "summary": "Cancela uma ordem",
+                "summary": "Inicia um leilão para um instrumento",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Order ID",
+                        "description": "Instrument ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Reason and duration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.AuctionRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.InstrumentDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     },
                     "404": {
                         "description": "Not Found",
@@ -954,142 +1108,1554 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "409": {
+                        "description": "instrument is delisted or already in an auction",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "big.Float": {
-            "type": "object"
         },
-        "github_com_mthpedrosa_financial-exchange-challenge_internal_account_domain_dto.AccountDTO": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
+        "/v1/instruments/{id}/delist": {
+            "post": {
+                "description": "Coloca o instrumento em DELISTED de forma definitiva e cancela as ordens ainda ativas. Diferente do DELETE, o instrumento e o histórico de ordens e trades são mantidos.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "instruments"
+                ],
+                "summary": "Deslista um instrumento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Instrument ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.StatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.InstrumentDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "instrument is already delisted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/instruments/{id}/halt": {
+            "post": {
+                "description": "Coloca o instrumento em HALTED: o livro é mantido e apenas cancelamentos são aceitos até a retomada. A mudança fica registrada no histórico com o motivo.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "instruments"
+                ],
+                "summary": "Suspende a negociação de um instrumento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Instrument ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.StatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.InstrumentDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "instrument cannot be halted from its current status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/instruments/{id}/resume": {
+            "post": {
+                "description": "Reabre um instrumento HALTED ou PRE_OPEN em TRADING ou, se informado, em POST_ONLY. A mudança fica registrada no histórico com o motivo.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "instruments"
+                ],
+                "summary": "Retoma a negociação de um instrumento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Instrument ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason and status",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.ResumeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.InstrumentDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "instrument is not halted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/instruments/{id}/status-history": {
+            "get": {
+                "description": "Lista as mudanças de status do instrumento com os motivos, da mais antiga para a mais recente",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "instruments"
+                ],
+                "summary": "Lista o histórico de status de um instrumento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Instrument ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.StatusChangeDTO"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/order-lists/{id}": {
+            "get": {
+                "description": "Retorna a lista com seu status (EXECUTING enquanto as duas ordens estão ativas, ALL_DONE depois), a reserva compartilhada e as ordens.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Busca uma lista de ordens por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order list ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.OrderListDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orders": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Lista todas as ordens",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.OrderDTO"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Cria uma nova ordem LIMIT, MARKET, STOP_MARKET, STOP_LIMIT ou TRAILING_STOP_MARKET e envia para a fila. Ordens MARKET não têm preço; ordens TRAILING_STOP_MARKET usam trail_amount ou trail_percent no lugar de stop_price; compras MARKET usam quote_quantity (time_in_force: GTC, IOC, FOK ou GTD com expires_at). Uma nova tentativa com o mesmo Idempotency-Key retorna a ordem criada pela primeira, sem duplicá-la; reutilizar a chave para uma ordem diferente retorna 409. Ordens que violam as regras de negociação do instrumento (tick size, lot size, quantidade mínima/máxima, notional mínimo) são rejeitadas com a lista de motivos.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Cria uma nova ordem",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chave de idempotência, única por conta",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.CreateOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.CreateOrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "order breaks the instrument's trading rules",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.RejectedOrderResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "client_order_id already in use or instrument not trading",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "insufficient balance",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orders/batch": {
+            "post": {
+                "description": "Cria até 50 ordens numa única chamada e retorna, na ordem do pedido, o ID ou o erro de cada uma, com os motivos quando a ordem viola as regras de negociação. O saldo é reservado considerando o lote inteiro, então duas ordens nunca usam os mesmos fundos.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Cria ordens em lote",
+                "parameters": [
+                    {
+                        "description": "Orders",
+                        "name": "orders",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.BatchCreateOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.BatchCreateOrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "client_order_id already in use",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orders/by-client-id/{client_order_id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Busca uma ordem pelo client_order_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client order ID",
+                        "name": "client_order_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "account_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.OrderDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orders/cancel-all": {
+            "post": {
+                "description": "Cancela, numa única operação do motor de matching, todas as ordens ativas que atendem ao filtro (account_id e/ou instrument_id, opcionalmente side) e retorna os IDs cancelados.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Cancela ordens em massa",
+                "parameters": [
+                    {
+                        "description": "Filter",
+                        "name": "filter",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.CancelAllRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.CancelAllResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "cancel was not confirmed in time",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orders/instrument/{instrumentID}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Busca as orders por um Intrument",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Instrument ID",
+                        "name": "instrument_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Asset",
+                        "name": "asset",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.OrderDTO"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "instrumentID required",
+                        "schema": {
+                            "type": "message"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orders/oco": {
+            "post": {
+                "description": "Cria duas ordens ligadas (one-cancels-other) com um ID de lista comum: uma ordem LIMIT e uma STOP_MARKET ou STOP_LIMIT da mesma conta, instrumento e lado, tipicamente um take-profit e um stop-loss. O preço LIMIT fica acima do stop_price nas vendas e abaixo nas compras. Uma única reserva de saldo, a maior entre as duas ordens, cobre ambas. Quando uma ordem é executada, total ou parcialmente, ou a ordem stop é disparada, o motor cancela a outra.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Cria uma lista de ordens OCO",
+                "parameters": [
+                    {
+                        "description": "Order list",
+                        "name": "list",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.CreateOrderListRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.CreateOrderListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "order breaks the instrument's trading rules",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.RejectedOrderResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "client_order_id already in use or instrument not trading",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "insufficient balance",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orders/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Busca uma ordem por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.OrderDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Altera preço e/ou quantidade total de uma ordem ativa. Reduzir a quantidade mantém a prioridade; alterar o preço ou aumentar a quantidade a perde. A alteração é aplicada de forma assíncrona pelo motor de matching.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Altera uma ordem em aberto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Amendment",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.AmendOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    },
+                    "400": {
+                        "description": "order breaks the instrument's trading rules",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.RejectedOrderResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "order is no longer active",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "insufficient balance",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orders/{id}/cancel": {
+            "post": {
+                "description": "Envia um comando de cancelamento ao motor de matching e aguarda a confirmação. A ordem só é considerada cancelada, e o saldo reservado liberado, após a confirmação; 409 indica que a ordem já estava executada ou cancelada.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Cancela uma ordem",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "order is no longer active",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "cancel was not confirmed in time",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/trades": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trades"
+                ],
+                "summary": "Lista as execuções (trades)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Instrument ID",
+                        "name": "instrument_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account ID (maker ou taker)",
+                        "name": "account_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_trade_domain_dto.TradeDTO"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "big.Float": {
+            "type": "object"
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_account_domain_dto.AccountDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "self_trade_prevention": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_account_domain_dto.AccountListDTO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_account_domain_dto.CreateAccountRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "self_trade_prevention": {
+                    "type": "string",
+                    "enum": [
+                        "NONE",
+                        "CANCEL_NEWEST",
+                        "CANCEL_OLDEST",
+                        "CANCEL_BOTH",
+                        "DECREMENT_AND_CANCEL"
+                    ]
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_account_domain_dto.CreateAccountResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_account_domain_dto.UpdateAccountRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "self_trade_prevention": {
+                    "type": "string",
+                    "enum": [
+                        "NONE",
+                        "CANCEL_NEWEST",
+                        "CANCEL_OLDEST",
+                        "CANCEL_BOTH",
+                        "DECREMENT_AND_CANCEL"
+                    ]
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_balance_domain_dto.BalanceListDTO": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "asset": {
+                    "type": "string"
+                },
+                "available": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "locked": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "total": {
+                    "$ref": "#/definitions/big.Float"
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_balance_domain_dto.BigFloat": {
+            "type": "object"
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_balance_domain_dto.CreateBalanceRequest": {
+            "type": "object",
+            "required": [
+                "account_id",
+                "amount",
+                "asset"
+            ],
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "amount": {
+                    "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_balance_domain_dto.BigFloat"
+                },
+                "asset": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_balance_domain_dto.UpdateBalanceRequest": {
+            "type": "object",
+            "required": [
+                "amount"
+            ],
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/big.Float"
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_fee_domain_dto.AccountTierDTO": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "computed_at": {
+                    "type": "string"
+                },
+                "next_tier": {
+                    "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_fee_domain_dto.TierDTO"
+                },
+                "progress_pct": {
+                    "type": "number"
+                },
+                "reference_asset": {
+                    "type": "string"
+                },
+                "tier": {
+                    "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_fee_domain_dto.TierDTO"
+                },
+                "volume_30d": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "volume_to_next_tier": {
+                    "$ref": "#/definitions/big.Float"
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_fee_domain_dto.OverrideDTO": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "maker_fee_bps": {
+                    "type": "integer"
+                },
+                "taker_fee_bps": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_fee_domain_dto.ScheduleDTO": {
+            "type": "object",
+            "properties": {
+                "instrument_id": {
+                    "type": "string"
+                },
+                "maker_fee_bps": {
+                    "type": "integer"
+                },
+                "taker_fee_bps": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_fee_domain_dto.TierDTO": {
+            "type": "object",
+            "properties": {
+                "level": {
+                    "type": "integer"
+                },
+                "maker_fee_bps": {
+                    "type": "integer"
+                },
+                "min_volume": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "taker_fee_bps": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_fee_domain_dto.UpdateOverrideRequest": {
+            "type": "object",
+            "properties": {
+                "maker_fee_bps": {
+                    "type": "integer",
+                    "maximum": 10000,
+                    "minimum": 0
+                },
+                "taker_fee_bps": {
+                    "type": "integer",
+                    "maximum": 10000,
+                    "minimum": 0
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_fee_domain_dto.UpdateScheduleRequest": {
+            "type": "object",
+            "required": [
+                "maker_fee_bps",
+                "taker_fee_bps"
+            ],
+            "properties": {
+                "maker_fee_bps": {
+                    "type": "integer",
+                    "maximum": 10000,
+                    "minimum": 0
+                },
+                "taker_fee_bps": {
+                    "type": "integer",
+                    "maximum": 10000,
+                    "minimum": 0
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.AuctionRequest": {
+            "type": "object",
+            "required": [
+                "duration_seconds",
+                "reason"
+            ],
+            "properties": {
+                "duration_seconds": {
+                    "type": "integer",
+                    "maximum": 3600,
+                    "minimum": 1
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.CreateInstrumentRequest": {
+            "type": "object",
+            "required": [
+                "base_asset",
+                "quote_asset"
+            ],
+            "properties": {
+                "base_asset": {
+                    "type": "string"
+                },
+                "circuit_breaker_auction_seconds": {
+                    "type": "integer",
+                    "maximum": 3600,
+                    "minimum": 0
+                },
+                "circuit_breaker_percent": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "circuit_breaker_window_seconds": {
+                    "type": "integer",
+                    "maximum": 86400,
+                    "minimum": 0
+                },
+                "lot_size": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "max_quantity": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "min_notional": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "min_quantity": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "price_band_percent": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "quote_asset": {
+                    "type": "string"
+                },
+                "reference_price": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "PRE_OPEN",
+                        "TRADING",
+                        "POST_ONLY"
+                    ]
+                },
+                "tick_size": {
+                    "$ref": "#/definitions/big.Float"
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.CreateInstrumentResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.InstrumentDTO": {
+            "type": "object",
+            "properties": {
+                "auction_ends_at": {
+                    "type": "string"
+                },
+                "base_asset": {
+                    "type": "string"
+                },
+                "circuit_breaker_auction_seconds": {
+                    "type": "integer"
+                },
+                "circuit_breaker_percent": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "circuit_breaker_window_seconds": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lot_size": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "max_quantity": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "min_notional": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "min_quantity": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "price_band_percent": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "quote_asset": {
+                    "type": "string"
+                },
+                "reference_price": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tick_size": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.InstrumentListDTO": {
+            "type": "object",
+            "properties": {
+                "base_asset": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "quote_asset": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.ResumeRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "TRADING",
+                        "POST_ONLY"
+                    ]
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.StatusChangeDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.StatusRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_matching_domain_dto.BookDTO": {
+            "type": "object",
+            "properties": {
+                "asks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_matching_domain_dto.PriceLevelDTO"
+                    }
+                },
+                "bids": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_matching_domain_dto.PriceLevelDTO"
+                    }
+                },
+                "indicative_price": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "indicative_quantity": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "instrument_id": {
+                    "type": "string"
+                },
+                "last_price": {
+                    "$ref": "#/definitions/big.Float"
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_matching_domain_dto.PriceLevelDTO": {
+            "type": "object",
+            "properties": {
+                "orders": {
+                    "type": "integer"
+                },
+                "price": {
+                    "$ref": "#/definitions/big.Float"
                 },
-                "updated_at": {
-                    "type": "string"
+                "quantity": {
+                    "$ref": "#/definitions/big.Float"
                 }
             }
         },
-        "github_com_mthpedrosa_financial-exchange-challenge_internal_account_domain_dto.AccountListDTO": {
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.AmendOrderRequest": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "string"
+                "price": {
+                    "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.BigFloat"
                 },
-                "name": {
-                    "type": "string"
+                "quantity": {
+                    "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.BigFloat"
                 }
             }
         },
-        "github_com_mthpedrosa_financial-exchange-challenge_internal_account_domain_dto.CreateAccountRequest": {
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.BatchCreateOrderRequest": {
             "type": "object",
-            "required": [
-                "email",
-                "name"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.CreateOrderRequest"
+                    }
                 }
             }
         },
-        "github_com_mthpedrosa_financial-exchange-challenge_internal_account_domain_dto.CreateAccountResponse": {
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.BatchCreateOrderResponse": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "string"
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.BatchOrderResult"
+                    }
                 }
             }
         },
-        "github_com_mthpedrosa_financial-exchange-challenge_internal_account_domain_dto.UpdateAccountRequest": {
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.BatchOrderResult": {
             "type": "object",
-            "required": [
-                "email",
-                "name"
-            ],
             "properties": {
-                "email": {
+                "error": {
                     "type": "string"
                 },
-                "name": {
+                "id": {
                     "type": "string"
+                },
+                "rejections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.RuleRejection"
+                    }
                 }
             }
         },
-        "github_com_mthpedrosa_financial-exchange-challenge_internal_balance_domain_dto.BalanceListDTO": {
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.BigFloat": {
+            "type": "object"
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.CancelAllRequest": {
             "type": "object",
             "properties": {
                 "account_id": {
                     "type": "string"
                 },
-                "amount": {
-                    "$ref": "#/definitions/big.Float"
-                },
-                "asset": {
+                "instrument_id": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
+                "side": {
+                    "type": "string",
+                    "enum": [
+                        "BUY",
+                        "SELL"
+                    ]
                 }
             }
         },
-        "github_com_mthpedrosa_financial-exchange-challenge_internal_balance_domain_dto.BigFloat": {
-            "type": "object"
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.CancelAllResponse": {
+            "type": "object",
+            "properties": {
+                "cancelled_order_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
         },
-        "github_com_mthpedrosa_financial-exchange-challenge_internal_balance_domain_dto.CreateBalanceRequest": {
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.CreateOrderListRequest": {
             "type": "object",
             "properties": {
-                "account_id": {
-                    "type": "string"
-                },
-                "amount": {
-                    "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_balance_domain_dto.BigFloat"
-                },
-                "asset": {
-                    "type": "string"
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.CreateOrderRequest"
+                    }
                 }
             }
         },
-        "github_com_mthpedrosa_financial-exchange-challenge_internal_balance_domain_dto.UpdateBalanceRequest": {
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.CreateOrderListResponse": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "$ref": "#/definitions/big.Float"
+                "id": {
+                    "type": "string"
+                },
+                "order_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.CreateInstrumentRequest": {
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.CreateOrderRequest": {
             "type": "object",
             "required": [
-                "base_asset",
-                "quote_asset"
+                "account_id",
+                "instrument_id",
+                "type"
             ],
             "properties": {
-                "base_asset": {
+                "account_id": {
                     "type": "string"
                 },
-                "quote_asset": {
+                "client_order_id": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "display_quantity": {
+                    "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.BigFloat"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "instrument_id": {
                     "type": "string"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "LIMIT",
+                        "MARKET",
+                        "STOP_MARKET",
+                        "STOP_LIMIT",
+                        "TRAILING_STOP_MARKET"
+                    ]
+                },
+                "post_only": {
+                    "type": "boolean"
+                },
+                "price": {
+                    "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.BigFloat"
+                },
+                "quantity": {
+                    "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.BigFloat"
+                },
+                "quote_quantity": {
+                    "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.BigFloat"
+                },
+                "reprice_on_cross": {
+                    "type": "boolean"
+                },
+                "self_trade_prevention": {
+                    "type": "string",
+                    "enum": [
+                        "NONE",
+                        "CANCEL_NEWEST",
+                        "CANCEL_OLDEST",
+                        "CANCEL_BOTH",
+                        "DECREMENT_AND_CANCEL"
+                    ]
+                },
+                "stop_price": {
+                    "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.BigFloat"
+                },
+                "time_in_force": {
+                    "type": "string",
+                    "enum": [
+                        "GTC",
+                        "IOC",
+                        "FOK",
+                        "GTD"
+                    ]
+                },
+                "trail_amount": {
+                    "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.BigFloat"
+                },
+                "trail_percent": {
+                    "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.BigFloat"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "BUY",
+                        "SELL"
+                    ]
                 }
             }
         },
-        "github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.CreateInstrumentResponse": {
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.CreateOrderResponse": {
             "type": "object",
             "properties": {
                 "id": {
@@ -1097,19 +2663,82 @@ const docTemplate = `{
                 }
             }
         },
-        "github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.InstrumentDTO": {
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.OrderDTO": {
             "type": "object",
             "properties": {
-                "base_asset": {
+                "account_id": {
+                    "type": "string"
+                },
+                "client_order_id": {
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
+                "current_trigger_price": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "display_quantity": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
-                "quote_asset": {
+                "instrument_id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "order_list_id": {
+                    "type": "string"
+                },
+                "post_only": {
+                    "type": "boolean"
+                },
+                "price": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "quantity": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "quote_quantity": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "remaining_quantity": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "remaining_quote_quantity": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "reprice_on_cross": {
+                    "type": "boolean"
+                },
+                "self_trade_prevention": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "stop_price": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "time_in_force": {
+                    "type": "string"
+                },
+                "trail_amount": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "trail_percent": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "triggered_at": {
+                    "type": "string"
+                },
+                "type": {
                     "type": "string"
                 },
                 "updated_at": {
@@ -1117,85 +2746,112 @@ const docTemplate = `{
                 }
             }
         },
-        "github_com_mthpedrosa_financial-exchange-challenge_internal_instrument_domain_dto.InstrumentListDTO": {
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.OrderListDTO": {
             "type": "object",
             "properties": {
-                "base_asset": {
+                "account_id": {
+                    "type": "string"
+                },
+                "contingency_type": {
+                    "type": "string"
+                },
+                "created_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "quote_asset": {
+                "instrument_id": {
+                    "type": "string"
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.OrderDTO"
+                    }
+                },
+                "reserved": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.BigFloat": {
-            "type": "object"
-        },
-        "github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.CreateOrderRequest": {
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.RejectedOrderResponse": {
             "type": "object",
-            "required": [
-                "account_id",
-                "instrument_id",
-                "price",
-                "quantity",
-                "type"
-            ],
             "properties": {
-                "account_id": {
+                "message": {
                     "type": "string"
                 },
-                "instrument_id": {
+                "rejections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.RuleRejection"
+                    }
+                }
+            }
+        },
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.RuleRejection": {
+            "type": "object",
+            "properties": {
+                "code": {
                     "type": "string"
                 },
-                "price": {
-                    "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.BigFloat"
-                },
-                "quantity": {
-                    "$ref": "#/definitions/github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.BigFloat"
+                "field": {
+                    "type": "string"
                 },
-                "type": {
-                    "type": "string",
-                    "enum": [
-                        "BUY",
-                        "SELL"
-                    ]
+                "message": {
+                    "type": "string"
                 }
             }
         },
-        "github_com_mthpedrosa_financial-exchange-challenge_internal_order_domain_dto.OrderDTO": {
+        "github_com_mthpedrosa_financial-exchange-challenge_internal_trade_domain_dto.TradeDTO": {
             "type": "object",
             "properties": {
-                "account_id": {
+                "aggressor_side": {
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
+                "fee": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "fee_asset": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
                 "instrument_id": {
                     "type": "string"
                 },
-                "price": {
-                    "$ref": "#/definitions/big.Float"
+                "maker_account_id": {
+                    "type": "string"
                 },
-                "quantity": {
+                "maker_fee": {
                     "$ref": "#/definitions/big.Float"
                 },
-                "remaining_quantity": {
-                    "$ref": "#/definitions/big.Float"
+                "maker_fee_asset": {
+                    "type": "string"
                 },
-                "status": {
+                "maker_order_id": {
                     "type": "string"
                 },
-                "type": {
+                "price": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "quantity": {
+                    "$ref": "#/definitions/big.Float"
+                },
+                "taker_account_id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "taker_order_id": {
                     "type": "string"
                 }
             }
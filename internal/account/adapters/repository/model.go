@@ -7,29 +7,32 @@ import (
 )
 
 type AccountModel struct {
-	ID        string    `db:"id"`
-	Name      string    `db:"name"`
-	Email     string    `db:"email"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID                  string    `db:"id"`
+	Name                string    `db:"name"`
+	Email               string    `db:"email"`
+	SelfTradePrevention string    `db:"self_trade_prevention"`
+	CreatedAt           time.Time `db:"created_at"`
+	UpdatedAt           time.Time `db:"updated_at"`
 }
 
 func ToModel(account entity.Account) *AccountModel {
 	return &AccountModel{
-		ID:        account.ID,
-		Name:      account.Name,
-		Email:     account.Email,
-		CreatedAt: account.CreatedAt,
-		UpdatedAt: account.UpdatedAt,
+		ID:                  account.ID,
+		Name:                account.Name,
+		Email:               account.Email,
+		SelfTradePrevention: account.SelfTradePrevention,
+		CreatedAt:           account.CreatedAt,
+		UpdatedAt:           account.UpdatedAt,
 	}
 }
 
 func ToEntity(model AccountModel) entity.Account {
 	return entity.Account{
-		ID:        model.ID,
-		Name:      model.Name,
-		Email:     model.Email,
-		CreatedAt: model.CreatedAt,
-		UpdatedAt: model.UpdatedAt,
+		ID:                  model.ID,
+		Name:                model.Name,
+		Email:               model.Email,
+		SelfTradePrevention: model.SelfTradePrevention,
+		CreatedAt:           model.CreatedAt,
+		UpdatedAt:           model.UpdatedAt,
 	}
 }
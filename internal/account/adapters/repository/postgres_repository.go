@@ -26,10 +26,10 @@ func NewAccountRepository(db *pgxpool.Pool) port.AccountRepository {
 func (r *account) Create(ctx context.Context, account entity.Account) (string, error) {
 	model := ToModel(account)
 
-	query := `INSERT INTO accounts (name, email, created_at, updated_at) VALUES ($1, $2, NOW(), NOW()) RETURNING id`
+	query := `INSERT INTO accounts (name, email, self_trade_prevention, created_at, updated_at) VALUES ($1, $2, $3, NOW(), NOW()) RETURNING id`
 	var id string
 
-	err := r.db.QueryRow(ctx, query, model.Name, model.Email).Scan(&id)
+	err := r.db.QueryRow(ctx, query, model.Name, model.Email, model.SelfTradePrevention).Scan(&id)
 	if err != nil {
 		return "", err
 	}
@@ -39,8 +39,8 @@ func (r *account) Create(ctx context.Context, account entity.Account) (string, e
 func (r *account) Update(ctx context.Context, account entity.Account) error {
 	model := ToModel(account)
 
-	query := `UPDATE accounts SET name=$1, email=$2, updated_at=NOW() WHERE id=$3`
-	result, err := r.db.Exec(ctx, query, model.Name, model.Email, model.ID)
+	query := `UPDATE accounts SET name=$1, email=$2, self_trade_prevention=$3, updated_at=NOW() WHERE id=$4`
+	result, err := r.db.Exec(ctx, query, model.Name, model.Email, model.SelfTradePrevention, model.ID)
 	if err != nil {
 		return err
 	}
@@ -54,13 +54,14 @@ func (r *account) Update(ctx context.Context, account entity.Account) error {
 }
 
 func (r *account) FindByID(ctx context.Context, id string) (entity.Account, error) {
-	query := `SELECT id, name, email, created_at, updated_at FROM accounts WHERE id = $1`
+	query := `SELECT id, name, email, self_trade_prevention, created_at, updated_at FROM accounts WHERE id = $1`
 
 	var acc entity.Account
 	err := r.db.QueryRow(ctx, query, id).Scan(
 		&acc.ID,
 		&acc.Name,
 		&acc.Email,
+		&acc.SelfTradePrevention,
 		&acc.CreatedAt,
 		&acc.UpdatedAt,
 	)
@@ -75,7 +76,7 @@ func (r *account) FindByID(ctx context.Context, id string) (entity.Account, erro
 }
 
 func (r *account) GetAccounts(ctx context.Context, filters entity.AccountFilter) ([]entity.Account, error) {
-	query := "SELECT id, name, email, created_at, updated_at FROM accounts"
+	query := "SELECT id, name, email, self_trade_prevention, created_at, updated_at FROM accounts"
 	var args []interface{}
 	var conditions []string
 	argID := 1
@@ -106,7 +107,7 @@ func (r *account) GetAccounts(ctx context.Context, filters entity.AccountFilter)
 	var accounts []entity.Account
 	for rows.Next() {
 		var model AccountModel
-		if err := rows.Scan(&model.ID, &model.Name, &model.Email, &model.CreatedAt, &model.UpdatedAt); err != nil {
+		if err := rows.Scan(&model.ID, &model.Name, &model.Email, &model.SelfTradePrevention, &model.CreatedAt, &model.UpdatedAt); err != nil {
 			return nil, err
 		}
 		accounts = append(accounts, ToEntity(model))
@@ -66,6 +66,10 @@ func (a *account) Update(ctx context.Context, id string, request dto.UpdateAccou
 	}
 
 	entityAccount.ID = id
+	if entityAccount.SelfTradePrevention == "" {
+		// keep the current default when the request does not change it
+		entityAccount.SelfTradePrevention = account.SelfTradePrevention
+	}
 
 	err = a.accountPort.Update(ctx, *entityAccount)
 	if err != nil {
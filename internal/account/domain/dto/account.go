@@ -7,11 +7,12 @@ import (
 )
 
 type AccountDTO struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                  string    `json:"id"`
+	Name                string    `json:"name"`
+	Email               string    `json:"email"`
+	SelfTradePrevention string    `json:"self_trade_prevention"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
 }
 
 type AccountListDTO struct {
@@ -19,14 +20,18 @@ type AccountListDTO struct {
 	Name string `json:"name"`
 }
 
+// CreateAccountRequest creates an account. SelfTradePrevention is the policy
+// its orders use when they do not choose one; it defaults to NONE.
 type CreateAccountRequest struct {
-	Name  string `json:"name" validate:"required"`
-	Email string `json:"email" validate:"required,email"`
+	Name                string `json:"name" validate:"required"`
+	Email               string `json:"email" validate:"required,email"`
+	SelfTradePrevention string `json:"self_trade_prevention,omitempty" validate:"omitempty,oneof=NONE CANCEL_NEWEST CANCEL_OLDEST CANCEL_BOTH DECREMENT_AND_CANCEL"`
 }
 
 type UpdateAccountRequest struct {
-	Name  string `json:"name" validate:"required"`
-	Email string `json:"email" validate:"required,email"`
+	Name                string `json:"name" validate:"required"`
+	Email               string `json:"email" validate:"required,email"`
+	SelfTradePrevention string `json:"self_trade_prevention,omitempty" validate:"omitempty,oneof=NONE CANCEL_NEWEST CANCEL_OLDEST CANCEL_BOTH DECREMENT_AND_CANCEL"`
 }
 
 type AccountFilter struct {
@@ -6,12 +6,18 @@ import (
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/account/domain/dto"
 )
 
+// SelfTradePreventionNone is the self-trade prevention default of accounts
+// that do not choose one.
+const SelfTradePreventionNone = "NONE"
+
+// Account holds the default SelfTradePrevention policy of its orders.
 type Account struct {
-	ID        string
-	Name      string
-	Email     string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID                  string
+	Name                string
+	Email               string
+	SelfTradePrevention string
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
 }
 
 type AccountFilter struct {
@@ -21,11 +27,12 @@ type AccountFilter struct {
 
 func (a Account) ToDTO() dto.AccountDTO {
 	return dto.AccountDTO{
-		ID:        a.ID,
-		Name:      a.Name,
-		Email:     a.Email,
-		CreatedAt: a.CreatedAt,
-		UpdatedAt: a.UpdatedAt,
+		ID:                  a.ID,
+		Name:                a.Name,
+		Email:               a.Email,
+		SelfTradePrevention: a.SelfTradePrevention,
+		CreatedAt:           a.CreatedAt,
+		UpdatedAt:           a.UpdatedAt,
 	}
 }
 
@@ -53,8 +60,9 @@ func ToEntity(request dto.CreateAccountRequest) (*Account, error) {
 	}
 
 	return &Account{
-		Name:  request.Name,
-		Email: request.Email,
+		Name:                request.Name,
+		Email:               request.Email,
+		SelfTradePrevention: selfTradePrevention(request.SelfTradePrevention),
 	}, nil
 }
 
@@ -64,11 +72,19 @@ func ToEntityUpdate(request dto.UpdateAccountRequest) (*Account, error) {
 	}
 
 	return &Account{
-		Name:  request.Name,
-		Email: request.Email,
+		Name:                request.Name,
+		Email:               request.Email,
+		SelfTradePrevention: request.SelfTradePrevention,
 	}, nil
 }
 
+func selfTradePrevention(mode string) string {
+	if mode == "" {
+		return SelfTradePreventionNone
+	}
+	return mode
+}
+
 func (a *Account) IsExisting() bool {
 	return a.ID != ""
 }
@@ -30,6 +30,25 @@ func TestToEntity_Invalid(t *testing.T) {
 	assert.Empty(t, acc.Email)
 }
 
+func TestToEntity_SelfTradePrevention(t *testing.T) {
+	req := dto.CreateAccountRequest{Name: "Alice", Email: "alice@email.com"}
+	acc, err := entity.ToEntity(req)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.SelfTradePreventionNone, acc.SelfTradePrevention)
+
+	req.SelfTradePrevention = "CANCEL_OLDEST"
+	acc, err = entity.ToEntity(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "CANCEL_OLDEST", acc.SelfTradePrevention)
+	assert.Equal(t, "CANCEL_OLDEST", acc.ToDTO().SelfTradePrevention)
+}
+
+func TestToEntity_InvalidSelfTradePrevention(t *testing.T) {
+	req := dto.CreateAccountRequest{Name: "Alice", Email: "alice@email.com", SelfTradePrevention: "ALLOW"}
+	_, err := entity.ToEntity(req)
+	assert.Error(t, err)
+}
+
 func TestToEntityUpdate_Valid(t *testing.T) {
 	req := dto.UpdateAccountRequest{
 		Name:  "Bob",
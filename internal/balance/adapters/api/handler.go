@@ -107,6 +107,8 @@ func (h *balance) Update(ctx echo.Context) error {
 		switch {
 		case errors.Is(err, ierr.ErrNotFound):
 			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		case errors.Is(err, ierr.ErrInvalidInput):
+			return echo.NewHTTPError(http.StatusBadRequest, "amount cannot be lower than the locked balance")
 		default:
 			slog.Error("error updating balance", "error", err)
 			return echo.NewHTTPError(http.StatusInternalServerError, "an unexpected error occurred")
@@ -172,6 +174,7 @@ func (h *balance) FindByAccountAndAsset(ctx echo.Context) error {
 
 // GetAllByAccountID godoc
 // @Summary      Lista todos os balances de uma conta
+// @Description  Retorna o total, o disponível e o valor bloqueado por ordens abertas de cada asset
 // @Tags         balances
 // @Produce      json
 // @Param        account_id   path   string  true  "Account ID"
@@ -11,6 +11,7 @@ type BalanceModel struct {
 	ID        string
 	AccountID string
 	Amount    *big.Float
+	Locked    *big.Float
 	Asset     string
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -21,6 +22,7 @@ func ToModel(b entity.Balance) *BalanceModel {
 		ID:        b.ID,
 		AccountID: b.AccountID,
 		Amount:    b.Amount,
+		Locked:    b.Locked,
 		Asset:     b.Asset,
 	}
 }
@@ -31,6 +33,7 @@ func (b *BalanceModel) ToEntity() entity.Balance {
 		AccountID: b.AccountID,
 		Asset:     b.Asset,
 		Amount:    b.Amount,
+		Locked:    b.Locked,
 		CreatedAt: b.CreatedAt,
 		UpdatedAt: b.UpdatedAt,
 	}
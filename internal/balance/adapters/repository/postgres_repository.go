@@ -10,6 +10,8 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/balance/domain/entity"
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/balance/domain/port"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/db"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/decimal"
 	"github.com/mthpedrosa/financial-exchange-challenge/pkg/ierr"
 )
 
@@ -26,7 +28,7 @@ func (r *balanceRepository) Create(ctx context.Context, balance entity.Balance)
 	// Check for duplicate
 	queryCheck := `SELECT id FROM balances WHERE account_id = $1 AND asset = $2`
 	var existingID string
-	err := r.db.QueryRow(ctx, queryCheck, balance.AccountID, balance.Asset).Scan(&existingID)
+	err := db.Conn(ctx, r.db).QueryRow(ctx, queryCheck, balance.AccountID, balance.Asset).Scan(&existingID)
 	if err == nil {
 		return "", ierr.ErrConflict
 	}
@@ -37,7 +39,7 @@ func (r *balanceRepository) Create(ctx context.Context, balance entity.Balance)
 	m := ToModel(balance)
 	query := `INSERT INTO balances (account_id, asset, amount, created_at, updated_at) VALUES ($1, $2, $3, NOW(), NOW()) RETURNING id`
 	var id string
-	err = r.db.QueryRow(ctx, query, m.AccountID, m.Asset, m.Amount).Scan(&id)
+	err = db.Conn(ctx, r.db).QueryRow(ctx, query, m.AccountID, m.Asset, m.Amount).Scan(&id)
 	if err != nil {
 		return "", err
 	}
@@ -46,15 +48,16 @@ func (r *balanceRepository) Create(ctx context.Context, balance entity.Balance)
 
 // FindByID returns a balance by its ID.
 func (r *balanceRepository) FindByID(ctx context.Context, id string) (entity.Balance, error) {
-	query := `SELECT id, account_id, asset, amount, created_at, updated_at FROM balances WHERE id = $1`
+	query := `SELECT id, account_id, asset, amount, locked, created_at, updated_at FROM balances WHERE id = $1`
 	var m BalanceModel
 
-	var amountStr string
-	err := r.db.QueryRow(ctx, query, id).Scan(
+	var amountStr, lockedStr string
+	err := db.Conn(ctx, r.db).QueryRow(ctx, query, id).Scan(
 		&m.ID,
 		&m.AccountID,
 		&m.Asset,
 		&amountStr,
+		&lockedStr,
 		&m.CreatedAt,
 		&m.UpdatedAt,
 	)
@@ -64,21 +67,22 @@ func (r *balanceRepository) FindByID(ctx context.Context, id string) (entity.Bal
 		}
 		return entity.Balance{}, err
 	}
-	m.Amount, _ = new(big.Float).SetString(amountStr)
+	m.Amount, _ = decimal.Parse(amountStr)
+	m.Locked, _ = decimal.Parse(lockedStr)
 	return m.ToEntity(), nil
 }
 
 // FindByAccountAndAsset returns a balance for a given account and asset.
 func (r *balanceRepository) FindByAccountAndAsset(ctx context.Context, accountID, asset string) (entity.Balance, error) {
-	fmt.Println("Finding balance for accountID:", accountID, "and asset:", asset)
-	query := `SELECT id, account_id, asset, amount, created_at, updated_at FROM balances WHERE account_id = $1 AND asset = $2`
+	query := `SELECT id, account_id, asset, amount, locked, created_at, updated_at FROM balances WHERE account_id = $1 AND asset = $2`
 	var m BalanceModel
-	var amountStr string
-	err := r.db.QueryRow(ctx, query, accountID, asset).Scan(
+	var amountStr, lockedStr string
+	err := db.Conn(ctx, r.db).QueryRow(ctx, query, accountID, asset).Scan(
 		&m.ID,
 		&m.AccountID,
 		&m.Asset,
 		&amountStr,
+		&lockedStr,
 		&m.CreatedAt,
 		&m.UpdatedAt,
 	)
@@ -88,10 +92,8 @@ func (r *balanceRepository) FindByAccountAndAsset(ctx context.Context, accountID
 		}
 		return entity.Balance{}, err
 	}
-	m.Amount, _ = new(big.Float).SetString(amountStr)
-	fmt.Println("Amount:", m.Amount.String())
-	fmt.Println("Convert:", m.ToEntity())
-
+	m.Amount, _ = decimal.Parse(amountStr)
+	m.Locked, _ = decimal.Parse(lockedStr)
 	return m.ToEntity(), nil
 }
 
@@ -99,7 +101,7 @@ func (r *balanceRepository) FindByAccountAndAsset(ctx context.Context, accountID
 func (r *balanceRepository) Update(ctx context.Context, balance entity.Balance) error {
 	m := ToModel(balance)
 	query := `UPDATE balances SET amount = $1, updated_at = NOW() WHERE id = $2`
-	result, err := r.db.Exec(ctx, query, m.Amount, m.ID)
+	result, err := db.Conn(ctx, r.db).Exec(ctx, query, m.Amount, m.ID)
 	if err != nil {
 		return err
 	}
@@ -112,7 +114,7 @@ func (r *balanceRepository) Update(ctx context.Context, balance entity.Balance)
 // DeleteByID removes a balance by its ID.
 func (r *balanceRepository) DeleteByID(ctx context.Context, id string) error {
 	query := `DELETE FROM balances WHERE id = $1`
-	result, err := r.db.Exec(ctx, query, id)
+	result, err := db.Conn(ctx, r.db).Exec(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -124,8 +126,8 @@ func (r *balanceRepository) DeleteByID(ctx context.Context, id string) error {
 
 // GetAllByAccountID returns all balances for a given account ID.
 func (r *balanceRepository) GetAllByAccountID(ctx context.Context, accountID string) ([]entity.Balance, error) {
-	query := `SELECT id, account_id, asset, amount, created_at, updated_at FROM balances WHERE account_id = $1`
-	rows, err := r.db.Query(ctx, query, accountID)
+	query := `SELECT id, account_id, asset, amount, locked, created_at, updated_at FROM balances WHERE account_id = $1`
+	rows, err := db.Conn(ctx, r.db).Query(ctx, query, accountID)
 	if err != nil {
 		return nil, err
 	}
@@ -134,11 +136,12 @@ func (r *balanceRepository) GetAllByAccountID(ctx context.Context, accountID str
 	var balances []entity.Balance
 	for rows.Next() {
 		var m BalanceModel
-		var amountStr string
-		if err := rows.Scan(&m.ID, &m.AccountID, &m.Asset, &amountStr, &m.CreatedAt, &m.UpdatedAt); err != nil {
+		var amountStr, lockedStr string
+		if err := rows.Scan(&m.ID, &m.AccountID, &m.Asset, &amountStr, &lockedStr, &m.CreatedAt, &m.UpdatedAt); err != nil {
 			return nil, err
 		}
-		m.Amount, _ = new(big.Float).SetString(amountStr)
+		m.Amount, _ = decimal.Parse(amountStr)
+		m.Locked, _ = decimal.Parse(lockedStr)
 		balances = append(balances, m.ToEntity())
 	}
 	if err := rows.Err(); err != nil {
@@ -146,3 +149,120 @@ func (r *balanceRepository) GetAllByAccountID(ctx context.Context, accountID str
 	}
 	return balances, nil
 }
+
+// Reserve moves amount from available to locked. It fails with
+// ierr.ErrInsufficientBalance when the available balance does not cover it
+// and with ierr.ErrInvalidInput when amount is not positive.
+func (r *balanceRepository) Reserve(ctx context.Context, accountID, asset string, amount *big.Float) error {
+	if amount == nil || amount.Sign() <= 0 {
+		return fmt.Errorf("reserve amount must be positive: %w", ierr.ErrInvalidInput)
+	}
+	query := `UPDATE balances SET locked = locked + $1, updated_at = NOW()
+        WHERE account_id = $2 AND asset = $3 AND amount - locked >= $1`
+	result, err := db.Conn(ctx, r.db).Exec(ctx, query, amount.Text('f', 18), accountID, asset)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		if _, err := r.FindByAccountAndAsset(ctx, accountID, asset); err != nil {
+			return err
+		}
+		return ierr.ErrInsufficientBalance
+	}
+	return nil
+}
+
+// Release moves amount from locked back to available. Releasing more than is
+// locked means a reservation was miscalculated; it fails instead of hiding
+// the difference, as does an amount that is not positive.
+func (r *balanceRepository) Release(ctx context.Context, accountID, asset string, amount *big.Float) error {
+	if amount == nil || amount.Sign() <= 0 {
+		return fmt.Errorf("release amount must be positive: %w", ierr.ErrInvalidInput)
+	}
+	query := `UPDATE balances SET locked = locked - $1, updated_at = NOW()
+        WHERE account_id = $2 AND asset = $3 AND locked >= $1`
+	result, err := db.Conn(ctx, r.db).Exec(ctx, query, amount.Text('f', 18), accountID, asset)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		balance, err := r.FindByAccountAndAsset(ctx, accountID, asset)
+		if err != nil {
+			return fmt.Errorf("no balance found for account %s and asset %s: %w", accountID, asset, err)
+		}
+		return fmt.Errorf("cannot release %s %s of account %s: only %s is locked",
+			amount.Text('f', 18), asset, accountID, balance.Locked.Text('f', 18))
+	}
+	return nil
+}
+
+// FindForUpdate locks the balance row of an account and asset for the rest of
+// the current transaction. It fails with ierr.ErrNotFound when there is none.
+func (r *balanceRepository) FindForUpdate(ctx context.Context, accountID, asset string) (entity.Balance, error) {
+	query := `SELECT id, account_id, asset, amount, locked, created_at, updated_at FROM balances
+        WHERE account_id = $1 AND asset = $2 FOR UPDATE`
+	var m BalanceModel
+	var amountStr, lockedStr string
+	err := db.Conn(ctx, r.db).QueryRow(ctx, query, accountID, asset).Scan(
+		&m.ID,
+		&m.AccountID,
+		&m.Asset,
+		&amountStr,
+		&lockedStr,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Balance{}, ierr.ErrNotFound
+		}
+		return entity.Balance{}, err
+	}
+	m.Amount, _ = decimal.Parse(amountStr)
+	m.Locked, _ = decimal.Parse(lockedStr)
+	return m.ToEntity(), nil
+}
+
+// FindOrCreateForUpdate locks the balance row of an account and asset for the
+// rest of the current transaction, creating an empty one if it does not exist.
+func (r *balanceRepository) FindOrCreateForUpdate(ctx context.Context, accountID, asset string) (entity.Balance, error) {
+	insert := `INSERT INTO balances (account_id, asset, amount, locked, created_at, updated_at)
+        VALUES ($1, $2, 0, 0, NOW(), NOW()) ON CONFLICT (account_id, asset) DO NOTHING`
+	if _, err := db.Conn(ctx, r.db).Exec(ctx, insert, accountID, asset); err != nil {
+		return entity.Balance{}, err
+	}
+
+	query := `SELECT id, account_id, asset, amount, locked, created_at, updated_at FROM balances
+        WHERE account_id = $1 AND asset = $2 FOR UPDATE`
+	var m BalanceModel
+	var amountStr, lockedStr string
+	err := db.Conn(ctx, r.db).QueryRow(ctx, query, accountID, asset).Scan(
+		&m.ID,
+		&m.AccountID,
+		&m.Asset,
+		&amountStr,
+		&lockedStr,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	)
+	if err != nil {
+		return entity.Balance{}, err
+	}
+	m.Amount, _ = decimal.Parse(amountStr)
+	m.Locked, _ = decimal.Parse(lockedStr)
+	return m.ToEntity(), nil
+}
+
+// Adjust applies relative changes to the amount and locked columns of a
+// balance, so concurrent writers never overwrite each other.
+func (r *balanceRepository) Adjust(ctx context.Context, id string, amountDelta, lockedDelta *big.Float) error {
+	query := `UPDATE balances SET amount = amount + $1, locked = locked + $2, updated_at = NOW() WHERE id = $3`
+	result, err := db.Conn(ctx, r.db).Exec(ctx, query, amountDelta.Text('f', 18), lockedDelta.Text('f', 18), id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("no balance found with id: %s", id)
+	}
+	return nil
+}
@@ -92,9 +92,15 @@ func (b *balance) Update(ctx context.Context, id string, request dto.UpdateBalan
 		return entity.Balance{}, ierr.ErrNotFound
 	}
 
+	// the total can never drop below what open orders have reserved
+	if existing.Locked != nil && balanceEntity.Amount.Cmp(existing.Locked) < 0 {
+		return entity.Balance{}, ierr.ErrInvalidInput
+	}
+
 	balanceEntity.ID = id
 	balanceEntity.AccountID = existing.AccountID // preserve accountID
 	balanceEntity.Asset = existing.Asset         // preserve asset
+	balanceEntity.Locked = existing.Locked       // preserve reservations
 
 	err = b.balancePort.Update(ctx, *balanceEntity)
 	if err != nil {
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/balance/domain/entity"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/balance/domain/port"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/db"
+	tradeEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/trade/domain/entity"
+	tradePort "github.com/mthpedrosa/financial-exchange-challenge/internal/trade/domain/port"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/decimal"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/ierr"
+)
+
+type Settlement interface {
+	Settle(ctx context.Context, settlement entity.Settlement, trade tradeEntity.Trade) (string, error)
+}
+
+type settlement struct {
+	balancePort  port.BalanceRepository
+	tradePort    tradePort.TradeRepository
+	txManager    db.TxManager
+	feeAccountID string
+}
+
+// NewSettlementApp creates the settlement service. Fees charged on fills are
+// credited to feeAccountID, the exchange's house account.
+func NewSettlementApp(balancePort port.BalanceRepository, tradePort tradePort.TradeRepository, txManager db.TxManager, feeAccountID string) Settlement {
+	return &settlement{
+		balancePort:  balancePort,
+		tradePort:    tradePort,
+		txManager:    txManager,
+		feeAccountID: feeAccountID,
+	}
+}
+
+// Settle moves the buyer's quote and the seller's base asset, credits the fees
+// to the house account and writes the trade row in one transaction. Every
+// balance row involved is locked before it is changed, and missing rows are
+// created empty. Unlocking more than a balance has locked fails, as Release
+// does. It returns the trade ID.
+func (s *settlement) Settle(ctx context.Context, settlement entity.Settlement, trade tradeEntity.Trade) (string, error) {
+	settlement.FeeAccountID = s.feeAccountID
+
+	var tradeID string
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		for _, movement := range settlement.Movements() {
+			balance, err := s.balancePort.FindOrCreateForUpdate(ctx, movement.AccountID, movement.Asset)
+			if err != nil {
+				return err
+			}
+
+			// compared at the stored scale, as the database will hold them
+			if decimal.Round(new(big.Float).Add(balance.Amount, movement.AmountDelta), decimal.AmountScale).Sign() < 0 {
+				return fmt.Errorf("settling %s for account %s: %w", movement.Asset, movement.AccountID, ierr.ErrInsufficientBalance)
+			}
+
+			if decimal.Round(new(big.Float).Add(balance.Locked, movement.LockedDelta), decimal.AmountScale).Sign() < 0 {
+				return fmt.Errorf("cannot unlock %s %s of account %s: only %s is locked",
+					new(big.Float).Neg(movement.LockedDelta).Text('f', 18), movement.Asset, movement.AccountID, balance.Locked.Text('f', 18))
+			}
+
+			if err := s.balancePort.Adjust(ctx, balance.ID, movement.AmountDelta, movement.LockedDelta); err != nil {
+				return err
+			}
+		}
+
+		id, err := s.tradePort.Create(ctx, trade)
+		if err != nil {
+			return err
+		}
+		tradeID = id
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return tradeID, nil
+}
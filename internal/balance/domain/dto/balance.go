@@ -41,7 +41,9 @@ type BalanceListDTO struct {
 	ID        string    `json:"id"`
 	AccountID string    `json:"account_id"`
 	Asset     string    `json:"asset"`
-	Amount    big.Float `json:"amount"`
+	Total     big.Float `json:"total"`
+	Available big.Float `json:"available"`
+	Locked    big.Float `json:"locked"`
 }
 
 func (r *CreateBalanceRequest) Validate() error {
@@ -12,18 +12,34 @@ type Balance struct {
 	AccountID string
 	Asset     string
 	Amount    *big.Float
+	Locked    *big.Float
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// Available returns the part of the balance that is not reserved by open
+// orders.
+func (b *Balance) Available() *big.Float {
+	if b.Locked == nil {
+		return new(big.Float).Set(b.Amount)
+	}
+	return new(big.Float).Sub(b.Amount, b.Locked)
+}
+
 func ToListDTO(balances []Balance) []dto.BalanceListDTO {
 	dtos := make([]dto.BalanceListDTO, len(balances))
 	for i, a := range balances {
+		locked := new(big.Float)
+		if a.Locked != nil {
+			locked = a.Locked
+		}
 		dtos[i] = dto.BalanceListDTO{
 			ID:        a.ID,
 			AccountID: a.AccountID,
 			Asset:     a.Asset,
-			Amount:    *a.Amount,
+			Total:     *a.Amount,
+			Available: *a.Available(),
+			Locked:    *locked,
 		}
 	}
 	return dtos
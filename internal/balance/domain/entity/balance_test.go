@@ -73,3 +73,24 @@ func TestToEntityUpdate_Invalid(t *testing.T) {
 	assert.Error(t, err)
 	assert.NotNil(t, b)
 }
+
+func TestBalance_Available(t *testing.T) {
+	b := entity.Balance{
+		Amount: big.NewFloat(100),
+		Locked: big.NewFloat(30),
+	}
+	assert.Equal(t, "70.00", b.Available().Text('f', 2))
+}
+
+func TestToListDTO_TotalAvailableLocked(t *testing.T) {
+	balances := []entity.Balance{
+		{ID: "id-1", Asset: "BTC", Amount: big.NewFloat(10), Locked: big.NewFloat(2.5)},
+		{ID: "id-2", Asset: "ETH", Amount: big.NewFloat(5)},
+	}
+	dtos := entity.ToListDTO(balances)
+	assert.Equal(t, "10.0", dtos[0].Total.Text('f', 1))
+	assert.Equal(t, "7.5", dtos[0].Available.Text('f', 1))
+	assert.Equal(t, "2.5", dtos[0].Locked.Text('f', 1))
+	assert.Equal(t, "5.0", dtos[1].Available.Text('f', 1))
+	assert.Equal(t, "0.0", dtos[1].Locked.Text('f', 1))
+}
@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"math/big"
+	"sort"
+)
+
+// Settlement describes the balance movements of a single fill: the buyer pays
+// Notional of the quote asset and receives Quantity of the base asset, and the
+// seller does the opposite. BuyerLocked and SellerLocked are the parts of each
+// order's reservation that back this fill and must be unlocked. BuyerFee is
+// taken from the base asset the buyer receives and SellerFee from the quote
+// asset the seller receives; both are credited to FeeAccountID.
+type Settlement struct {
+	BuyerAccountID  string
+	SellerAccountID string
+	FeeAccountID    string
+	BaseAsset       string
+	QuoteAsset      string
+	Quantity        *big.Float
+	Notional        *big.Float
+	BuyerLocked     *big.Float
+	SellerLocked    *big.Float
+	BuyerFee        *big.Float
+	SellerFee       *big.Float
+}
+
+// Movement is a relative change applied to a single balance row.
+type Movement struct {
+	AccountID   string
+	Asset       string
+	AmountDelta *big.Float
+	LockedDelta *big.Float
+}
+
+// Movements returns one movement per balance row touched by the settlement,
+// merged when buyer and seller are the same account and sorted by account and
+// asset so rows are always locked in the same order.
+func (s Settlement) Movements() []Movement {
+	type key struct{ accountID, asset string }
+	merged := make(map[key]*Movement)
+	var keys []key
+
+	add := func(accountID, asset string, amountDelta, lockedDelta *big.Float) {
+		k := key{accountID, asset}
+		m, ok := merged[k]
+		if !ok {
+			m = &Movement{AccountID: accountID, Asset: asset, AmountDelta: new(big.Float), LockedDelta: new(big.Float)}
+			merged[k] = m
+			keys = append(keys, k)
+		}
+		m.AmountDelta.Add(m.AmountDelta, amountDelta)
+		m.LockedDelta.Add(m.LockedDelta, lockedDelta)
+	}
+
+	zero := new(big.Float)
+	buyerFee, sellerFee := orZero(s.BuyerFee), orZero(s.SellerFee)
+	add(s.BuyerAccountID, s.QuoteAsset, new(big.Float).Neg(s.Notional), new(big.Float).Neg(s.BuyerLocked))
+	add(s.BuyerAccountID, s.BaseAsset, new(big.Float).Sub(s.Quantity, buyerFee), zero)
+	add(s.SellerAccountID, s.BaseAsset, new(big.Float).Neg(s.Quantity), new(big.Float).Neg(s.SellerLocked))
+	add(s.SellerAccountID, s.QuoteAsset, new(big.Float).Sub(s.Notional, sellerFee), zero)
+	if buyerFee.Sign() > 0 {
+		add(s.FeeAccountID, s.BaseAsset, buyerFee, zero)
+	}
+	if sellerFee.Sign() > 0 {
+		add(s.FeeAccountID, s.QuoteAsset, sellerFee, zero)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].accountID != keys[j].accountID {
+			return keys[i].accountID < keys[j].accountID
+		}
+		return keys[i].asset < keys[j].asset
+	})
+
+	movements := make([]Movement, len(keys))
+	for i, k := range keys {
+		movements[i] = *merged[k]
+	}
+	return movements
+}
+
+func orZero(amount *big.Float) *big.Float {
+	if amount == nil {
+		return new(big.Float)
+	}
+	return amount
+}
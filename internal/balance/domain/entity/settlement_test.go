@@ -0,0 +1,114 @@
+package entity_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/balance/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettlement_Movements(t *testing.T) {
+	// arrange
+	settlement := entity.Settlement{
+		BuyerAccountID:  "acc-buyer",
+		SellerAccountID: "acc-seller",
+		BaseAsset:       "BTC",
+		QuoteAsset:      "USDT",
+		Quantity:        big.NewFloat(2),
+		Notional:        big.NewFloat(200),
+		BuyerLocked:     big.NewFloat(210),
+		SellerLocked:    big.NewFloat(2),
+	}
+
+	// act
+	movements := settlement.Movements()
+
+	// assert
+	assert.Len(t, movements, 4)
+
+	assert.Equal(t, "acc-buyer", movements[0].AccountID)
+	assert.Equal(t, "BTC", movements[0].Asset)
+	assert.Equal(t, "2", movements[0].AmountDelta.Text('f', 0))
+	assert.Zero(t, movements[0].LockedDelta.Sign())
+
+	assert.Equal(t, "acc-buyer", movements[1].AccountID)
+	assert.Equal(t, "USDT", movements[1].Asset)
+	assert.Equal(t, "-200", movements[1].AmountDelta.Text('f', 0))
+	assert.Equal(t, "-210", movements[1].LockedDelta.Text('f', 0))
+
+	assert.Equal(t, "acc-seller", movements[2].AccountID)
+	assert.Equal(t, "BTC", movements[2].Asset)
+	assert.Equal(t, "-2", movements[2].AmountDelta.Text('f', 0))
+	assert.Equal(t, "-2", movements[2].LockedDelta.Text('f', 0))
+
+	assert.Equal(t, "acc-seller", movements[3].AccountID)
+	assert.Equal(t, "USDT", movements[3].Asset)
+	assert.Equal(t, "200", movements[3].AmountDelta.Text('f', 0))
+	assert.Zero(t, movements[3].LockedDelta.Sign())
+}
+
+func TestSettlement_Movements_SameAccount(t *testing.T) {
+	// arrange
+	settlement := entity.Settlement{
+		BuyerAccountID:  "acc-1",
+		SellerAccountID: "acc-1",
+		BaseAsset:       "BTC",
+		QuoteAsset:      "USDT",
+		Quantity:        big.NewFloat(1),
+		Notional:        big.NewFloat(100),
+		BuyerLocked:     big.NewFloat(100),
+		SellerLocked:    big.NewFloat(1),
+	}
+
+	// act
+	movements := settlement.Movements()
+
+	// assert
+	assert.Len(t, movements, 2)
+	for _, m := range movements {
+		assert.Zero(t, m.AmountDelta.Sign(), "amounts should net out for %s", m.Asset)
+	}
+	assert.Equal(t, "-1", movements[0].LockedDelta.Text('f', 0))
+	assert.Equal(t, "-100", movements[1].LockedDelta.Text('f', 0))
+}
+
+func TestSettlement_Movements_WithFees(t *testing.T) {
+	// arrange
+	settlement := entity.Settlement{
+		BuyerAccountID:  "acc-buyer",
+		SellerAccountID: "acc-seller",
+		FeeAccountID:    "acc-fees",
+		BaseAsset:       "BTC",
+		QuoteAsset:      "USDT",
+		Quantity:        big.NewFloat(2),
+		Notional:        big.NewFloat(200),
+		BuyerLocked:     big.NewFloat(200),
+		SellerLocked:    big.NewFloat(2),
+		BuyerFee:        big.NewFloat(0.5),
+		SellerFee:       big.NewFloat(4),
+	}
+
+	// act
+	movements := settlement.Movements()
+
+	// assert
+	assert.Len(t, movements, 6)
+
+	assert.Equal(t, "acc-buyer", movements[0].AccountID)
+	assert.Equal(t, "BTC", movements[0].Asset)
+	assert.Equal(t, "1.5", movements[0].AmountDelta.Text('f', 1))
+
+	assert.Equal(t, "acc-fees", movements[2].AccountID)
+	assert.Equal(t, "BTC", movements[2].Asset)
+	assert.Equal(t, "0.5", movements[2].AmountDelta.Text('f', 1))
+	assert.Zero(t, movements[2].LockedDelta.Sign())
+
+	assert.Equal(t, "acc-fees", movements[3].AccountID)
+	assert.Equal(t, "USDT", movements[3].Asset)
+	assert.Equal(t, "4", movements[3].AmountDelta.Text('f', 0))
+
+	assert.Equal(t, "acc-seller", movements[5].AccountID)
+	assert.Equal(t, "USDT", movements[5].Asset)
+	assert.Equal(t, "196", movements[5].AmountDelta.Text('f', 0))
+}
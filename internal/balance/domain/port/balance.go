@@ -2,6 +2,7 @@ package port
 
 import (
 	"context"
+	"math/big"
 
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/balance/domain/entity"
 )
@@ -14,4 +15,9 @@ type BalanceRepository interface {
 	Update(ctx context.Context, balance entity.Balance) error
 	DeleteByID(ctx context.Context, id string) error
 	GetAllByAccountID(ctx context.Context, accountID string) ([]entity.Balance, error)
+	Reserve(ctx context.Context, accountID, asset string, amount *big.Float) error
+	Release(ctx context.Context, accountID, asset string, amount *big.Float) error
+	FindForUpdate(ctx context.Context, accountID, asset string) (entity.Balance, error)
+	FindOrCreateForUpdate(ctx context.Context, accountID, asset string) (entity.Balance, error)
+	Adjust(ctx context.Context, id string, amountDelta, lockedDelta *big.Float) error
 }
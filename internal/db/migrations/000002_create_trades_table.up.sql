@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS trades (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    instrument_id UUID NOT NULL REFERENCES instruments(id),
+    maker_order_id UUID NOT NULL REFERENCES orders(id),
+    taker_order_id UUID NOT NULL REFERENCES orders(id),
+    maker_account_id UUID NOT NULL REFERENCES accounts(id),
+    taker_account_id UUID NOT NULL REFERENCES accounts(id),
+    aggressor_side order_type NOT NULL,
+    price NUMERIC(30, 10) NOT NULL,
+    quantity NUMERIC(30, 18) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_trades_instrument_id ON trades(instrument_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_trades_maker_account_id ON trades(maker_account_id);
+CREATE INDEX IF NOT EXISTS idx_trades_taker_account_id ON trades(taker_account_id);
@@ -0,0 +1,3 @@
+ALTER TABLE balances DROP CONSTRAINT IF EXISTS balances_locked_check;
+
+ALTER TABLE balances DROP COLUMN IF EXISTS locked;
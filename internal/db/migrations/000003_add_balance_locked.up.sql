@@ -0,0 +1,3 @@
+ALTER TABLE balances ADD COLUMN IF NOT EXISTS locked NUMERIC(30, 18) NOT NULL DEFAULT 0;
+
+ALTER TABLE balances ADD CONSTRAINT balances_locked_check CHECK (locked >= 0 AND locked <= amount);
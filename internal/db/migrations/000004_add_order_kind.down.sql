@@ -0,0 +1,8 @@
+UPDATE orders SET price = 0 WHERE price IS NULL;
+ALTER TABLE orders ALTER COLUMN price SET NOT NULL;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS remaining_quote_quantity;
+ALTER TABLE orders DROP COLUMN IF EXISTS quote_quantity;
+ALTER TABLE orders DROP COLUMN IF EXISTS kind;
+
+DROP TYPE IF EXISTS order_kind;
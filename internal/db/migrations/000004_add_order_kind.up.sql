@@ -0,0 +1,12 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'order_kind') THEN
+        CREATE TYPE order_kind AS ENUM ('LIMIT', 'MARKET');
+    END IF;
+END$$;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS kind order_kind NOT NULL DEFAULT 'LIMIT';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS quote_quantity NUMERIC(30, 18);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS remaining_quote_quantity NUMERIC(30, 18);
+
+ALTER TABLE orders ALTER COLUMN price DROP NOT NULL;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_orders_expires_at;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS time_in_force;
+
+DROP TYPE IF EXISTS time_in_force;
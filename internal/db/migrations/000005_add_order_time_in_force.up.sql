@@ -0,0 +1,13 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'time_in_force') THEN
+        CREATE TYPE time_in_force AS ENUM ('GTC', 'IOC', 'FOK', 'GTD');
+    END IF;
+END$$;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS time_in_force time_in_force NOT NULL DEFAULT 'GTC';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+
+UPDATE orders SET time_in_force = 'IOC' WHERE kind = 'MARKET';
+
+CREATE INDEX IF NOT EXISTS idx_orders_expires_at ON orders(expires_at) WHERE expires_at IS NOT NULL;
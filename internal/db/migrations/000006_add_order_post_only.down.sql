@@ -0,0 +1,2 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS reprice_on_cross;
+ALTER TABLE orders DROP COLUMN IF EXISTS post_only;
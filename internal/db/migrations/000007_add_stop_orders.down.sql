@@ -0,0 +1,4 @@
+-- enum values cannot be dropped; STOP_MARKET, STOP_LIMIT and TRIGGERED stay
+-- in order_kind and order_status but are no longer used.
+ALTER TABLE orders DROP COLUMN IF EXISTS triggered_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS stop_price;
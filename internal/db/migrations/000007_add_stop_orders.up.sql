@@ -0,0 +1,6 @@
+ALTER TYPE order_kind ADD VALUE IF NOT EXISTS 'STOP_MARKET';
+ALTER TYPE order_kind ADD VALUE IF NOT EXISTS 'STOP_LIMIT';
+ALTER TYPE order_status ADD VALUE IF NOT EXISTS 'TRIGGERED' AFTER 'OPEN';
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS stop_price NUMERIC(30, 10);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS triggered_at TIMESTAMPTZ;
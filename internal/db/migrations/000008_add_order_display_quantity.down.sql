@@ -0,0 +1,2 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS visible_quantity;
+ALTER TABLE orders DROP COLUMN IF EXISTS display_quantity;
@@ -0,0 +1,2 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS display_quantity NUMERIC(30, 18);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS visible_quantity NUMERIC(30, 18);
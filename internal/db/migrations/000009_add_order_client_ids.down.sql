@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_orders_account_idempotency_key;
+DROP INDEX IF EXISTS idx_orders_account_client_order_id;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS idempotency_key;
+ALTER TABLE orders DROP COLUMN IF EXISTS client_order_id;
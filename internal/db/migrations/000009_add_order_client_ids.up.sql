@@ -0,0 +1,7 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS client_order_id VARCHAR(64);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS idempotency_key VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_account_client_order_id
+    ON orders(account_id, client_order_id) WHERE client_order_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_account_idempotency_key
+    ON orders(account_id, idempotency_key) WHERE idempotency_key IS NOT NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS self_trade_prevention;
+ALTER TABLE accounts DROP COLUMN IF EXISTS self_trade_prevention;
+
+DROP TYPE IF EXISTS self_trade_prevention;
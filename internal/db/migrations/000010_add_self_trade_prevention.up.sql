@@ -0,0 +1,9 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'self_trade_prevention') THEN
+        CREATE TYPE self_trade_prevention AS ENUM ('NONE', 'CANCEL_NEWEST', 'CANCEL_OLDEST', 'CANCEL_BOTH', 'DECREMENT_AND_CANCEL');
+    END IF;
+END$$;
+
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS self_trade_prevention self_trade_prevention NOT NULL DEFAULT 'NONE';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS self_trade_prevention self_trade_prevention NOT NULL DEFAULT 'NONE';
@@ -0,0 +1,9 @@
+ALTER TABLE trades DROP COLUMN IF EXISTS maker_fee_asset;
+ALTER TABLE trades DROP COLUMN IF EXISTS maker_fee;
+ALTER TABLE trades DROP COLUMN IF EXISTS fee_asset;
+ALTER TABLE trades DROP COLUMN IF EXISTS fee;
+
+-- the house account is kept: the fees it collected still reference it
+
+DROP TABLE IF EXISTS account_fee_overrides;
+DROP TABLE IF EXISTS instrument_fee_schedules;
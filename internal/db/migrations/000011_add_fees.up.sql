@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS instrument_fee_schedules (
+    instrument_id UUID PRIMARY KEY REFERENCES instruments(id) ON DELETE CASCADE,
+    maker_fee_bps INTEGER NOT NULL DEFAULT 0 CHECK (maker_fee_bps BETWEEN 0 AND 10000),
+    taker_fee_bps INTEGER NOT NULL DEFAULT 0 CHECK (taker_fee_bps BETWEEN 0 AND 10000),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS account_fee_overrides (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+    maker_fee_bps INTEGER CHECK (maker_fee_bps BETWEEN 0 AND 10000),
+    taker_fee_bps INTEGER CHECK (taker_fee_bps BETWEEN 0 AND 10000),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- house account that collects every fee charged at settlement
+INSERT INTO accounts (id, name, email)
+VALUES ('00000000-0000-0000-0000-000000000001', 'Exchange Fees', 'fees@exchange.local')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS fee NUMERIC(30, 18) NOT NULL DEFAULT 0;
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS fee_asset VARCHAR(10);
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS maker_fee NUMERIC(30, 18) NOT NULL DEFAULT 0;
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS maker_fee_asset VARCHAR(10);
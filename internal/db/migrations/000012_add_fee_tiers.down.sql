@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_trades_created_at;
+
+DROP TABLE IF EXISTS account_trading_volumes;
+DROP TABLE IF EXISTS fee_tiers;
@@ -0,0 +1,27 @@
+-- a tier caps the maker and taker rates of every instrument for accounts whose
+-- 30-day volume reaches min_volume; NULL leaves that side uncapped
+CREATE TABLE IF NOT EXISTS fee_tiers (
+    level INTEGER PRIMARY KEY CHECK (level >= 0),
+    name VARCHAR(64) NOT NULL,
+    min_volume NUMERIC(38, 18) NOT NULL UNIQUE CHECK (min_volume >= 0),
+    maker_fee_bps INTEGER CHECK (maker_fee_bps BETWEEN 0 AND 10000),
+    taker_fee_bps INTEGER CHECK (taker_fee_bps BETWEEN 0 AND 10000)
+);
+
+INSERT INTO fee_tiers (level, name, min_volume, maker_fee_bps, taker_fee_bps) VALUES
+    (0, 'Regular', 0, NULL, NULL),
+    (1, 'VIP 1', 1000000, 8, 10),
+    (2, 'VIP 2', 5000000, 6, 8),
+    (3, 'VIP 3', 25000000, 4, 6),
+    (4, 'VIP 4', 100000000, 2, 4)
+ON CONFLICT DO NOTHING;
+
+-- rolling 30-day traded notional per account in the reference quote asset,
+-- rewritten by the nightly tier job
+CREATE TABLE IF NOT EXISTS account_trading_volumes (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+    volume NUMERIC(38, 18) NOT NULL DEFAULT 0,
+    computed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_trades_created_at ON trades(created_at);
@@ -0,0 +1,5 @@
+ALTER TABLE instruments DROP COLUMN IF EXISTS min_notional;
+ALTER TABLE instruments DROP COLUMN IF EXISTS max_quantity;
+ALTER TABLE instruments DROP COLUMN IF EXISTS min_quantity;
+ALTER TABLE instruments DROP COLUMN IF EXISTS lot_size;
+ALTER TABLE instruments DROP COLUMN IF EXISTS tick_size;
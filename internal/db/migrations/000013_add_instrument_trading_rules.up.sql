@@ -0,0 +1,5 @@
+ALTER TABLE instruments ADD COLUMN IF NOT EXISTS tick_size NUMERIC(30, 18) CHECK (tick_size > 0);
+ALTER TABLE instruments ADD COLUMN IF NOT EXISTS lot_size NUMERIC(30, 18) CHECK (lot_size > 0);
+ALTER TABLE instruments ADD COLUMN IF NOT EXISTS min_quantity NUMERIC(30, 18) CHECK (min_quantity > 0);
+ALTER TABLE instruments ADD COLUMN IF NOT EXISTS max_quantity NUMERIC(30, 18) CHECK (max_quantity > 0);
+ALTER TABLE instruments ADD COLUMN IF NOT EXISTS min_notional NUMERIC(30, 18) CHECK (min_notional > 0);
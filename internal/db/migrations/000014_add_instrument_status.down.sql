@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS instrument_status_changes;
+ALTER TABLE instruments DROP COLUMN IF EXISTS status;
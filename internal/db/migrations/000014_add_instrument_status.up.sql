@@ -0,0 +1,14 @@
+ALTER TABLE instruments ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'TRADING'
+    CHECK (status IN ('PRE_OPEN', 'TRADING', 'HALTED', 'POST_ONLY', 'DELISTED'));
+
+-- every status change of an instrument, with the reason given for it
+CREATE TABLE IF NOT EXISTS instrument_status_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    instrument_id UUID NOT NULL REFERENCES instruments(id) ON DELETE CASCADE,
+    from_status VARCHAR(16),
+    to_status VARCHAR(16) NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_instrument_status_changes_instrument_id ON instrument_status_changes(instrument_id, created_at);
@@ -0,0 +1,6 @@
+ALTER TABLE instruments DROP COLUMN IF EXISTS auction_ends_at;
+
+UPDATE instruments SET status = 'HALTED' WHERE status = 'AUCTION';
+ALTER TABLE instruments DROP CONSTRAINT IF EXISTS instruments_status_check;
+ALTER TABLE instruments ADD CONSTRAINT instruments_status_check
+    CHECK (status IN ('PRE_OPEN', 'TRADING', 'HALTED', 'POST_ONLY', 'DELISTED'));
@@ -0,0 +1,6 @@
+ALTER TABLE instruments DROP CONSTRAINT IF EXISTS instruments_status_check;
+ALTER TABLE instruments ADD CONSTRAINT instruments_status_check
+    CHECK (status IN ('PRE_OPEN', 'TRADING', 'HALTED', 'POST_ONLY', 'AUCTION', 'DELISTED'));
+
+-- when the call phase of an instrument in AUCTION ends and its book uncrosses
+ALTER TABLE instruments ADD COLUMN IF NOT EXISTS auction_ends_at TIMESTAMPTZ;
@@ -0,0 +1,5 @@
+ALTER TABLE instruments DROP COLUMN IF EXISTS circuit_breaker_auction_seconds;
+ALTER TABLE instruments DROP COLUMN IF EXISTS circuit_breaker_window_seconds;
+ALTER TABLE instruments DROP COLUMN IF EXISTS circuit_breaker_percent;
+ALTER TABLE instruments DROP COLUMN IF EXISTS price_band_percent;
+ALTER TABLE instruments DROP COLUMN IF EXISTS reference_price;
@@ -0,0 +1,11 @@
+-- limit prices further than this from the last trade or reference price are rejected
+ALTER TABLE instruments ADD COLUMN IF NOT EXISTS reference_price NUMERIC(30, 18) CHECK (reference_price > 0);
+ALTER TABLE instruments ADD COLUMN IF NOT EXISTS price_band_percent NUMERIC(30, 18) CHECK (price_band_percent > 0);
+
+-- a move larger than circuit_breaker_percent within the window pauses trading,
+-- in an auction of circuit_breaker_auction_seconds or, when it is 0, a halt
+ALTER TABLE instruments ADD COLUMN IF NOT EXISTS circuit_breaker_percent NUMERIC(30, 18) CHECK (circuit_breaker_percent > 0);
+ALTER TABLE instruments ADD COLUMN IF NOT EXISTS circuit_breaker_window_seconds INTEGER NOT NULL DEFAULT 0
+    CHECK (circuit_breaker_window_seconds >= 0);
+ALTER TABLE instruments ADD COLUMN IF NOT EXISTS circuit_breaker_auction_seconds INTEGER NOT NULL DEFAULT 0
+    CHECK (circuit_breaker_auction_seconds >= 0);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_orders_order_list_id;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS order_list_id;
+
+DROP TABLE IF EXISTS order_lists;
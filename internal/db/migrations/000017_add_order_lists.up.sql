@@ -0,0 +1,18 @@
+-- linked orders, such as the two orders of an OCO list, backed by a single
+-- reservation: reserved is what the list locked and shared_reserved the part
+-- of it every order counts as its own
+CREATE TABLE IF NOT EXISTS order_lists (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    instrument_id UUID NOT NULL REFERENCES instruments(id),
+    contingency_type VARCHAR(16) NOT NULL DEFAULT 'OCO' CHECK (contingency_type IN ('OCO')),
+    status VARCHAR(16) NOT NULL DEFAULT 'EXECUTING' CHECK (status IN ('EXECUTING', 'ALL_DONE')),
+    reserved NUMERIC(30, 18) NOT NULL CHECK (reserved >= 0),
+    shared_reserved NUMERIC(30, 18) NOT NULL CHECK (shared_reserved >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS order_list_id UUID REFERENCES order_lists(id);
+
+CREATE INDEX IF NOT EXISTS idx_orders_order_list_id ON orders(order_list_id) WHERE order_list_id IS NOT NULL;
@@ -0,0 +1,5 @@
+-- enum values cannot be dropped; TRAILING_STOP_MARKET stays in order_kind but
+-- is no longer used.
+ALTER TABLE orders DROP COLUMN IF EXISTS water_mark;
+ALTER TABLE orders DROP COLUMN IF EXISTS trail_percent;
+ALTER TABLE orders DROP COLUMN IF EXISTS trail_amount;
@@ -0,0 +1,7 @@
+ALTER TYPE order_kind ADD VALUE IF NOT EXISTS 'TRAILING_STOP_MARKET';
+
+-- stop_price holds the current trigger price of a trailing stop, which trails
+-- water_mark, the best trade price since the order was placed
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS trail_amount NUMERIC(30, 10) CHECK (trail_amount > 0);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS trail_percent NUMERIC(30, 10) CHECK (trail_percent > 0 AND trail_percent < 100);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS water_mark NUMERIC(30, 10);
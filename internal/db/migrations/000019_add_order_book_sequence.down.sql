@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_orders_active;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS book_sequence;
@@ -0,0 +1,6 @@
+-- book_sequence is the order's place in the time priority of its book, set
+-- whenever the engine queues it; orders the engine never booked have none
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS book_sequence BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_orders_active ON orders(book_sequence, created_at)
+    WHERE status IN ('OPEN', 'TRIGGERED', 'PARTIALLY_FILLED');
@@ -0,0 +1,3 @@
+-- amendment_id is the last amendment the engine applied to the order, so an
+-- amendment message delivered twice is applied once
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS amendment_id UUID;
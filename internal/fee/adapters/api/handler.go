@@ -0,0 +1,238 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/fee/app"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/fee/domain/dto"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/ierr"
+)
+
+type Fee interface {
+	FindSchedule(c echo.Context) error
+	UpdateSchedule(c echo.Context) error
+	FindOverride(c echo.Context) error
+	UpdateOverride(c echo.Context) error
+	DeleteOverride(c echo.Context) error
+	FindTiers(c echo.Context) error
+	FindAccountTier(c echo.Context) error
+	RegisterRoutes(g *echo.Group)
+	RegisterAccountRoutes(g *echo.Group)
+}
+
+type fee struct {
+	feeApp app.Fee
+}
+
+func NewFeeHandler(feeApp app.Fee) Fee {
+	return &fee{
+		feeApp: feeApp,
+	}
+}
+
+func (h *fee) RegisterRoutes(g *echo.Group) {
+	g.GET("/instruments/:instrument_id", h.FindSchedule)
+	g.PUT("/instruments/:instrument_id", h.UpdateSchedule)
+	g.GET("/accounts/:account_id", h.FindOverride)
+	g.PUT("/accounts/:account_id", h.UpdateOverride)
+	g.DELETE("/accounts/:account_id", h.DeleteOverride)
+	g.GET("/tiers", h.FindTiers)
+}
+
+// RegisterAccountRoutes registers the fee routes that live under an account.
+func (h *fee) RegisterAccountRoutes(g *echo.Group) {
+	g.GET("/:id/fee-tier", h.FindAccountTier)
+}
+
+// FindSchedule godoc
+// @Summary      Busca as taxas de um instrumento
+// @Description  Retorna as taxas maker e taker do instrumento em basis points. Instrumentos sem tabela de taxas cobram zero.
+// @Tags         fees
+// @Produce      json
+// @Param        instrument_id  path      string  true  "Instrument ID"
+// @Success      200  {object}  dto.ScheduleDTO
+// @Failure      404  {object}  map[string]string
+// @Router       /v1/fees/instruments/{instrument_id} [get]
+func (h *fee) FindSchedule(c echo.Context) error {
+	instrumentID := c.Param("instrument_id")
+	if instrumentID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "instrument ID cannot be empty")
+	}
+
+	schedule, err := h.feeApp.FindSchedule(c.Request().Context(), instrumentID)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "internal server error"})
+	}
+
+	return c.JSON(http.StatusOK, schedule)
+}
+
+// UpdateSchedule godoc
+// @Summary      Define as taxas de um instrumento
+// @Description  Define as taxas maker e taker do instrumento em basis points (0 a 10000)
+// @Tags         fees
+// @Accept       json
+// @Produce      json
+// @Param        instrument_id  path      string                     true  "Instrument ID"
+// @Param        schedule       body      dto.UpdateScheduleRequest  true  "Fee schedule"
+// @Success      200  {object}  dto.ScheduleDTO
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /v1/fees/instruments/{instrument_id} [put]
+func (h *fee) UpdateSchedule(c echo.Context) error {
+	instrumentID := c.Param("instrument_id")
+	if instrumentID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "instrument ID cannot be empty")
+	}
+
+	var request dto.UpdateScheduleRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	schedule, err := h.feeApp.UpdateSchedule(c.Request().Context(), instrumentID, request)
+	if err != nil {
+		if errors.Is(err, ierr.ErrInvalidInput) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		if errors.Is(err, ierr.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "internal server error"})
+	}
+
+	return c.JSON(http.StatusOK, schedule)
+}
+
+// FindOverride godoc
+// @Summary      Busca as taxas personalizadas de uma conta
+// @Tags         fees
+// @Produce      json
+// @Param        account_id  path      string  true  "Account ID"
+// @Success      200  {object}  dto.OverrideDTO
+// @Failure      404  {object}  map[string]string
+// @Router       /v1/fees/accounts/{account_id} [get]
+func (h *fee) FindOverride(c echo.Context) error {
+	accountID := c.Param("account_id")
+	if accountID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "account ID cannot be empty")
+	}
+
+	override, err := h.feeApp.FindOverride(c.Request().Context(), accountID)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "internal server error"})
+	}
+
+	return c.JSON(http.StatusOK, override)
+}
+
+// UpdateOverride godoc
+// @Summary      Define taxas personalizadas para uma conta
+// @Description  Substitui as taxas maker e/ou taker dos instrumentos para a conta. Um lado omitido segue a taxa do instrumento.
+// @Tags         fees
+// @Accept       json
+// @Produce      json
+// @Param        account_id  path      string                     true  "Account ID"
+// @Param        override    body      dto.UpdateOverrideRequest  true  "Fee override"
+// @Success      200  {object}  dto.OverrideDTO
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /v1/fees/accounts/{account_id} [put]
+func (h *fee) UpdateOverride(c echo.Context) error {
+	accountID := c.Param("account_id")
+	if accountID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "account ID cannot be empty")
+	}
+
+	var request dto.UpdateOverrideRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	override, err := h.feeApp.UpdateOverride(c.Request().Context(), accountID, request)
+	if err != nil {
+		if errors.Is(err, ierr.ErrInvalidInput) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		if errors.Is(err, ierr.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "internal server error"})
+	}
+
+	return c.JSON(http.StatusOK, override)
+}
+
+// DeleteOverride godoc
+// @Summary      Remove as taxas personalizadas de uma conta
+// @Tags         fees
+// @Produce      json
+// @Param        account_id  path      string  true  "Account ID"
+// @Success      204  "No Content"
+// @Failure      404  {object}  map[string]string
+// @Router       /v1/fees/accounts/{account_id} [delete]
+func (h *fee) DeleteOverride(c echo.Context) error {
+	accountID := c.Param("account_id")
+	if accountID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "account ID cannot be empty")
+	}
+
+	if err := h.feeApp.DeleteOverride(c.Request().Context(), accountID); err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "internal server error"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// FindTiers godoc
+// @Summary      Lista os níveis de taxa
+// @Description  Lista os níveis de taxa por volume de 30 dias. As taxas de cada nível limitam as taxas dos instrumentos; taxa nula não limita.
+// @Tags         fees
+// @Produce      json
+// @Success      200  {array}   dto.TierDTO
+// @Router       /v1/fees/tiers [get]
+func (h *fee) FindTiers(c echo.Context) error {
+	tiers, err := h.feeApp.FindTiers(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "internal server error"})
+	}
+
+	return c.JSON(http.StatusOK, tiers)
+}
+
+// FindAccountTier godoc
+// @Summary      Busca o nível de taxa de uma conta
+// @Description  Retorna o nível de taxa atual da conta, o volume de 30 dias no ativo de referência e o progresso até o próximo nível, conforme o último recálculo noturno.
+// @Tags         accounts
+// @Produce      json
+// @Param        id   path      string  true  "Account ID"
+// @Success      200  {object}  dto.AccountTierDTO
+// @Failure      404  {object}  map[string]string
+// @Router       /v1/accounts/{id}/fee-tier [get]
+func (h *fee) FindAccountTier(c echo.Context) error {
+	accountID := c.Param("id")
+	if accountID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "account ID cannot be empty")
+	}
+
+	tier, err := h.feeApp.FindAccountTier(c.Request().Context(), accountID)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "internal server error"})
+	}
+
+	return c.JSON(http.StatusOK, tier)
+}
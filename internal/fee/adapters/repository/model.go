@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/fee/domain/entity"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/decimal"
+)
+
+type ScheduleModel struct {
+	InstrumentID string    `db:"instrument_id"`
+	MakerFeeBps  int       `db:"maker_fee_bps"`
+	TakerFeeBps  int       `db:"taker_fee_bps"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+type OverrideModel struct {
+	AccountID   string    `db:"account_id"`
+	MakerFeeBps *int      `db:"maker_fee_bps"`
+	TakerFeeBps *int      `db:"taker_fee_bps"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func (m *ScheduleModel) ToEntity() entity.Schedule {
+	return entity.Schedule{
+		InstrumentID: m.InstrumentID,
+		MakerFeeBps:  m.MakerFeeBps,
+		TakerFeeBps:  m.TakerFeeBps,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
+
+func (m *OverrideModel) ToEntity() entity.Override {
+	return entity.Override{
+		AccountID:   m.AccountID,
+		MakerFeeBps: m.MakerFeeBps,
+		TakerFeeBps: m.TakerFeeBps,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+type TierModel struct {
+	Level       int    `db:"level"`
+	Name        string `db:"name"`
+	MinVolume   string `db:"min_volume"`
+	MakerFeeBps *int   `db:"maker_fee_bps"`
+	TakerFeeBps *int   `db:"taker_fee_bps"`
+}
+
+func (m *TierModel) ToEntity() entity.Tier {
+	minVolume, _ := decimal.Parse(m.MinVolume)
+	return entity.Tier{
+		Level:       m.Level,
+		Name:        m.Name,
+		MinVolume:   minVolume,
+		MakerFeeBps: m.MakerFeeBps,
+		TakerFeeBps: m.TakerFeeBps,
+	}
+}
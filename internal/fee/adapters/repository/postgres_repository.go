@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/db"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/fee/domain/entity"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/fee/domain/port"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/decimal"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/ierr"
+)
+
+type feeRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewFeeRepository(db *pgxpool.Pool) port.FeeRepository {
+	return &feeRepository{db: db}
+}
+
+// FindSchedule returns the fee schedule of an instrument, or ErrNotFound when
+// none was set.
+func (r *feeRepository) FindSchedule(ctx context.Context, instrumentID string) (entity.Schedule, error) {
+	query := `SELECT instrument_id, maker_fee_bps, taker_fee_bps, updated_at FROM instrument_fee_schedules WHERE instrument_id = $1`
+	var m ScheduleModel
+	err := db.Conn(ctx, r.db).QueryRow(ctx, query, instrumentID).Scan(
+		&m.InstrumentID,
+		&m.MakerFeeBps,
+		&m.TakerFeeBps,
+		&m.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Schedule{}, ierr.ErrNotFound
+		}
+		return entity.Schedule{}, err
+	}
+	return m.ToEntity(), nil
+}
+
+// SaveSchedule creates or replaces the fee schedule of an instrument.
+func (r *feeRepository) SaveSchedule(ctx context.Context, schedule entity.Schedule) (entity.Schedule, error) {
+	query := `INSERT INTO instrument_fee_schedules (instrument_id, maker_fee_bps, taker_fee_bps, updated_at)
+        VALUES ($1, $2, $3, NOW())
+        ON CONFLICT (instrument_id) DO UPDATE
+        SET maker_fee_bps = EXCLUDED.maker_fee_bps, taker_fee_bps = EXCLUDED.taker_fee_bps, updated_at = NOW()
+        RETURNING updated_at`
+	err := db.Conn(ctx, r.db).QueryRow(ctx, query,
+		schedule.InstrumentID,
+		schedule.MakerFeeBps,
+		schedule.TakerFeeBps,
+	).Scan(&schedule.UpdatedAt)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+	return schedule, nil
+}
+
+// FindOverride returns the fee override of an account, or ErrNotFound when
+// the account has none.
+func (r *feeRepository) FindOverride(ctx context.Context, accountID string) (entity.Override, error) {
+	query := `SELECT account_id, maker_fee_bps, taker_fee_bps, updated_at FROM account_fee_overrides WHERE account_id = $1`
+	var m OverrideModel
+	err := db.Conn(ctx, r.db).QueryRow(ctx, query, accountID).Scan(
+		&m.AccountID,
+		&m.MakerFeeBps,
+		&m.TakerFeeBps,
+		&m.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Override{}, ierr.ErrNotFound
+		}
+		return entity.Override{}, err
+	}
+	return m.ToEntity(), nil
+}
+
+// SaveOverride creates or replaces the fee override of an account.
+func (r *feeRepository) SaveOverride(ctx context.Context, override entity.Override) (entity.Override, error) {
+	query := `INSERT INTO account_fee_overrides (account_id, maker_fee_bps, taker_fee_bps, updated_at)
+        VALUES ($1, $2, $3, NOW())
+        ON CONFLICT (account_id) DO UPDATE
+        SET maker_fee_bps = EXCLUDED.maker_fee_bps, taker_fee_bps = EXCLUDED.taker_fee_bps, updated_at = NOW()
+        RETURNING updated_at`
+	err := db.Conn(ctx, r.db).QueryRow(ctx, query,
+		override.AccountID,
+		override.MakerFeeBps,
+		override.TakerFeeBps,
+	).Scan(&override.UpdatedAt)
+	if err != nil {
+		return entity.Override{}, err
+	}
+	return override, nil
+}
+
+// DeleteOverride removes the fee override of an account.
+func (r *feeRepository) DeleteOverride(ctx context.Context, accountID string) error {
+	query := `DELETE FROM account_fee_overrides WHERE account_id = $1`
+	result, err := db.Conn(ctx, r.db).Exec(ctx, query, accountID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ierr.ErrNotFound
+	}
+	return nil
+}
+
+// FindTiers returns every fee tier ordered by minimum volume.
+func (r *feeRepository) FindTiers(ctx context.Context) ([]entity.Tier, error) {
+	query := `SELECT level, name, min_volume::TEXT, maker_fee_bps, taker_fee_bps FROM fee_tiers ORDER BY min_volume`
+	rows, err := db.Conn(ctx, r.db).Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tiers []entity.Tier
+	for rows.Next() {
+		var m TierModel
+		if err := rows.Scan(&m.Level, &m.Name, &m.MinVolume, &m.MakerFeeBps, &m.TakerFeeBps); err != nil {
+			return nil, err
+		}
+		tiers = append(tiers, m.ToEntity())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tiers, nil
+}
+
+// FindAccountVolume returns the 30-day volume last computed for an account,
+// or ErrNotFound when it did not trade in the window.
+func (r *feeRepository) FindAccountVolume(ctx context.Context, accountID string) (entity.AccountVolume, error) {
+	query := `SELECT account_id, volume::TEXT, computed_at FROM account_trading_volumes WHERE account_id = $1`
+	var volume entity.AccountVolume
+	var amount string
+	err := db.Conn(ctx, r.db).QueryRow(ctx, query, accountID).Scan(&volume.AccountID, &amount, &volume.ComputedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.AccountVolume{}, ierr.ErrNotFound
+		}
+		return entity.AccountVolume{}, err
+	}
+	volume.Volume, _ = decimal.Parse(amount)
+	return volume, nil
+}
+
+// ReplaceAccountVolumes swaps every stored volume for the given ones. It must
+// run inside a transaction so readers never see the table half rewritten.
+func (r *feeRepository) ReplaceAccountVolumes(ctx context.Context, volumes []entity.AccountVolume) error {
+	conn := db.Conn(ctx, r.db)
+	if _, err := conn.Exec(ctx, `DELETE FROM account_trading_volumes`); err != nil {
+		return err
+	}
+	if len(volumes) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, v := range volumes {
+		batch.Queue(`INSERT INTO account_trading_volumes (account_id, volume, computed_at) VALUES ($1, $2, $3)`,
+			v.AccountID, v.Volume.Text('f', 18), v.ComputedAt)
+	}
+	results := conn.SendBatch(ctx, batch)
+	defer results.Close()
+	for range volumes {
+		if _, err := results.Exec(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
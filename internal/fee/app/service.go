@@ -0,0 +1,274 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+
+	accountPort "github.com/mthpedrosa/financial-exchange-challenge/internal/account/domain/port"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/db"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/fee/domain/dto"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/fee/domain/entity"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/fee/domain/port"
+	instrumentPort "github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/domain/port"
+	tradePort "github.com/mthpedrosa/financial-exchange-challenge/internal/trade/domain/port"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/ierr"
+)
+
+type Fee interface {
+	FindSchedule(ctx context.Context, instrumentID string) (dto.ScheduleDTO, error)
+	UpdateSchedule(ctx context.Context, instrumentID string, request dto.UpdateScheduleRequest) (dto.ScheduleDTO, error)
+	FindOverride(ctx context.Context, accountID string) (dto.OverrideDTO, error)
+	UpdateOverride(ctx context.Context, accountID string, request dto.UpdateOverrideRequest) (dto.OverrideDTO, error)
+	DeleteOverride(ctx context.Context, accountID string) error
+	FindTiers(ctx context.Context) ([]dto.TierDTO, error)
+	FindAccountTier(ctx context.Context, accountID string) (dto.AccountTierDTO, error)
+	RecomputeTiers(ctx context.Context, now time.Time) error
+	Rates(ctx context.Context, accountID, instrumentID string) (entity.Rates, error)
+}
+
+type fee struct {
+	feePort        port.FeeRepository
+	accountPort    accountPort.AccountRepository
+	instrumentPort instrumentPort.InstrumentRepository
+	tradePort      tradePort.TradeRepository
+	txManager      db.TxManager
+	referenceAsset string
+}
+
+// NewFeeApp creates the fee service. Volumes that decide fee tiers are
+// normalized to referenceAsset.
+func NewFeeApp(
+	feePort port.FeeRepository,
+	accountPort accountPort.AccountRepository,
+	instrumentPort instrumentPort.InstrumentRepository,
+	tradePort tradePort.TradeRepository,
+	txManager db.TxManager,
+	referenceAsset string,
+) Fee {
+	return &fee{
+		feePort:        feePort,
+		accountPort:    accountPort,
+		instrumentPort: instrumentPort,
+		tradePort:      tradePort,
+		txManager:      txManager,
+		referenceAsset: referenceAsset,
+	}
+}
+
+// FindSchedule returns the fee schedule of an instrument. Instruments without
+// one report zero rates.
+func (f *fee) FindSchedule(ctx context.Context, instrumentID string) (dto.ScheduleDTO, error) {
+	if _, err := f.instrumentPort.FindByID(ctx, instrumentID); err != nil {
+		return dto.ScheduleDTO{}, err
+	}
+
+	schedule, err := f.schedule(ctx, instrumentID)
+	if err != nil {
+		return dto.ScheduleDTO{}, err
+	}
+	return schedule.ToDTO(), nil
+}
+
+func (f *fee) UpdateSchedule(ctx context.Context, instrumentID string, request dto.UpdateScheduleRequest) (dto.ScheduleDTO, error) {
+	schedule, err := entity.ToSchedule(instrumentID, request)
+	if err != nil {
+		return dto.ScheduleDTO{}, fmt.Errorf("%s: %w", err.Error(), ierr.ErrInvalidInput)
+	}
+	if _, err := f.instrumentPort.FindByID(ctx, instrumentID); err != nil {
+		return dto.ScheduleDTO{}, err
+	}
+
+	saved, err := f.feePort.SaveSchedule(ctx, schedule)
+	if err != nil {
+		return dto.ScheduleDTO{}, err
+	}
+	return saved.ToDTO(), nil
+}
+
+func (f *fee) FindOverride(ctx context.Context, accountID string) (dto.OverrideDTO, error) {
+	override, err := f.feePort.FindOverride(ctx, accountID)
+	if err != nil {
+		return dto.OverrideDTO{}, err
+	}
+	return override.ToDTO(), nil
+}
+
+func (f *fee) UpdateOverride(ctx context.Context, accountID string, request dto.UpdateOverrideRequest) (dto.OverrideDTO, error) {
+	override, err := entity.ToOverride(accountID, request)
+	if err != nil {
+		return dto.OverrideDTO{}, fmt.Errorf("%s: %w", err.Error(), ierr.ErrInvalidInput)
+	}
+	if _, err := f.accountPort.FindByID(ctx, accountID); err != nil {
+		return dto.OverrideDTO{}, err
+	}
+
+	saved, err := f.feePort.SaveOverride(ctx, override)
+	if err != nil {
+		return dto.OverrideDTO{}, err
+	}
+	return saved.ToDTO(), nil
+}
+
+func (f *fee) DeleteOverride(ctx context.Context, accountID string) error {
+	return f.feePort.DeleteOverride(ctx, accountID)
+}
+
+func (f *fee) FindTiers(ctx context.Context) ([]dto.TierDTO, error) {
+	tiers, err := f.feePort.FindTiers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return entity.ToTierListDTO(tiers), nil
+}
+
+// FindAccountTier reports the account's current fee tier and its progress to
+// the next one, as of the last tier computation.
+func (f *fee) FindAccountTier(ctx context.Context, accountID string) (dto.AccountTierDTO, error) {
+	if _, err := f.accountPort.FindByID(ctx, accountID); err != nil {
+		return dto.AccountTierDTO{}, err
+	}
+
+	tiers, err := f.feePort.FindTiers(ctx)
+	if err != nil {
+		return dto.AccountTierDTO{}, err
+	}
+	if len(tiers) == 0 {
+		return dto.AccountTierDTO{}, fmt.Errorf("no fee tiers configured: %w", ierr.ErrNotFound)
+	}
+	volume, err := f.volume(ctx, accountID)
+	if err != nil {
+		return dto.AccountTierDTO{}, err
+	}
+	return volume.ToAccountTierDTO(tiers, f.referenceAsset), nil
+}
+
+// RecomputeTiers recomputes every account's rolling 30-day volume as of now,
+// in the reference asset. Notional in other quote assets is converted at the
+// last price traded against the reference asset; assets that never traded
+// against it do not count.
+func (f *fee) RecomputeTiers(ctx context.Context, now time.Time) error {
+	notionals, err := f.tradePort.NotionalByAccount(ctx, now.Add(-entity.VolumeWindow))
+	if err != nil {
+		return err
+	}
+
+	byAccount := make(map[string]map[string]*big.Float)
+	prices := map[string]*big.Float{f.referenceAsset: big.NewFloat(1)}
+	var accountIDs []string
+	for _, n := range notionals {
+		if _, ok := byAccount[n.AccountID]; !ok {
+			byAccount[n.AccountID] = make(map[string]*big.Float)
+			accountIDs = append(accountIDs, n.AccountID)
+		}
+		byAccount[n.AccountID][n.QuoteAsset] = n.Notional
+
+		if _, ok := prices[n.QuoteAsset]; ok {
+			continue
+		}
+		price, err := f.referencePrice(ctx, n.QuoteAsset)
+		if err != nil {
+			return err
+		}
+		if price != nil {
+			prices[n.QuoteAsset] = price
+		}
+	}
+
+	volumes := make([]entity.AccountVolume, len(accountIDs))
+	for i, accountID := range accountIDs {
+		volume, skipped := entity.Normalize(byAccount[accountID], prices)
+		if len(skipped) > 0 {
+			slog.Warn("volume not converted to reference asset", "account_id", accountID, "assets", skipped)
+		}
+		volumes[i] = entity.AccountVolume{AccountID: accountID, Volume: volume, ComputedAt: now}
+	}
+
+	err = f.txManager.WithTx(ctx, func(ctx context.Context) error {
+		return f.feePort.ReplaceAccountVolumes(ctx, volumes)
+	})
+	if err != nil {
+		return err
+	}
+	slog.Info("fee tiers recomputed", "accounts", len(volumes), "reference_asset", f.referenceAsset)
+	return nil
+}
+
+// Rates returns the rates an account pays on an instrument: the instrument's
+// schedule capped by the account's fee tier, with the account's override
+// applied on top.
+func (f *fee) Rates(ctx context.Context, accountID, instrumentID string) (entity.Rates, error) {
+	schedule, err := f.schedule(ctx, instrumentID)
+	if err != nil {
+		return entity.Rates{}, err
+	}
+	rates := schedule.Rates()
+
+	tiers, err := f.feePort.FindTiers(ctx)
+	if err != nil {
+		return entity.Rates{}, err
+	}
+	if len(tiers) > 0 {
+		volume, err := f.volume(ctx, accountID)
+		if err != nil {
+			return entity.Rates{}, err
+		}
+		tier, _ := entity.TierFor(tiers, volume.Volume)
+		rates = rates.CappedBy(tier)
+	}
+
+	override, err := f.feePort.FindOverride(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return rates, nil
+		}
+		return entity.Rates{}, err
+	}
+	return rates.WithOverride(override), nil
+}
+
+func (f *fee) schedule(ctx context.Context, instrumentID string) (entity.Schedule, error) {
+	schedule, err := f.feePort.FindSchedule(ctx, instrumentID)
+	if errors.Is(err, ierr.ErrNotFound) {
+		return entity.Schedule{InstrumentID: instrumentID}, nil
+	}
+	return schedule, err
+}
+
+// volume returns the account's last computed volume, zero when it did not
+// trade in the window.
+func (f *fee) volume(ctx context.Context, accountID string) (entity.AccountVolume, error) {
+	volume, err := f.feePort.FindAccountVolume(ctx, accountID)
+	if errors.Is(err, ierr.ErrNotFound) {
+		return entity.AccountVolume{AccountID: accountID, Volume: new(big.Float)}, nil
+	}
+	return volume, err
+}
+
+// referencePrice returns the value of one unit of asset in the reference
+// asset, from the last trade on either asset/reference or reference/asset.
+// It returns nil when neither instrument traded.
+func (f *fee) referencePrice(ctx context.Context, asset string) (*big.Float, error) {
+	price, err := f.tradePort.LastPrice(ctx, asset, f.referenceAsset)
+	if err == nil {
+		return price, nil
+	}
+	if !errors.Is(err, ierr.ErrNotFound) {
+		return nil, err
+	}
+
+	inverse, err := f.tradePort.LastPrice(ctx, f.referenceAsset, asset)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if inverse.Sign() == 0 {
+		return nil, nil
+	}
+	return new(big.Float).Quo(big.NewFloat(1), inverse), nil
+}
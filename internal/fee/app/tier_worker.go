@@ -0,0 +1,46 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// TierWorker recomputes the accounts' fee tiers every night.
+type TierWorker struct {
+	fees Fee
+}
+
+func NewTierWorker(fees Fee) *TierWorker {
+	return &TierWorker{
+		fees: fees,
+	}
+}
+
+// Run recomputes the tiers once on start, so a restart never leaves them
+// stale, and then at every midnight UTC until ctx is cancelled.
+func (w *TierWorker) Run(ctx context.Context) {
+	w.recompute(ctx, time.Now())
+
+	for {
+		timer := time.NewTimer(time.Until(nextMidnight(time.Now())))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case now := <-timer.C:
+			w.recompute(ctx, now)
+		}
+	}
+}
+
+func (w *TierWorker) recompute(ctx context.Context, now time.Time) {
+	if err := w.fees.RecomputeTiers(ctx, now); err != nil {
+		slog.Error("failed to recompute fee tiers", "error", err)
+	}
+}
+
+func nextMidnight(now time.Time) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type ScheduleDTO struct {
+	InstrumentID string     `json:"instrument_id"`
+	MakerFeeBps  int        `json:"maker_fee_bps"`
+	TakerFeeBps  int        `json:"taker_fee_bps"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+type UpdateScheduleRequest struct {
+	MakerFeeBps *int `json:"maker_fee_bps" validate:"required,min=0,max=10000"`
+	TakerFeeBps *int `json:"taker_fee_bps" validate:"required,min=0,max=10000"`
+}
+
+// OverrideDTO is an account's fee override. A nil rate means the account
+// pays the instrument's rate for that side.
+type OverrideDTO struct {
+	AccountID   string     `json:"account_id"`
+	MakerFeeBps *int       `json:"maker_fee_bps"`
+	TakerFeeBps *int       `json:"taker_fee_bps"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+type UpdateOverrideRequest struct {
+	MakerFeeBps *int `json:"maker_fee_bps" validate:"omitempty,min=0,max=10000"`
+	TakerFeeBps *int `json:"taker_fee_bps" validate:"omitempty,min=0,max=10000"`
+}
+
+// TierDTO is a fee tier. A nil rate leaves that side uncapped.
+type TierDTO struct {
+	Level       int       `json:"level"`
+	Name        string    `json:"name"`
+	MinVolume   big.Float `json:"min_volume"`
+	MakerFeeBps *int      `json:"maker_fee_bps"`
+	TakerFeeBps *int      `json:"taker_fee_bps"`
+}
+
+type AccountTierDTO struct {
+	AccountID        string     `json:"account_id"`
+	ReferenceAsset   string     `json:"reference_asset"`
+	Volume30d        big.Float  `json:"volume_30d"`
+	Tier             TierDTO    `json:"tier"`
+	NextTier         *TierDTO   `json:"next_tier,omitempty"`
+	VolumeToNextTier *big.Float `json:"volume_to_next_tier,omitempty"`
+	ProgressPct      float64    `json:"progress_pct"`
+	ComputedAt       *time.Time `json:"computed_at,omitempty"`
+}
+
+func (r *UpdateScheduleRequest) Validate() error {
+	return validator.New().Struct(r)
+}
+
+func (r *UpdateOverrideRequest) Validate() error {
+	if err := validator.New().Struct(r); err != nil {
+		return err
+	}
+	if r.MakerFeeBps == nil && r.TakerFeeBps == nil {
+		return errors.New("maker_fee_bps or taker_fee_bps is required")
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package dto_test
+
+import (
+	"testing"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/fee/domain/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestUpdateScheduleRequest_Validate_Valid(t *testing.T) {
+	req := &dto.UpdateScheduleRequest{MakerFeeBps: intPtr(0), TakerFeeBps: intPtr(25)}
+	err := req.Validate()
+	assert.NoError(t, err)
+}
+
+func TestUpdateScheduleRequest_Validate_Missing(t *testing.T) {
+	req := &dto.UpdateScheduleRequest{MakerFeeBps: intPtr(10)}
+	err := req.Validate()
+	assert.Error(t, err)
+}
+
+func TestUpdateScheduleRequest_Validate_OutOfRange(t *testing.T) {
+	req := &dto.UpdateScheduleRequest{MakerFeeBps: intPtr(10), TakerFeeBps: intPtr(10001)}
+	err := req.Validate()
+	assert.Error(t, err)
+}
+
+func TestUpdateOverrideRequest_Validate_OneSide(t *testing.T) {
+	req := &dto.UpdateOverrideRequest{TakerFeeBps: intPtr(5)}
+	err := req.Validate()
+	assert.NoError(t, err)
+}
+
+func TestUpdateOverrideRequest_Validate_Empty(t *testing.T) {
+	req := &dto.UpdateOverrideRequest{}
+	err := req.Validate()
+	assert.EqualError(t, err, "maker_fee_bps or taker_fee_bps is required")
+}
+
+func TestUpdateOverrideRequest_Validate_Negative(t *testing.T) {
+	req := &dto.UpdateOverrideRequest{MakerFeeBps: intPtr(-1)}
+	err := req.Validate()
+	assert.Error(t, err)
+}
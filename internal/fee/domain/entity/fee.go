@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/fee/domain/dto"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/decimal"
+)
+
+// basisPoints is the number of basis points in a whole.
+const basisPoints = 10000
+
+// Schedule holds the maker and taker fee rates of an instrument, in basis
+// points. Instruments without a schedule charge no fees.
+type Schedule struct {
+	InstrumentID string
+	MakerFeeBps  int
+	TakerFeeBps  int
+	UpdatedAt    time.Time
+}
+
+// Override replaces an instrument's rates for a single account, on every
+// instrument. A nil rate keeps the instrument's rate for that side.
+type Override struct {
+	AccountID   string
+	MakerFeeBps *int
+	TakerFeeBps *int
+	UpdatedAt   time.Time
+}
+
+// Rates are the fee rates an account pays on an instrument, in basis points.
+type Rates struct {
+	MakerBps int
+	TakerBps int
+}
+
+func (s Schedule) Rates() Rates {
+	return Rates{MakerBps: s.MakerFeeBps, TakerBps: s.TakerFeeBps}
+}
+
+// WithOverride returns the rates with the account's override applied.
+func (r Rates) WithOverride(override Override) Rates {
+	if override.MakerFeeBps != nil {
+		r.MakerBps = *override.MakerFeeBps
+	}
+	if override.TakerFeeBps != nil {
+		r.TakerBps = *override.TakerFeeBps
+	}
+	return r
+}
+
+// Charge returns the fee of bps basis points on amount, rounded to the scale
+// balances are stored with.
+func Charge(amount *big.Float, bps int) *big.Float {
+	if bps <= 0 || amount == nil {
+		return new(big.Float)
+	}
+	fee := new(big.Float).Mul(amount, big.NewFloat(float64(bps)))
+	fee.Quo(fee, big.NewFloat(basisPoints))
+	fee = decimal.Round(fee, decimal.AmountScale)
+	return fee
+}
+
+func (s Schedule) ToDTO() dto.ScheduleDTO {
+	schedule := dto.ScheduleDTO{
+		InstrumentID: s.InstrumentID,
+		MakerFeeBps:  s.MakerFeeBps,
+		TakerFeeBps:  s.TakerFeeBps,
+	}
+	if !s.UpdatedAt.IsZero() {
+		schedule.UpdatedAt = &s.UpdatedAt
+	}
+	return schedule
+}
+
+func (o Override) ToDTO() dto.OverrideDTO {
+	override := dto.OverrideDTO{
+		AccountID:   o.AccountID,
+		MakerFeeBps: o.MakerFeeBps,
+		TakerFeeBps: o.TakerFeeBps,
+	}
+	if !o.UpdatedAt.IsZero() {
+		override.UpdatedAt = &o.UpdatedAt
+	}
+	return override
+}
+
+func ToSchedule(instrumentID string, request dto.UpdateScheduleRequest) (Schedule, error) {
+	if err := request.Validate(); err != nil {
+		return Schedule{}, err
+	}
+	return Schedule{
+		InstrumentID: instrumentID,
+		MakerFeeBps:  *request.MakerFeeBps,
+		TakerFeeBps:  *request.TakerFeeBps,
+	}, nil
+}
+
+func ToOverride(accountID string, request dto.UpdateOverrideRequest) (Override, error) {
+	if err := request.Validate(); err != nil {
+		return Override{}, err
+	}
+	return Override{
+		AccountID:   accountID,
+		MakerFeeBps: request.MakerFeeBps,
+		TakerFeeBps: request.TakerFeeBps,
+	}, nil
+}
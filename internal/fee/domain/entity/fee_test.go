@@ -0,0 +1,72 @@
+package entity_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/fee/domain/dto"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/fee/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestCharge(t *testing.T) {
+	// arrange
+	amount := big.NewFloat(1500)
+
+	// act
+	fee := entity.Charge(amount, 25)
+
+	// assert
+	assert.Equal(t, "3.75", fee.Text('f', 2))
+	assert.Equal(t, "1500", amount.Text('f', 0))
+}
+
+func TestCharge_ZeroRate(t *testing.T) {
+	// act
+	fee := entity.Charge(big.NewFloat(1500), 0)
+
+	// assert
+	assert.Zero(t, fee.Sign())
+}
+
+func TestRates_WithOverride(t *testing.T) {
+	// arrange
+	schedule := entity.Schedule{InstrumentID: "inst-1", MakerFeeBps: 10, TakerFeeBps: 20}
+	override := entity.Override{AccountID: "acc-1", TakerFeeBps: intPtr(5)}
+
+	// act
+	rates := schedule.Rates().WithOverride(override)
+
+	// assert
+	assert.Equal(t, entity.Rates{MakerBps: 10, TakerBps: 5}, rates)
+}
+
+func TestToSchedule(t *testing.T) {
+	// arrange
+	request := dto.UpdateScheduleRequest{MakerFeeBps: intPtr(10), TakerFeeBps: intPtr(20)}
+
+	// act
+	schedule, err := entity.ToSchedule("inst-1", request)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "inst-1", schedule.InstrumentID)
+	assert.Equal(t, 10, schedule.MakerFeeBps)
+	assert.Equal(t, 20, schedule.TakerFeeBps)
+}
+
+func TestSchedule_ToDTO(t *testing.T) {
+	// arrange
+	schedule := entity.Schedule{InstrumentID: "inst-1", MakerFeeBps: 10, TakerFeeBps: 20}
+
+	// act
+	result := schedule.ToDTO()
+
+	// assert
+	assert.Equal(t, "inst-1", result.InstrumentID)
+	assert.Equal(t, 10, result.MakerFeeBps)
+	assert.Equal(t, 20, result.TakerFeeBps)
+	assert.Nil(t, result.UpdatedAt)
+}
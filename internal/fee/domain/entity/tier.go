@@ -0,0 +1,131 @@
+package entity
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/fee/domain/dto"
+)
+
+// VolumeWindow is how far back an account's traded volume counts towards its
+// fee tier.
+const VolumeWindow = 30 * 24 * time.Hour
+
+// Tier is a fee level reached by accounts whose rolling 30-day volume is at
+// least MinVolume, in the reference quote asset. Its rates cap the rates of
+// every instrument; a nil rate leaves that side uncapped.
+type Tier struct {
+	Level       int
+	Name        string
+	MinVolume   *big.Float
+	MakerFeeBps *int
+	TakerFeeBps *int
+}
+
+// AccountVolume is an account's rolling 30-day traded notional as of the last
+// tier computation.
+type AccountVolume struct {
+	AccountID  string
+	Volume     *big.Float
+	ComputedAt time.Time
+}
+
+// CappedBy returns the rates limited to the tier's rates.
+func (r Rates) CappedBy(tier Tier) Rates {
+	if tier.MakerFeeBps != nil && *tier.MakerFeeBps < r.MakerBps {
+		r.MakerBps = *tier.MakerFeeBps
+	}
+	if tier.TakerFeeBps != nil && *tier.TakerFeeBps < r.TakerBps {
+		r.TakerBps = *tier.TakerFeeBps
+	}
+	return r
+}
+
+// TierFor returns the highest tier volume reaches and the tier after it, nil
+// when volume already reached the last one. tiers must not be empty.
+func TierFor(tiers []Tier, volume *big.Float) (Tier, *Tier) {
+	sorted := make([]Tier, len(tiers))
+	copy(sorted, tiers)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].MinVolume.Cmp(sorted[j].MinVolume) < 0
+	})
+
+	current := 0
+	for i, tier := range sorted {
+		if volume.Cmp(tier.MinVolume) >= 0 {
+			current = i
+		}
+	}
+	if current+1 < len(sorted) {
+		return sorted[current], &sorted[current+1]
+	}
+	return sorted[current], nil
+}
+
+// Normalize adds up the notional an account traded in each quote asset,
+// converted to the reference asset at prices. Assets without a price are
+// skipped and returned.
+func Normalize(notionals map[string]*big.Float, prices map[string]*big.Float) (*big.Float, []string) {
+	volume := new(big.Float)
+	var skipped []string
+	for asset, notional := range notionals {
+		price, ok := prices[asset]
+		if !ok {
+			skipped = append(skipped, asset)
+			continue
+		}
+		volume.Add(volume, new(big.Float).Mul(notional, price))
+	}
+	sort.Strings(skipped)
+	return volume, skipped
+}
+
+func (t Tier) ToDTO() dto.TierDTO {
+	return dto.TierDTO{
+		Level:       t.Level,
+		Name:        t.Name,
+		MinVolume:   *t.MinVolume,
+		MakerFeeBps: t.MakerFeeBps,
+		TakerFeeBps: t.TakerFeeBps,
+	}
+}
+
+func ToTierListDTO(tiers []Tier) []dto.TierDTO {
+	dtos := make([]dto.TierDTO, len(tiers))
+	for i, t := range tiers {
+		dtos[i] = t.ToDTO()
+	}
+	return dtos
+}
+
+// ToAccountTierDTO reports where the account stands in the tiers: its
+// current tier and, unless it reached the last one, how much volume is left
+// to the next and how far along it is, in percent.
+func (v AccountVolume) ToAccountTierDTO(tiers []Tier, referenceAsset string) dto.AccountTierDTO {
+	current, next := TierFor(tiers, v.Volume)
+	result := dto.AccountTierDTO{
+		AccountID:      v.AccountID,
+		ReferenceAsset: referenceAsset,
+		Volume30d:      *v.Volume,
+		Tier:           current.ToDTO(),
+	}
+	if !v.ComputedAt.IsZero() {
+		result.ComputedAt = &v.ComputedAt
+	}
+	if next == nil {
+		result.ProgressPct = 100
+		return result
+	}
+
+	nextTier := next.ToDTO()
+	result.NextTier = &nextTier
+	remaining := new(big.Float).Sub(next.MinVolume, v.Volume)
+	result.VolumeToNextTier = remaining
+
+	span := new(big.Float).Sub(next.MinVolume, current.MinVolume)
+	progressed := new(big.Float).Sub(v.Volume, current.MinVolume)
+	pct, _ := new(big.Float).Quo(new(big.Float).Mul(progressed, big.NewFloat(100)), span).Float64()
+	result.ProgressPct = pct
+	return result
+}
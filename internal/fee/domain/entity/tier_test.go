@@ -0,0 +1,100 @@
+package entity_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/fee/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTiers() []entity.Tier {
+	return []entity.Tier{
+		{Level: 1, Name: "VIP 1", MinVolume: big.NewFloat(1000), MakerFeeBps: intPtr(8), TakerFeeBps: intPtr(10)},
+		{Level: 0, Name: "Regular", MinVolume: big.NewFloat(0)},
+		{Level: 2, Name: "VIP 2", MinVolume: big.NewFloat(5000), MakerFeeBps: intPtr(6), TakerFeeBps: intPtr(8)},
+	}
+}
+
+func TestTierFor(t *testing.T) {
+	// act
+	current, next := entity.TierFor(newTiers(), big.NewFloat(1200))
+
+	// assert
+	assert.Equal(t, 1, current.Level)
+	assert.Equal(t, 2, next.Level)
+}
+
+func TestTierFor_LastTier(t *testing.T) {
+	// act
+	current, next := entity.TierFor(newTiers(), big.NewFloat(9000))
+
+	// assert
+	assert.Equal(t, 2, current.Level)
+	assert.Nil(t, next)
+}
+
+func TestRates_CappedBy(t *testing.T) {
+	// arrange
+	rates := entity.Rates{MakerBps: 5, TakerBps: 20}
+	tier := entity.Tier{MakerFeeBps: intPtr(8), TakerFeeBps: intPtr(10)}
+
+	// act
+	capped := rates.CappedBy(tier)
+	uncapped := rates.CappedBy(entity.Tier{})
+
+	// assert
+	assert.Equal(t, entity.Rates{MakerBps: 5, TakerBps: 10}, capped)
+	assert.Equal(t, rates, uncapped)
+}
+
+func TestNormalize(t *testing.T) {
+	// arrange
+	notionals := map[string]*big.Float{
+		"USDT": big.NewFloat(100),
+		"BRL":  big.NewFloat(500),
+		"XYZ":  big.NewFloat(7),
+	}
+	prices := map[string]*big.Float{
+		"USDT": big.NewFloat(1),
+		"BRL":  big.NewFloat(0.2),
+	}
+
+	// act
+	volume, skipped := entity.Normalize(notionals, prices)
+
+	// assert
+	assert.Equal(t, "200", volume.Text('f', 0))
+	assert.Equal(t, []string{"XYZ"}, skipped)
+}
+
+func TestAccountVolume_ToAccountTierDTO(t *testing.T) {
+	// arrange
+	volume := entity.AccountVolume{AccountID: "acc-1", Volume: big.NewFloat(2000)}
+
+	// act
+	result := volume.ToAccountTierDTO(newTiers(), "USDT")
+
+	// assert
+	assert.Equal(t, "acc-1", result.AccountID)
+	assert.Equal(t, "USDT", result.ReferenceAsset)
+	assert.Equal(t, 1, result.Tier.Level)
+	assert.Equal(t, 2, result.NextTier.Level)
+	assert.Equal(t, "3000", result.VolumeToNextTier.Text('f', 0))
+	assert.InDelta(t, 25.0, result.ProgressPct, 0.0001)
+	assert.Nil(t, result.ComputedAt)
+}
+
+func TestAccountVolume_ToAccountTierDTO_LastTier(t *testing.T) {
+	// arrange
+	volume := entity.AccountVolume{AccountID: "acc-1", Volume: big.NewFloat(8000)}
+
+	// act
+	result := volume.ToAccountTierDTO(newTiers(), "USDT")
+
+	// assert
+	assert.Equal(t, 2, result.Tier.Level)
+	assert.Nil(t, result.NextTier)
+	assert.Nil(t, result.VolumeToNextTier)
+	assert.Equal(t, 100.0, result.ProgressPct)
+}
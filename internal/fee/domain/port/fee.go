@@ -0,0 +1,18 @@
+package port
+
+import (
+	"context"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/fee/domain/entity"
+)
+
+type FeeRepository interface {
+	FindSchedule(ctx context.Context, instrumentID string) (entity.Schedule, error)
+	SaveSchedule(ctx context.Context, schedule entity.Schedule) (entity.Schedule, error)
+	FindOverride(ctx context.Context, accountID string) (entity.Override, error)
+	SaveOverride(ctx context.Context, override entity.Override) (entity.Override, error)
+	DeleteOverride(ctx context.Context, accountID string) error
+	FindTiers(ctx context.Context) ([]entity.Tier, error)
+	FindAccountVolume(ctx context.Context, accountID string) (entity.AccountVolume, error)
+	ReplaceAccountVolumes(ctx context.Context, volumes []entity.AccountVolume) error
+}
@@ -16,6 +16,11 @@ type Instrument interface {
 	FindByID(c echo.Context) error
 	GetInstruments(c echo.Context) error
 	DeleteByID(c echo.Context) error
+	Halt(c echo.Context) error
+	Resume(c echo.Context) error
+	Delist(c echo.Context) error
+	StartAuction(c echo.Context) error
+	FindStatusHistory(c echo.Context) error
 	RegisterRoutes(g *echo.Group)
 }
 
@@ -35,11 +40,16 @@ func (h *instrument) RegisterRoutes(g *echo.Group) {
 	g.GET("", h.GetInstruments)
 	g.PUT("/:id", h.Update)
 	g.DELETE("/:id", h.DeleteByID)
+	g.POST("/:id/halt", h.Halt)
+	g.POST("/:id/resume", h.Resume)
+	g.POST("/:id/delist", h.Delist)
+	g.POST("/:id/auction", h.StartAuction)
+	g.GET("/:id/status-history", h.FindStatusHistory)
 }
 
 // Create godoc
 // @Summary      Cria um novo instrumento
-// @Description  Cria um novo instrumento financeiro
+// @Description  Cria um novo instrumento financeiro com regras de negociação opcionais: tick_size, lot_size, min_quantity, max_quantity e min_notional, e proteção de preço opcional: reference_price, price_band_percent e o circuit breaker (circuit_breaker_percent, circuit_breaker_window_seconds, circuit_breaker_auction_seconds). O status inicial é TRADING, a menos que seja informado PRE_OPEN ou POST_ONLY.
 // @Tags         instruments
 // @Accept       json
 // @Produce      json
@@ -59,6 +69,9 @@ func (h *instrument) Create(c echo.Context) error {
 
 	createdInstrument, err := h.instrumentApp.Create(c.Request().Context(), request)
 	if err != nil {
+		if errors.Is(err, ierr.ErrInvalidInput) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
 		if errors.Is(err, ierr.ErrConflict) {
 			return c.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
 		}
@@ -70,6 +83,7 @@ func (h *instrument) Create(c echo.Context) error {
 
 // Update godoc
 // @Summary      Atualiza um instrumento
+// @Description  Substitui os ativos e as regras de negociação do instrumento; uma regra omitida deixa de ser aplicada
 // @Tags         instruments
 // @Accept       json
 // @Produce      json
@@ -96,6 +110,9 @@ func (h *instrument) Update(c echo.Context) error {
 
 	updatedInstrument, err := h.instrumentApp.Update(c.Request().Context(), id, request)
 	if err != nil {
+		if errors.Is(err, ierr.ErrInvalidInput) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
 		if errors.Is(err, ierr.ErrNotFound) {
 			return c.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
 		}
@@ -139,12 +156,14 @@ func (h *instrument) FindByID(c echo.Context) error {
 // @Produce      json
 // @Param        base_asset   query   string  false  "Base Asset"
 // @Param        quote_asset  query   string  false  "Quote Asset"
+// @Param        status       query   string  false  "Status"
 // @Success      200  {array}   dto.InstrumentListDTO
 // @Router       /v1/instruments [get]
 func (h *instrument) GetInstruments(c echo.Context) error {
 	filter := dto.InstrumentFilter{
 		BaseAsset:  c.QueryParam("base_asset"),
 		QuoteAsset: c.QueryParam("quote_asset"),
+		Status:     c.QueryParam("status"),
 	}
 
 	instruments, err := h.instrumentApp.GetInstruments(c.Request().Context(), filter)
@@ -157,6 +176,7 @@ func (h *instrument) GetInstruments(c echo.Context) error {
 
 // DeleteByID godoc
 // @Summary      Deleta um instrumento
+// @Description  Remove o instrumento definitivamente. Para instrumentos que já tiveram ordens use /delist.
 // @Tags         instruments
 // @Produce      json
 // @Param        id   path      string  true  "Instrument ID"
@@ -178,3 +198,171 @@ func (h *instrument) DeleteByID(c echo.Context) error {
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+// Halt godoc
+// @Summary      Suspende a negociação de um instrumento
+// @Description  Coloca o instrumento em HALTED: o livro é mantido e apenas cancelamentos são aceitos até a retomada. A mudança fica registrada no histórico com o motivo.
+// @Tags         instruments
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string             true  "Instrument ID"
+// @Param        request  body      dto.StatusRequest  true  "Reason"
+// @Success      200  {object}  dto.InstrumentDTO
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string "instrument cannot be halted from its current status"
+// @Router       /v1/instruments/{id}/halt [post]
+func (h *instrument) Halt(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "instrument ID cannot be empty")
+	}
+
+	var request dto.StatusRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	instrument, err := h.instrumentApp.Halt(c.Request().Context(), id, request)
+	if err != nil {
+		return statusError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, instrument)
+}
+
+// Resume godoc
+// @Summary      Retoma a negociação de um instrumento
+// @Description  Reabre um instrumento HALTED ou PRE_OPEN em TRADING ou, se informado, em POST_ONLY. A mudança fica registrada no histórico com o motivo.
+// @Tags         instruments
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string             true  "Instrument ID"
+// @Param        request  body      dto.ResumeRequest  true  "Reason and status"
+// @Success      200  {object}  dto.InstrumentDTO
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string "instrument is not halted"
+// @Router       /v1/instruments/{id}/resume [post]
+func (h *instrument) Resume(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "instrument ID cannot be empty")
+	}
+
+	var request dto.ResumeRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	instrument, err := h.instrumentApp.Resume(c.Request().Context(), id, request)
+	if err != nil {
+		return statusError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, instrument)
+}
+
+// Delist godoc
+// @Summary      Deslista um instrumento
+// @Description  Coloca o instrumento em DELISTED de forma definitiva e cancela as ordens ainda ativas. Diferente do DELETE, o instrumento e o histórico de ordens e trades são mantidos.
+// @Tags         instruments
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string             true  "Instrument ID"
+// @Param        request  body      dto.StatusRequest  true  "Reason"
+// @Success      200  {object}  dto.InstrumentDTO
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string "instrument is already delisted"
+// @Router       /v1/instruments/{id}/delist [post]
+func (h *instrument) Delist(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "instrument ID cannot be empty")
+	}
+
+	var request dto.StatusRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	instrument, err := h.instrumentApp.Delist(c.Request().Context(), id, request)
+	if err != nil {
+		return statusError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, instrument)
+}
+
+// StartAuction godoc
+// @Summary      Inicia um leilão para um instrumento
+// @Description  Coloca o instrumento em AUCTION por duration_seconds: ordens limit GTC ou GTD são acumuladas sem casar e o preço e o volume indicativos aparecem em /v1/book. Ao final, o livro é cruzado a um único preço que maximiza o volume executado e o instrumento volta a TRADING.
+// @Tags         instruments
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string              true  "Instrument ID"
+// @Param        request  body      dto.AuctionRequest  true  "Reason and duration"
+// @Success      200  {object}  dto.InstrumentDTO
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string "instrument is delisted or already in an auction"
+// @Router       /v1/instruments/{id}/auction [post]
+func (h *instrument) StartAuction(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "instrument ID cannot be empty")
+	}
+
+	var request dto.AuctionRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	instrument, err := h.instrumentApp.StartAuction(c.Request().Context(), id, request)
+	if err != nil {
+		return statusError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, instrument)
+}
+
+// FindStatusHistory godoc
+// @Summary      Lista o histórico de status de um instrumento
+// @Description  Lista as mudanças de status do instrumento com os motivos, da mais antiga para a mais recente
+// @Tags         instruments
+// @Produce      json
+// @Param        id   path      string  true  "Instrument ID"
+// @Success      200  {array}   dto.StatusChangeDTO
+// @Failure      404  {object}  map[string]string
+// @Router       /v1/instruments/{id}/status-history [get]
+func (h *instrument) FindStatusHistory(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "instrument ID cannot be empty")
+	}
+
+	history, err := h.instrumentApp.FindStatusHistory(c.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "internal server error"})
+	}
+
+	return c.JSON(http.StatusOK, history)
+}
+
+// statusError maps the errors of a status change to a response.
+func statusError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, ierr.ErrInvalidInput):
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	case errors.Is(err, ierr.ErrNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+	case errors.Is(err, ierr.ErrConflict):
+		return c.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "internal server error"})
+	}
+}
@@ -1,35 +1,109 @@
 package repository
 
 import (
+	"math/big"
 	"time"
 
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/domain/entity"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/decimal"
 )
 
 type InstrumentModel struct {
-	ID         string    `json:"id"`
-	BaseAsset  string    `json:"base_asset"`
-	QuoteAsset string    `json:"quote_asset"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID                           string     `json:"id"`
+	BaseAsset                    string     `json:"base_asset"`
+	QuoteAsset                   string     `json:"quote_asset"`
+	Status                       string     `json:"status"`
+	AuctionEndsAt                *time.Time `json:"auction_ends_at"`
+	TickSize                     *string    `json:"tick_size"`
+	LotSize                      *string    `json:"lot_size"`
+	MinQuantity                  *string    `json:"min_quantity"`
+	MaxQuantity                  *string    `json:"max_quantity"`
+	MinNotional                  *string    `json:"min_notional"`
+	ReferencePrice               *string    `json:"reference_price"`
+	PriceBandPercent             *string    `json:"price_band_percent"`
+	CircuitBreakerPercent        *string    `json:"circuit_breaker_percent"`
+	CircuitBreakerWindowSeconds  int        `json:"circuit_breaker_window_seconds"`
+	CircuitBreakerAuctionSeconds int        `json:"circuit_breaker_auction_seconds"`
+	CreatedAt                    time.Time  `json:"created_at"`
+	UpdatedAt                    time.Time  `json:"updated_at"`
 }
 
 func ToModel(instrument *entity.Instrument) *InstrumentModel {
 	return &InstrumentModel{
-		ID:         instrument.ID,
-		BaseAsset:  instrument.BaseAsset,
-		QuoteAsset: instrument.QuoteAsset,
-		CreatedAt:  instrument.CreatedAt,
-		UpdatedAt:  instrument.UpdatedAt,
+		ID:                           instrument.ID,
+		BaseAsset:                    instrument.BaseAsset,
+		QuoteAsset:                   instrument.QuoteAsset,
+		Status:                       string(instrument.Status),
+		AuctionEndsAt:                instrument.AuctionEndsAt,
+		TickSize:                     formatOptional(instrument.TickSize),
+		LotSize:                      formatOptional(instrument.LotSize),
+		MinQuantity:                  formatOptional(instrument.MinQuantity),
+		MaxQuantity:                  formatOptional(instrument.MaxQuantity),
+		MinNotional:                  formatOptional(instrument.MinNotional),
+		ReferencePrice:               formatOptional(instrument.ReferencePrice),
+		PriceBandPercent:             formatOptional(instrument.PriceBandPercent),
+		CircuitBreakerPercent:        formatOptional(instrument.CircuitBreakerPercent),
+		CircuitBreakerWindowSeconds:  int(instrument.CircuitBreakerWindow / time.Second),
+		CircuitBreakerAuctionSeconds: int(instrument.CircuitBreakerAuction / time.Second),
+		CreatedAt:                    instrument.CreatedAt,
+		UpdatedAt:                    instrument.UpdatedAt,
 	}
 }
 
 func ToEntity(model *InstrumentModel) *entity.Instrument {
 	return &entity.Instrument{
-		ID:         model.ID,
-		BaseAsset:  model.BaseAsset,
-		QuoteAsset: model.QuoteAsset,
-		CreatedAt:  model.CreatedAt,
-		UpdatedAt:  model.UpdatedAt,
+		ID:            model.ID,
+		BaseAsset:     model.BaseAsset,
+		QuoteAsset:    model.QuoteAsset,
+		Status:        entity.InstrumentStatus(model.Status),
+		AuctionEndsAt: model.AuctionEndsAt,
+		TradingRules: entity.TradingRules{
+			TickSize:    decimal.ParseOptional(model.TickSize),
+			LotSize:     decimal.ParseOptional(model.LotSize),
+			MinQuantity: decimal.ParseOptional(model.MinQuantity),
+			MaxQuantity: decimal.ParseOptional(model.MaxQuantity),
+			MinNotional: decimal.ParseOptional(model.MinNotional),
+		},
+		PriceProtection: entity.PriceProtection{
+			ReferencePrice:        decimal.ParseOptional(model.ReferencePrice),
+			PriceBandPercent:      decimal.ParseOptional(model.PriceBandPercent),
+			CircuitBreakerPercent: decimal.ParseOptional(model.CircuitBreakerPercent),
+			CircuitBreakerWindow:  time.Duration(model.CircuitBreakerWindowSeconds) * time.Second,
+			CircuitBreakerAuction: time.Duration(model.CircuitBreakerAuctionSeconds) * time.Second,
+		},
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
 	}
 }
+
+// formatOptional renders a nullable decimal column; nil stays NULL.
+func formatOptional(value *big.Float) *string {
+	if value == nil {
+		return nil
+	}
+	s := value.Text('f', 18)
+	return &s
+}
+
+type StatusChangeModel struct {
+	ID           string    `json:"id"`
+	InstrumentID string    `json:"instrument_id"`
+	FromStatus   *string   `json:"from_status"`
+	ToStatus     string    `json:"to_status"`
+	Reason       string    `json:"reason"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (m *StatusChangeModel) ToEntity() entity.StatusChange {
+	change := entity.StatusChange{
+		ID:           m.ID,
+		InstrumentID: m.InstrumentID,
+		To:           entity.InstrumentStatus(m.ToStatus),
+		Reason:       m.Reason,
+		CreatedAt:    m.CreatedAt,
+	}
+	if m.FromStatus != nil {
+		change.From = entity.InstrumentStatus(*m.FromStatus)
+	}
+	return change
+}
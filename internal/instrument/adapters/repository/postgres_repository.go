@@ -8,11 +8,17 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/db"
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/domain/entity"
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/domain/port"
 	"github.com/mthpedrosa/financial-exchange-challenge/pkg/ierr"
 )
 
+// instrumentColumns is the column list every instrument query selects, in the
+// order scanTargets expects.
+const instrumentColumns = `id, base_asset, quote_asset, status, auction_ends_at, tick_size, lot_size, min_quantity, max_quantity, min_notional,
+    reference_price, price_band_percent, circuit_breaker_percent, circuit_breaker_window_seconds, circuit_breaker_auction_seconds, created_at, updated_at`
+
 type instrument struct {
 	db *pgxpool.Pool
 }
@@ -27,10 +33,26 @@ func (r *instrument) Create(ctx context.Context, instrument *entity.Instrument)
 	model := ToModel(instrument)
 	fmt.Print(instrument, "chequei até aqui repositoru ")
 
-	query := `INSERT INTO instruments (base_asset, quote_asset, created_at, updated_at) VALUES ($1, $2, NOW(), NOW()) RETURNING id`
+	query := `INSERT INTO instruments (base_asset, quote_asset, status, tick_size, lot_size, min_quantity, max_quantity, min_notional,
+            reference_price, price_band_percent, circuit_breaker_percent, circuit_breaker_window_seconds, circuit_breaker_auction_seconds, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NOW(), NOW()) RETURNING id`
 	var id string
 
-	err := r.db.QueryRow(ctx, query, model.BaseAsset, model.QuoteAsset).Scan(&id)
+	err := db.Conn(ctx, r.db).QueryRow(ctx, query,
+		model.BaseAsset,
+		model.QuoteAsset,
+		model.Status,
+		model.TickSize,
+		model.LotSize,
+		model.MinQuantity,
+		model.MaxQuantity,
+		model.MinNotional,
+		model.ReferencePrice,
+		model.PriceBandPercent,
+		model.CircuitBreakerPercent,
+		model.CircuitBreakerWindowSeconds,
+		model.CircuitBreakerAuctionSeconds,
+	).Scan(&id)
 	if err != nil {
 		return "", err
 	}
@@ -40,8 +62,26 @@ func (r *instrument) Create(ctx context.Context, instrument *entity.Instrument)
 func (r *instrument) Update(ctx context.Context, instrument *entity.Instrument) error {
 	model := ToModel(instrument)
 
-	query := `UPDATE instruments SET base_asset=$1, quote_asset=$2, updated_at=NOW() WHERE id=$3`
-	result, err := r.db.Exec(ctx, query, model.BaseAsset, model.QuoteAsset, model.ID)
+	query := `UPDATE instruments
+        SET base_asset=$1, quote_asset=$2, tick_size=$3, lot_size=$4, min_quantity=$5, max_quantity=$6, min_notional=$7,
+            reference_price=$8, price_band_percent=$9, circuit_breaker_percent=$10, circuit_breaker_window_seconds=$11,
+            circuit_breaker_auction_seconds=$12, updated_at=NOW()
+        WHERE id=$13`
+	result, err := r.db.Exec(ctx, query,
+		model.BaseAsset,
+		model.QuoteAsset,
+		model.TickSize,
+		model.LotSize,
+		model.MinQuantity,
+		model.MaxQuantity,
+		model.MinNotional,
+		model.ReferencePrice,
+		model.PriceBandPercent,
+		model.CircuitBreakerPercent,
+		model.CircuitBreakerWindowSeconds,
+		model.CircuitBreakerAuctionSeconds,
+		model.ID,
+	)
 	if err != nil {
 		return err
 	}
@@ -55,16 +95,10 @@ func (r *instrument) Update(ctx context.Context, instrument *entity.Instrument)
 }
 
 func (r *instrument) FindByID(ctx context.Context, id string) (*entity.Instrument, error) {
-	query := `SELECT id, base_asset, quote_asset, created_at, updated_at FROM instruments WHERE id = $1`
-
-	var acc entity.Instrument
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&acc.ID,
-		&acc.BaseAsset,
-		&acc.QuoteAsset,
-		&acc.CreatedAt,
-		&acc.UpdatedAt,
-	)
+	query := `SELECT ` + instrumentColumns + ` FROM instruments WHERE id = $1`
+
+	var model InstrumentModel
+	err := r.db.QueryRow(ctx, query, id).Scan(scanTargets(&model)...)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return &entity.Instrument{}, ierr.ErrNotFound
@@ -72,12 +106,12 @@ func (r *instrument) FindByID(ctx context.Context, id string) (*entity.Instrumen
 		return &entity.Instrument{}, err
 	}
 
-	return &acc, nil
+	return ToEntity(&model), nil
 }
 
 func (r *instrument) FindAll(ctx context.Context, filter *entity.InstrumentFilter) ([]*entity.Instrument, error) {
 	var queryBuilder strings.Builder
-	queryBuilder.WriteString("SELECT id, base_asset, quote_asset, created_at, updated_at FROM instruments WHERE 1=1")
+	queryBuilder.WriteString("SELECT " + instrumentColumns + " FROM instruments WHERE 1=1")
 
 	args := []interface{}{}
 	argID := 1
@@ -91,6 +125,11 @@ func (r *instrument) FindAll(ctx context.Context, filter *entity.InstrumentFilte
 	if filter.QuoteAsset != "" {
 		queryBuilder.WriteString(fmt.Sprintf(" AND quote_asset = $%d", argID))
 		args = append(args, filter.QuoteAsset)
+		argID++
+	}
+	if filter.Status != "" {
+		queryBuilder.WriteString(fmt.Sprintf(" AND status = $%d", argID))
+		args = append(args, string(filter.Status))
 	}
 
 	query := queryBuilder.String()
@@ -103,13 +142,7 @@ func (r *instrument) FindAll(ctx context.Context, filter *entity.InstrumentFilte
 	var instruments []*entity.Instrument
 	for rows.Next() {
 		var model InstrumentModel
-		if err := rows.Scan(
-			&model.ID,
-			&model.BaseAsset,
-			&model.QuoteAsset,
-			&model.CreatedAt,
-			&model.UpdatedAt,
-		); err != nil {
+		if err := rows.Scan(scanTargets(&model)...); err != nil {
 			return nil, err
 		}
 		instruments = append(instruments, ToEntity(&model))
@@ -139,18 +172,12 @@ func (r *instrument) DeleteByID(ctx context.Context, id string) error {
 
 func (r *instrument) FindByAssets(ctx context.Context, baseAsset, quoteAsset string) (*entity.Instrument, error) {
 	query := `
-        SELECT id, base_asset, quote_asset, created_at, updated_at 
+        SELECT ` + instrumentColumns + `
         FROM instruments 
         WHERE base_asset = $1 AND quote_asset = $2`
 
 	var model InstrumentModel
-	err := r.db.QueryRow(ctx, query, baseAsset, quoteAsset).Scan(
-		&model.ID,
-		&model.BaseAsset,
-		&model.QuoteAsset,
-		&model.CreatedAt,
-		&model.UpdatedAt,
-	)
+	err := r.db.QueryRow(ctx, query, baseAsset, quoteAsset).Scan(scanTargets(&model)...)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ierr.ErrNotFound
@@ -160,3 +187,84 @@ func (r *instrument) FindByAssets(ctx context.Context, baseAsset, quoteAsset str
 
 	return ToEntity(&model), nil
 }
+
+// UpdateStatus applies a status change to its instrument, along with the end
+// of the auction it starts, if any. It fails with ErrConflict when the
+// instrument is no longer in the change's from status, so two concurrent
+// changes cannot both apply.
+func (r *instrument) UpdateStatus(ctx context.Context, change entity.StatusChange) error {
+	query := `UPDATE instruments SET status = $3, auction_ends_at = $4, updated_at = NOW() WHERE id = $1 AND status = $2`
+	result, err := db.Conn(ctx, r.db).Exec(ctx, query, change.InstrumentID, string(change.From), string(change.To), change.AuctionEndsAt)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("instrument is no longer %s: %w", change.From, ierr.ErrConflict)
+	}
+	return nil
+}
+
+// CreateStatusChange appends a change to the status history of an instrument.
+func (r *instrument) CreateStatusChange(ctx context.Context, change entity.StatusChange) (entity.StatusChange, error) {
+	query := `INSERT INTO instrument_status_changes (instrument_id, from_status, to_status, reason)
+        VALUES ($1, $2, $3, $4) RETURNING id, created_at`
+	var from *string
+	if change.From != "" {
+		status := string(change.From)
+		from = &status
+	}
+	err := db.Conn(ctx, r.db).QueryRow(ctx, query, change.InstrumentID, from, string(change.To), change.Reason).
+		Scan(&change.ID, &change.CreatedAt)
+	if err != nil {
+		return entity.StatusChange{}, err
+	}
+	return change, nil
+}
+
+// FindStatusHistory returns the status changes of an instrument, oldest first.
+func (r *instrument) FindStatusHistory(ctx context.Context, id string) ([]entity.StatusChange, error) {
+	query := `SELECT id, instrument_id, from_status, to_status, reason, created_at
+        FROM instrument_status_changes
+        WHERE instrument_id = $1
+        ORDER BY created_at, id`
+	rows, err := db.Conn(ctx, r.db).Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []entity.StatusChange
+	for rows.Next() {
+		var m StatusChangeModel
+		if err := rows.Scan(&m.ID, &m.InstrumentID, &m.FromStatus, &m.ToStatus, &m.Reason, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, m.ToEntity())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func scanTargets(model *InstrumentModel) []any {
+	return []any{
+		&model.ID,
+		&model.BaseAsset,
+		&model.QuoteAsset,
+		&model.Status,
+		&model.AuctionEndsAt,
+		&model.TickSize,
+		&model.LotSize,
+		&model.MinQuantity,
+		&model.MaxQuantity,
+		&model.MinNotional,
+		&model.ReferencePrice,
+		&model.PriceBandPercent,
+		&model.CircuitBreakerPercent,
+		&model.CircuitBreakerWindowSeconds,
+		&model.CircuitBreakerAuctionSeconds,
+		&model.CreatedAt,
+		&model.UpdatedAt,
+	}
+}
@@ -3,35 +3,61 @@ package app
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"time"
 
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/db"
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/domain/dto"
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/domain/entity"
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/domain/port"
+	orderEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+	orderPort "github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/port"
 	"github.com/mthpedrosa/financial-exchange-challenge/pkg/ierr"
 )
 
+// listedReason is recorded as the reason of an instrument's first status.
+const listedReason = "instrument listed"
+
+// cancelTimeout bounds how long delisting waits for the engine to cancel the
+// instrument's orders.
+const cancelTimeout = 5 * time.Second
+
 type Instrument interface {
 	Create(ctx context.Context, request dto.CreateInstrumentRequest) (dto.CreateInstrumentResponse, error)
 	FindByID(ctx context.Context, id string) (dto.InstrumentDTO, error)
 	GetInstruments(ctx context.Context, filter dto.InstrumentFilter) ([]*entity.Instrument, error)
 	Update(ctx context.Context, id string, request dto.CreateInstrumentRequest) (dto.InstrumentDTO, error)
 	DeleteByID(ctx context.Context, id string) error
+	Halt(ctx context.Context, id string, request dto.StatusRequest) (dto.InstrumentDTO, error)
+	Resume(ctx context.Context, id string, request dto.ResumeRequest) (dto.InstrumentDTO, error)
+	Delist(ctx context.Context, id string, request dto.StatusRequest) (dto.InstrumentDTO, error)
+	StartAuction(ctx context.Context, id string, request dto.AuctionRequest) (dto.InstrumentDTO, error)
+	FindStatusHistory(ctx context.Context, id string) ([]dto.StatusChangeDTO, error)
 }
 
 type instrument struct {
 	instrumentPort port.InstrumentRepository
+	orderQueue     orderPort.OrderQueue
+	txManager      db.TxManager
 }
 
-func NewInstrumentApp(instrumentPort port.InstrumentRepository) Instrument {
+func NewInstrumentApp(
+	instrumentPort port.InstrumentRepository,
+	orderQueue orderPort.OrderQueue,
+	txManager db.TxManager,
+) Instrument {
 	return &instrument{
 		instrumentPort: instrumentPort,
+		orderQueue:     orderQueue,
+		txManager:      txManager,
 	}
 }
 
 func (i *instrument) Create(ctx context.Context, request dto.CreateInstrumentRequest) (dto.CreateInstrumentResponse, error) {
 	instrumentEntity, err := entity.ToEntity(request)
 	if err != nil {
-		return dto.CreateInstrumentResponse{}, ierr.ErrInvalidInput
+		return dto.CreateInstrumentResponse{}, fmt.Errorf("%s: %w", err.Error(), ierr.ErrInvalidInput)
 	}
 
 	// check duplicate
@@ -44,8 +70,22 @@ func (i *instrument) Create(ctx context.Context, request dto.CreateInstrumentReq
 		return dto.CreateInstrumentResponse{}, err
 	}
 
-	// create
-	createdInstrumentID, createErr := i.instrumentPort.Create(ctx, instrumentEntity)
+	// create it together with the first entry of its status history
+	var createdInstrumentID string
+	createErr := i.txManager.WithTx(ctx, func(ctx context.Context) error {
+		id, err := i.instrumentPort.Create(ctx, instrumentEntity)
+		if err != nil {
+			return err
+		}
+		createdInstrumentID = id
+
+		_, err = i.instrumentPort.CreateStatusChange(ctx, entity.StatusChange{
+			InstrumentID: id,
+			To:           instrumentEntity.Status,
+			Reason:       listedReason,
+		})
+		return err
+	})
 	if createErr != nil {
 		return dto.CreateInstrumentResponse{}, createErr
 	}
@@ -74,6 +114,15 @@ func (i *instrument) DeleteByID(ctx context.Context, id string) error {
 }
 
 func (i *instrument) Update(ctx context.Context, id string, request dto.CreateInstrumentRequest) (dto.InstrumentDTO, error) {
+	rules := entity.ToTradingRules(request)
+	if err := rules.Validate(); err != nil {
+		return dto.InstrumentDTO{}, fmt.Errorf("%s: %w", err.Error(), ierr.ErrInvalidInput)
+	}
+	protection := entity.ToPriceProtection(request)
+	if err := protection.Validate(); err != nil {
+		return dto.InstrumentDTO{}, fmt.Errorf("%s: %w", err.Error(), ierr.ErrInvalidInput)
+	}
+
 	// check duplicate
 	instrumentToUpdate, err := i.instrumentPort.FindByID(ctx, id)
 	if err != nil {
@@ -83,6 +132,8 @@ func (i *instrument) Update(ctx context.Context, id string, request dto.CreateIn
 	// update fields
 	instrumentToUpdate.BaseAsset = request.BaseAsset
 	instrumentToUpdate.QuoteAsset = request.QuoteAsset
+	instrumentToUpdate.TradingRules = rules
+	instrumentToUpdate.PriceProtection = protection
 
 	if err := i.instrumentPort.Update(ctx, instrumentToUpdate); err != nil {
 		return dto.InstrumentDTO{}, err
@@ -90,3 +141,135 @@ func (i *instrument) Update(ctx context.Context, id string, request dto.CreateIn
 
 	return instrumentToUpdate.ToDTO(), nil
 }
+
+// Halt stops trading on an instrument. Its book is kept and only cancels are
+// accepted until it is resumed.
+func (i *instrument) Halt(ctx context.Context, id string, request dto.StatusRequest) (dto.InstrumentDTO, error) {
+	if err := request.Validate(); err != nil {
+		return dto.InstrumentDTO{}, fmt.Errorf("%s: %w", err.Error(), ierr.ErrInvalidInput)
+	}
+
+	found, err := i.instrumentPort.FindByID(ctx, id)
+	if err != nil {
+		return dto.InstrumentDTO{}, err
+	}
+	change, err := found.Halt(request.Reason)
+	if err != nil {
+		return dto.InstrumentDTO{}, err
+	}
+	return i.changeStatus(ctx, found, change)
+}
+
+// Resume opens a halted or pre-open instrument, to TRADING unless the request
+// asks for POST_ONLY.
+func (i *instrument) Resume(ctx context.Context, id string, request dto.ResumeRequest) (dto.InstrumentDTO, error) {
+	if err := request.Validate(); err != nil {
+		return dto.InstrumentDTO{}, fmt.Errorf("%s: %w", err.Error(), ierr.ErrInvalidInput)
+	}
+
+	found, err := i.instrumentPort.FindByID(ctx, id)
+	if err != nil {
+		return dto.InstrumentDTO{}, err
+	}
+	to := entity.StatusTrading
+	if request.Status != "" {
+		to = entity.InstrumentStatus(request.Status)
+	}
+	change, err := found.Resume(to, request.Reason)
+	if err != nil {
+		return dto.InstrumentDTO{}, err
+	}
+	return i.changeStatus(ctx, found, change)
+}
+
+// Delist retires an instrument for good and asks the engine to cancel every
+// order still working on it. Unlike DeleteByID, the instrument and its order
+// and trade history are kept.
+func (i *instrument) Delist(ctx context.Context, id string, request dto.StatusRequest) (dto.InstrumentDTO, error) {
+	if err := request.Validate(); err != nil {
+		return dto.InstrumentDTO{}, fmt.Errorf("%s: %w", err.Error(), ierr.ErrInvalidInput)
+	}
+
+	found, err := i.instrumentPort.FindByID(ctx, id)
+	if err != nil {
+		return dto.InstrumentDTO{}, err
+	}
+	change, err := found.Transition(entity.StatusDelisted, request.Reason)
+	if err != nil {
+		return dto.InstrumentDTO{}, err
+	}
+	delisted, err := i.changeStatus(ctx, found, change)
+	if err != nil {
+		return dto.InstrumentDTO{}, err
+	}
+
+	// the instrument no longer accepts orders, so nothing new can slip in
+	ctx, cancel := context.WithTimeout(ctx, cancelTimeout)
+	defer cancel()
+	ids, err := i.orderQueue.PublishCancelAll(ctx, orderEntity.CancelFilter{InstrumentID: id})
+	if err != nil {
+		slog.Error("error cancelling orders of delisted instrument", "instrument_id", id, "error", err)
+	} else {
+		slog.Info("orders of delisted instrument cancelled", "instrument_id", id, "count", len(ids))
+	}
+	return delisted, nil
+}
+
+// StartAuction puts an instrument in an auction: orders collect without
+// matching until the duration elapses and the engine uncrosses the book at a
+// single price, after which the instrument trades.
+func (i *instrument) StartAuction(ctx context.Context, id string, request dto.AuctionRequest) (dto.InstrumentDTO, error) {
+	if err := request.Validate(); err != nil {
+		return dto.InstrumentDTO{}, fmt.Errorf("%s: %w", err.Error(), ierr.ErrInvalidInput)
+	}
+
+	found, err := i.instrumentPort.FindByID(ctx, id)
+	if err != nil {
+		return dto.InstrumentDTO{}, err
+	}
+	endsAt := time.Now().Add(time.Duration(request.DurationSeconds) * time.Second)
+	change, err := found.StartAuction(endsAt, request.Reason)
+	if err != nil {
+		return dto.InstrumentDTO{}, err
+	}
+	return i.changeStatus(ctx, found, change)
+}
+
+// FindStatusHistory returns every status change of an instrument, oldest
+// first.
+func (i *instrument) FindStatusHistory(ctx context.Context, id string) ([]dto.StatusChangeDTO, error) {
+	if _, err := i.instrumentPort.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	changes, err := i.instrumentPort.FindStatusHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return entity.ToStatusHistoryDTO(changes), nil
+}
+
+// changeStatus applies a status change and records it in the instrument's
+// history in the same transaction.
+func (i *instrument) changeStatus(ctx context.Context, found *entity.Instrument, change entity.StatusChange) (dto.InstrumentDTO, error) {
+	err := i.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := i.instrumentPort.UpdateStatus(ctx, change); err != nil {
+			return err
+		}
+		_, err := i.instrumentPort.CreateStatusChange(ctx, change)
+		return err
+	})
+	if err != nil {
+		return dto.InstrumentDTO{}, err
+	}
+
+	slog.Info("instrument status changed",
+		"instrument_id", found.ID,
+		"from", change.From,
+		"to", change.To,
+		"reason", change.Reason,
+	)
+	found.Status = change.To
+	found.AuctionEndsAt = change.AuctionEndsAt
+	return found.ToDTO(), nil
+}
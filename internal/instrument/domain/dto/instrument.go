@@ -1,22 +1,56 @@
 package dto
 
 import (
+	"math/big"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 )
 
 type InstrumentDTO struct {
-	ID         string    `json:"id"`
-	BaseAsset  string    `json:"base_asset"`
-	QuoteAsset string    `json:"quote_asset"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID                           string     `json:"id"`
+	BaseAsset                    string     `json:"base_asset"`
+	QuoteAsset                   string     `json:"quote_asset"`
+	Status                       string     `json:"status"`
+	AuctionEndsAt                *time.Time `json:"auction_ends_at,omitempty"`
+	TickSize                     *big.Float `json:"tick_size,omitempty"`
+	LotSize                      *big.Float `json:"lot_size,omitempty"`
+	MinQuantity                  *big.Float `json:"min_quantity,omitempty"`
+	MaxQuantity                  *big.Float `json:"max_quantity,omitempty"`
+	MinNotional                  *big.Float `json:"min_notional,omitempty"`
+	ReferencePrice               *big.Float `json:"reference_price,omitempty"`
+	PriceBandPercent             *big.Float `json:"price_band_percent,omitempty"`
+	CircuitBreakerPercent        *big.Float `json:"circuit_breaker_percent,omitempty"`
+	CircuitBreakerWindowSeconds  int        `json:"circuit_breaker_window_seconds,omitempty"`
+	CircuitBreakerAuctionSeconds int        `json:"circuit_breaker_auction_seconds,omitempty"`
+	CreatedAt                    time.Time  `json:"created_at"`
+	UpdatedAt                    time.Time  `json:"updated_at"`
 }
 
+// CreateInstrumentRequest creates or replaces an instrument. The trading
+// rules are optional decimal strings (e.g. "0.01"); a rule left out is not
+// enforced. Status is the status a new instrument is listed with, TRADING by
+// default; updates leave the status alone.
+//
+// The price protection is optional too. Limit prices more than
+// PriceBandPercent away from the last trade price, or ReferencePrice before
+// the first trade, are rejected. A move of more than CircuitBreakerPercent
+// within CircuitBreakerWindowSeconds pauses trading: an auction of
+// CircuitBreakerAuctionSeconds, or a halt when it is left out.
 type CreateInstrumentRequest struct {
-	BaseAsset  string `json:"base_asset" validate:"required"`
-	QuoteAsset string `json:"quote_asset" validate:"required"`
+	BaseAsset                    string     `json:"base_asset" validate:"required"`
+	QuoteAsset                   string     `json:"quote_asset" validate:"required"`
+	Status                       string     `json:"status,omitempty" validate:"omitempty,oneof=PRE_OPEN TRADING POST_ONLY"`
+	TickSize                     *big.Float `json:"tick_size,omitempty"`
+	LotSize                      *big.Float `json:"lot_size,omitempty"`
+	MinQuantity                  *big.Float `json:"min_quantity,omitempty"`
+	MaxQuantity                  *big.Float `json:"max_quantity,omitempty"`
+	MinNotional                  *big.Float `json:"min_notional,omitempty"`
+	ReferencePrice               *big.Float `json:"reference_price,omitempty"`
+	PriceBandPercent             *big.Float `json:"price_band_percent,omitempty"`
+	CircuitBreakerPercent        *big.Float `json:"circuit_breaker_percent,omitempty"`
+	CircuitBreakerWindowSeconds  int        `json:"circuit_breaker_window_seconds,omitempty" validate:"min=0,max=86400"`
+	CircuitBreakerAuctionSeconds int        `json:"circuit_breaker_auction_seconds,omitempty" validate:"min=0,max=3600"`
 }
 
 type CreateInstrumentResponse struct {
@@ -26,14 +60,58 @@ type CreateInstrumentResponse struct {
 type InstrumentFilter struct {
 	BaseAsset  string `query:"base_asset"`
 	QuoteAsset string `query:"quote_asset"`
+	Status     string `query:"status"`
 }
 
 type InstrumentListDTO struct {
 	ID         string `json:"id"`
 	BaseAsset  string `json:"base_asset"`
 	QuoteAsset string `json:"quote_asset"`
+	Status     string `json:"status"`
+}
+
+// StatusRequest halts or delists an instrument. The reason is kept in its
+// status history.
+type StatusRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+// ResumeRequest reopens a halted or pre-open instrument, to TRADING by
+// default or to POST_ONLY.
+type ResumeRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+	Status string `json:"status,omitempty" validate:"omitempty,oneof=TRADING POST_ONLY"`
+}
+
+// AuctionRequest starts an auction on an instrument. Orders collect without
+// matching for DurationSeconds and the book then uncrosses at a single price.
+type AuctionRequest struct {
+	Reason          string `json:"reason" validate:"required,max=255"`
+	DurationSeconds int    `json:"duration_seconds" validate:"required,min=1,max=3600"`
+}
+
+// StatusChangeDTO is an entry of an instrument's status history. From is
+// empty for the status the instrument was listed with.
+type StatusChangeDTO struct {
+	ID        string    `json:"id"`
+	From      string    `json:"from,omitempty"`
+	To        string    `json:"to"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 func (r *CreateInstrumentRequest) Validate() error {
 	return validator.New().Struct(r)
 }
+
+func (r *StatusRequest) Validate() error {
+	return validator.New().Struct(r)
+}
+
+func (r *ResumeRequest) Validate() error {
+	return validator.New().Struct(r)
+}
+
+func (r *AuctionRequest) Validate() error {
+	return validator.New().Struct(r)
+}
@@ -39,3 +39,30 @@ func TestCreateInstrumentRequest_Validate_MissingBoth(t *testing.T) {
 	err := req.Validate()
 	assert.Error(t, err)
 }
+
+func TestCreateInstrumentRequest_Validate_Status(t *testing.T) {
+	req := &dto.CreateInstrumentRequest{BaseAsset: "BTC", QuoteAsset: "USD", Status: "PRE_OPEN"}
+	assert.NoError(t, req.Validate())
+
+	req.Status = "HALTED"
+	assert.Error(t, req.Validate())
+}
+
+func TestStatusRequest_Validate(t *testing.T) {
+	assert.NoError(t, (&dto.StatusRequest{Reason: "volatility"}).Validate())
+	assert.Error(t, (&dto.StatusRequest{}).Validate())
+}
+
+func TestResumeRequest_Validate(t *testing.T) {
+	assert.NoError(t, (&dto.ResumeRequest{Reason: "recovered"}).Validate())
+	assert.NoError(t, (&dto.ResumeRequest{Reason: "recovered", Status: "POST_ONLY"}).Validate())
+	assert.Error(t, (&dto.ResumeRequest{Reason: "recovered", Status: "HALTED"}).Validate())
+	assert.Error(t, (&dto.ResumeRequest{Status: "TRADING"}).Validate())
+}
+
+func TestAuctionRequest_Validate(t *testing.T) {
+	assert.NoError(t, (&dto.AuctionRequest{Reason: "opening auction", DurationSeconds: 300}).Validate())
+	assert.Error(t, (&dto.AuctionRequest{Reason: "opening auction"}).Validate())
+	assert.Error(t, (&dto.AuctionRequest{Reason: "opening auction", DurationSeconds: 7200}).Validate())
+	assert.Error(t, (&dto.AuctionRequest{DurationSeconds: 300}).Validate())
+}
@@ -7,24 +7,43 @@ import (
 )
 
 type Instrument struct {
-	ID         string    `json:"id"`
-	BaseAsset  string    `json:"base_asset"`
-	QuoteAsset string    `json:"quote_asset"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID         string           `json:"id"`
+	BaseAsset  string           `json:"base_asset"`
+	QuoteAsset string           `json:"quote_asset"`
+	Status     InstrumentStatus `json:"status"`
+	// AuctionEndsAt is when the current auction uncrosses, set while the
+	// instrument is in AUCTION.
+	AuctionEndsAt *time.Time `json:"auction_ends_at,omitempty"`
+	TradingRules
+	PriceProtection
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 type InstrumentFilter struct {
-	BaseAsset  string `json:"base_asset"`
-	QuoteAsset string `json:"quote_asset"`
+	BaseAsset  string           `json:"base_asset"`
+	QuoteAsset string           `json:"quote_asset"`
+	Status     InstrumentStatus `json:"status"`
 }
 
 func (i *Instrument) ToDTO() dto.InstrumentDTO {
 	return dto.InstrumentDTO{
-		ID:         i.ID,
-		BaseAsset:  i.BaseAsset,
-		QuoteAsset: i.QuoteAsset,
-		CreatedAt:  i.CreatedAt,
-		UpdatedAt:  i.UpdatedAt,
+		ID:                           i.ID,
+		BaseAsset:                    i.BaseAsset,
+		QuoteAsset:                   i.QuoteAsset,
+		Status:                       string(i.Status),
+		AuctionEndsAt:                i.AuctionEndsAt,
+		TickSize:                     i.TickSize,
+		LotSize:                      i.LotSize,
+		MinQuantity:                  i.MinQuantity,
+		MaxQuantity:                  i.MaxQuantity,
+		MinNotional:                  i.MinNotional,
+		ReferencePrice:               i.ReferencePrice,
+		PriceBandPercent:             i.PriceBandPercent,
+		CircuitBreakerPercent:        i.CircuitBreakerPercent,
+		CircuitBreakerWindowSeconds:  int(i.CircuitBreakerWindow / time.Second),
+		CircuitBreakerAuctionSeconds: int(i.CircuitBreakerAuction / time.Second),
+		CreatedAt:                    i.CreatedAt,
+		UpdatedAt:                    i.UpdatedAt,
 	}
 }
 
@@ -33,10 +52,37 @@ func ToEntity(dto dto.CreateInstrumentRequest) (*Instrument, error) {
 		return nil, err
 	}
 
-	return &Instrument{
-		BaseAsset:  dto.BaseAsset,
-		QuoteAsset: dto.QuoteAsset,
-	}, nil
+	rules := ToTradingRules(dto)
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	protection := ToPriceProtection(dto)
+	if err := protection.Validate(); err != nil {
+		return nil, err
+	}
+
+	instrument := &Instrument{
+		BaseAsset:       dto.BaseAsset,
+		QuoteAsset:      dto.QuoteAsset,
+		Status:          StatusTrading,
+		TradingRules:    rules,
+		PriceProtection: protection,
+	}
+	if dto.Status != "" {
+		instrument.Status = InstrumentStatus(dto.Status)
+	}
+	return instrument, nil
+}
+
+// ToTradingRules reads the trading rules of a create or update request.
+func ToTradingRules(dto dto.CreateInstrumentRequest) TradingRules {
+	return TradingRules{
+		TickSize:    dto.TickSize,
+		LotSize:     dto.LotSize,
+		MinQuantity: dto.MinQuantity,
+		MaxQuantity: dto.MaxQuantity,
+		MinNotional: dto.MinNotional,
+	}
 }
 
 func ToListDTO(accounts []Instrument) []dto.InstrumentListDTO {
@@ -46,6 +92,7 @@ func ToListDTO(accounts []Instrument) []dto.InstrumentListDTO {
 			ID:         a.ID,
 			BaseAsset:  a.BaseAsset,
 			QuoteAsset: a.QuoteAsset,
+			Status:     string(a.Status),
 		}
 	}
 	return dtos
@@ -55,5 +102,6 @@ func ToEntityFilter(dto dto.InstrumentFilter) *InstrumentFilter {
 	return &InstrumentFilter{
 		BaseAsset:  dto.BaseAsset,
 		QuoteAsset: dto.QuoteAsset,
+		Status:     InstrumentStatus(dto.Status),
 	}
 }
@@ -0,0 +1,97 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/domain/dto"
+)
+
+// RejectionPriceBand is reported when a limit price is too far from the
+// instrument's reference price.
+const RejectionPriceBand = "PRICE_OUTSIDE_PRICE_BAND"
+
+// PriceProtection guards an instrument against erroneous prices and sudden
+// moves. Limit prices more than PriceBandPercent away from the last trade
+// price, or ReferencePrice until the instrument trades, are rejected. When
+// the price moves more than CircuitBreakerPercent within CircuitBreakerWindow
+// the engine pauses the instrument: it enters an auction lasting
+// CircuitBreakerAuction or, when that is zero, it is halted. A nil percentage
+// or a zero window is not enforced.
+type PriceProtection struct {
+	ReferencePrice        *big.Float    `json:"reference_price,omitempty"`
+	PriceBandPercent      *big.Float    `json:"price_band_percent,omitempty"`
+	CircuitBreakerPercent *big.Float    `json:"circuit_breaker_percent,omitempty"`
+	CircuitBreakerWindow  time.Duration `json:"circuit_breaker_window,omitempty"`
+	CircuitBreakerAuction time.Duration `json:"circuit_breaker_auction,omitempty"`
+}
+
+// CheckPriceBand returns a *RuleViolationError when price is further from the
+// reference than the price band allows. The reference is lastPrice or, when
+// the instrument has not traded, ReferencePrice; without either nothing is
+// checked.
+func (p PriceProtection) CheckPriceBand(price, lastPrice *big.Float) error {
+	reference := lastPrice
+	if reference == nil {
+		reference = p.ReferencePrice
+	}
+	if p.PriceBandPercent == nil || price == nil || reference == nil {
+		return nil
+	}
+
+	allowed := new(big.Float).Quo(new(big.Float).Mul(reference, p.PriceBandPercent), big.NewFloat(100))
+	low := new(big.Float).Sub(reference, allowed)
+	high := new(big.Float).Add(reference, allowed)
+	if price.Cmp(low) >= 0 && price.Cmp(high) <= 0 {
+		return nil
+	}
+	return &RuleViolationError{Rejections: []Rejection{{
+		Code:    RejectionPriceBand,
+		Field:   "price",
+		Message: fmt.Sprintf("price must be within %s%% of the reference price %s", decimal(p.PriceBandPercent), decimal(reference)),
+	}}}
+}
+
+// HasCircuitBreaker reports whether the instrument pauses on sudden moves.
+func (p PriceProtection) HasCircuitBreaker() bool {
+	return p.CircuitBreakerPercent != nil && p.CircuitBreakerWindow > 0
+}
+
+// Validate checks that the protection settings are usable.
+func (p PriceProtection) Validate() error {
+	if p.ReferencePrice != nil && p.ReferencePrice.Sign() <= 0 {
+		return errors.New("reference_price must be positive")
+	}
+	if p.PriceBandPercent != nil && p.PriceBandPercent.Sign() <= 0 {
+		return errors.New("price_band_percent must be positive")
+	}
+	if p.CircuitBreakerPercent != nil && p.CircuitBreakerPercent.Sign() <= 0 {
+		return errors.New("circuit_breaker_percent must be positive")
+	}
+	if p.CircuitBreakerPercent != nil && p.CircuitBreakerWindow <= 0 {
+		return errors.New("circuit_breaker_window_seconds is required with circuit_breaker_percent")
+	}
+	return nil
+}
+
+// TripCircuitBreaker returns the change that pauses the instrument after a
+// sudden move at now: an auction when the breaker has one, a halt otherwise.
+func (i *Instrument) TripCircuitBreaker(now time.Time, reason string) (StatusChange, error) {
+	if i.CircuitBreakerAuction > 0 {
+		return i.StartAuction(now.Add(i.CircuitBreakerAuction), reason)
+	}
+	return i.Halt(reason)
+}
+
+// ToPriceProtection reads the price protection of a create or update request.
+func ToPriceProtection(dto dto.CreateInstrumentRequest) PriceProtection {
+	return PriceProtection{
+		ReferencePrice:        dto.ReferencePrice,
+		PriceBandPercent:      dto.PriceBandPercent,
+		CircuitBreakerPercent: dto.CircuitBreakerPercent,
+		CircuitBreakerWindow:  time.Duration(dto.CircuitBreakerWindowSeconds) * time.Second,
+		CircuitBreakerAuction: time.Duration(dto.CircuitBreakerAuctionSeconds) * time.Second,
+	}
+}
@@ -0,0 +1,118 @@
+package entity_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/domain/dto"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/domain/entity"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/ierr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceProtection_CheckPriceBand(t *testing.T) {
+	protection := entity.PriceProtection{ReferencePrice: decimal("100"), PriceBandPercent: decimal("10")}
+	tests := []struct {
+		name      string
+		price     string
+		lastPrice string
+		accepted  bool
+	}{
+		{"inside band around reference", "109", "", true},
+		{"on the band edge", "90", "", true},
+		{"above band around reference", "111", "", false},
+		{"last price replaces reference", "130", "120", true},
+		{"below band around last price", "100", "120", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			// an empty last price parses to nil: the instrument has not traded
+			err := protection.CheckPriceBand(decimal(tt.price), decimal(tt.lastPrice))
+
+			// assert
+			if tt.accepted {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, ierr.ErrInvalidInput))
+			assert.Equal(t, []string{entity.RejectionPriceBand}, rejectionCodes(t, err))
+		})
+	}
+}
+
+func TestPriceProtection_CheckPriceBand_NoReference(t *testing.T) {
+	// arrange
+	protection := entity.PriceProtection{PriceBandPercent: decimal("10")}
+
+	// act
+	err := protection.CheckPriceBand(decimal("1000000"), nil)
+
+	// assert
+	assert.NoError(t, err)
+}
+
+func TestPriceProtection_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		protection entity.PriceProtection
+		err        string
+	}{
+		{"none", entity.PriceProtection{}, ""},
+		{"valid", entity.PriceProtection{PriceBandPercent: decimal("5"), CircuitBreakerPercent: decimal("10"), CircuitBreakerWindow: time.Minute}, ""},
+		{"zero band", entity.PriceProtection{PriceBandPercent: decimal("0")}, "price_band_percent must be positive"},
+		{"breaker without window", entity.PriceProtection{CircuitBreakerPercent: decimal("10")}, "circuit_breaker_window_seconds is required with circuit_breaker_percent"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			err := tt.protection.Validate()
+
+			// assert
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestInstrument_TripCircuitBreaker(t *testing.T) {
+	// arrange
+	now := time.Now()
+	halting := entity.Instrument{Status: entity.StatusTrading}
+	auctioning := entity.Instrument{Status: entity.StatusTrading, PriceProtection: entity.PriceProtection{CircuitBreakerAuction: time.Minute}}
+
+	// act
+	halt, haltErr := halting.TripCircuitBreaker(now, "circuit breaker tripped")
+	auction, auctionErr := auctioning.TripCircuitBreaker(now, "circuit breaker tripped")
+
+	// assert
+	assert.NoError(t, haltErr)
+	assert.Equal(t, entity.StatusHalted, halt.To)
+	assert.NoError(t, auctionErr)
+	assert.Equal(t, entity.StatusAuction, auction.To)
+	assert.Equal(t, now.Add(time.Minute), *auction.AuctionEndsAt)
+}
+
+func TestToEntity_PriceProtection(t *testing.T) {
+	// arrange
+	req := dto.CreateInstrumentRequest{
+		BaseAsset:                   "BTC",
+		QuoteAsset:                  "USDT",
+		PriceBandPercent:            decimal("5"),
+		CircuitBreakerPercent:       decimal("10"),
+		CircuitBreakerWindowSeconds: 60,
+	}
+
+	// act
+	inst, err := entity.ToEntity(req)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, inst.CircuitBreakerWindow)
+	assert.True(t, inst.HasCircuitBreaker())
+	assert.Equal(t, 60, inst.ToDTO().CircuitBreakerWindowSeconds)
+}
@@ -0,0 +1,167 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/ierr"
+)
+
+// Rejection codes reported when an order breaks an instrument's trading rules.
+const (
+	RejectionTickSize    = "PRICE_NOT_MULTIPLE_OF_TICK_SIZE"
+	RejectionLotSize     = "QUANTITY_NOT_MULTIPLE_OF_LOT_SIZE"
+	RejectionMinQuantity = "QUANTITY_BELOW_MIN_QUANTITY"
+	RejectionMaxQuantity = "QUANTITY_ABOVE_MAX_QUANTITY"
+	RejectionMinNotional = "NOTIONAL_BELOW_MIN_NOTIONAL"
+)
+
+// TradingRules constrain the orders accepted on an instrument. Prices must be
+// multiples of TickSize and quantities multiples of LotSize, between
+// MinQuantity and MaxQuantity, and an order's notional must reach
+// MinNotional. A nil rule is not enforced.
+type TradingRules struct {
+	TickSize    *big.Float `json:"tick_size,omitempty"`
+	LotSize     *big.Float `json:"lot_size,omitempty"`
+	MinQuantity *big.Float `json:"min_quantity,omitempty"`
+	MaxQuantity *big.Float `json:"max_quantity,omitempty"`
+	MinNotional *big.Float `json:"min_notional,omitempty"`
+}
+
+// OrderTerms are the parts of an order the trading rules apply to. Nil terms
+// are not checked.
+type OrderTerms struct {
+	Price           *big.Float
+	StopPrice       *big.Float
+	Quantity        *big.Float
+	DisplayQuantity *big.Float
+	QuoteQuantity   *big.Float
+}
+
+// Rejection is a single trading rule an order breaks.
+type Rejection struct {
+	Code    string `json:"code"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// RuleViolationError lists every trading rule an order breaks. It wraps
+// ierr.ErrInvalidInput.
+type RuleViolationError struct {
+	Rejections []Rejection
+}
+
+func (e *RuleViolationError) Error() string {
+	messages := make([]string, len(e.Rejections))
+	for i, r := range e.Rejections {
+		messages[i] = r.Message
+	}
+	return "order violates trading rules: " + strings.Join(messages, "; ")
+}
+
+func (e *RuleViolationError) Unwrap() error {
+	return ierr.ErrInvalidInput
+}
+
+// Check returns a *RuleViolationError listing every rule the order terms
+// break, or nil when they follow all of them. Notional is checked on the
+// price and quantity of limit orders and on the budget of quote-sized ones.
+func (r TradingRules) Check(terms OrderTerms) error {
+	var rejections []Rejection
+	reject := func(code, field, format string, args ...any) {
+		rejections = append(rejections, Rejection{Code: code, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if r.TickSize != nil {
+		if terms.Price != nil && !isMultiple(terms.Price, r.TickSize) {
+			reject(RejectionTickSize, "price", "price must be a multiple of the tick size %s", decimal(r.TickSize))
+		}
+		if terms.StopPrice != nil && !isMultiple(terms.StopPrice, r.TickSize) {
+			reject(RejectionTickSize, "stop_price", "stop_price must be a multiple of the tick size %s", decimal(r.TickSize))
+		}
+	}
+
+	if terms.Quantity != nil {
+		if r.LotSize != nil && !isMultiple(terms.Quantity, r.LotSize) {
+			reject(RejectionLotSize, "quantity", "quantity must be a multiple of the lot size %s", decimal(r.LotSize))
+		}
+		if r.MinQuantity != nil && terms.Quantity.Cmp(r.MinQuantity) < 0 {
+			reject(RejectionMinQuantity, "quantity", "quantity must be at least %s", decimal(r.MinQuantity))
+		}
+		if r.MaxQuantity != nil && terms.Quantity.Cmp(r.MaxQuantity) > 0 {
+			reject(RejectionMaxQuantity, "quantity", "quantity must be at most %s", decimal(r.MaxQuantity))
+		}
+	}
+	if terms.DisplayQuantity != nil && r.LotSize != nil && !isMultiple(terms.DisplayQuantity, r.LotSize) {
+		reject(RejectionLotSize, "display_quantity", "display_quantity must be a multiple of the lot size %s", decimal(r.LotSize))
+	}
+
+	if r.MinNotional != nil {
+		var notional *big.Float
+		field := "quantity"
+		switch {
+		case terms.QuoteQuantity != nil:
+			notional, field = terms.QuoteQuantity, "quote_quantity"
+		case terms.Price != nil && terms.Quantity != nil:
+			notional = new(big.Float).Mul(terms.Price, terms.Quantity)
+		}
+		if notional != nil && notional.Cmp(r.MinNotional) < 0 {
+			reject(RejectionMinNotional, field, "notional must be at least %s", decimal(r.MinNotional))
+		}
+	}
+
+	if len(rejections) == 0 {
+		return nil
+	}
+	return &RuleViolationError{Rejections: rejections}
+}
+
+// Validate checks that the rules are consistent with each other.
+func (r TradingRules) Validate() error {
+	rules := []struct {
+		name  string
+		value *big.Float
+	}{
+		{"tick_size", r.TickSize},
+		{"lot_size", r.LotSize},
+		{"min_quantity", r.MinQuantity},
+		{"max_quantity", r.MaxQuantity},
+		{"min_notional", r.MinNotional},
+	}
+	for _, rule := range rules {
+		if rule.value != nil && rule.value.Sign() <= 0 {
+			return fmt.Errorf("%s must be positive", rule.name)
+		}
+	}
+	if r.MinQuantity != nil && r.MaxQuantity != nil && r.MinQuantity.Cmp(r.MaxQuantity) > 0 {
+		return errors.New("min_quantity cannot be greater than max_quantity")
+	}
+	return nil
+}
+
+// multipleTolerance is how far, in steps, a value may be from a whole
+// multiple and still count as one. It absorbs the binary rounding of decimals
+// such as 0.1 sent as JSON numbers.
+var multipleTolerance = big.NewFloat(1e-9)
+
+// isMultiple reports whether value is a whole multiple of step.
+func isMultiple(value, step *big.Float) bool {
+	if step.Sign() <= 0 {
+		return false
+	}
+	steps := new(big.Float).SetPrec(256).Quo(value, step)
+	whole, _ := new(big.Float).SetPrec(256).Add(steps, big.NewFloat(0.5)).Int(nil)
+	diff := new(big.Float).SetPrec(256).Sub(steps, new(big.Float).SetInt(whole))
+	return diff.Abs(diff).Cmp(multipleTolerance) <= 0
+}
+
+// decimal formats a rule value without trailing zeros.
+func decimal(value *big.Float) string {
+	text := value.Text('f', 18)
+	if strings.Contains(text, ".") {
+		text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	}
+	return text
+}
@@ -0,0 +1,208 @@
+package entity_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/domain/dto"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/domain/entity"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/ierr"
+	"github.com/stretchr/testify/assert"
+)
+
+func decimal(value string) *big.Float {
+	f, _ := new(big.Float).SetString(value)
+	return f
+}
+
+func rules() entity.TradingRules {
+	return entity.TradingRules{
+		TickSize:    decimal("0.01"),
+		LotSize:     decimal("0.001"),
+		MinQuantity: decimal("0.001"),
+		MaxQuantity: decimal("100"),
+		MinNotional: decimal("10"),
+	}
+}
+
+func rejectionCodes(t *testing.T, err error) []string {
+	t.Helper()
+	var violation *entity.RuleViolationError
+	if !assert.ErrorAs(t, err, &violation) {
+		return nil
+	}
+	codes := make([]string, len(violation.Rejections))
+	for i, r := range violation.Rejections {
+		codes[i] = r.Code
+	}
+	return codes
+}
+
+func TestTradingRules_Check_Accepts(t *testing.T) {
+	// arrange
+	terms := entity.OrderTerms{Price: decimal("20000.50"), Quantity: decimal("0.005")}
+
+	// act
+	err := rules().Check(terms)
+
+	// assert
+	assert.NoError(t, err)
+}
+
+func TestTradingRules_Check_NoRules(t *testing.T) {
+	// arrange
+	terms := entity.OrderTerms{Price: decimal("0.123456"), Quantity: decimal("0.0000001")}
+
+	// act
+	err := entity.TradingRules{}.Check(terms)
+
+	// assert
+	assert.NoError(t, err)
+}
+
+func TestTradingRules_Check_TickSize(t *testing.T) {
+	// arrange
+	terms := entity.OrderTerms{Price: decimal("20000.505"), StopPrice: decimal("19999.999"), Quantity: decimal("1")}
+
+	// act
+	err := rules().Check(terms)
+
+	// assert
+	assert.Equal(t, []string{entity.RejectionTickSize, entity.RejectionTickSize}, rejectionCodes(t, err))
+}
+
+func TestTradingRules_Check_Quantity(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity string
+		codes    []string
+	}{
+		{"not a lot multiple", "1.0005", []string{entity.RejectionLotSize}},
+		{"above max", "150", []string{entity.RejectionMaxQuantity}},
+		{"below min", "0.0005", []string{entity.RejectionLotSize, entity.RejectionMinQuantity, entity.RejectionMinNotional}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			terms := entity.OrderTerms{Price: decimal("100"), Quantity: decimal(tt.quantity)}
+
+			// act
+			err := rules().Check(terms)
+
+			// assert
+			assert.Equal(t, tt.codes, rejectionCodes(t, err))
+		})
+	}
+}
+
+func TestTradingRules_Check_DisplayQuantity(t *testing.T) {
+	// arrange
+	terms := entity.OrderTerms{Price: decimal("100"), Quantity: decimal("1"), DisplayQuantity: decimal("0.0001")}
+
+	// act
+	err := rules().Check(terms)
+
+	// assert
+	var violation *entity.RuleViolationError
+	assert.ErrorAs(t, err, &violation)
+	assert.Equal(t, "display_quantity", violation.Rejections[0].Field)
+}
+
+func TestTradingRules_Check_MinNotional(t *testing.T) {
+	// arrange
+	limit := entity.OrderTerms{Price: decimal("9.99"), Quantity: decimal("1")}
+	quoteSized := entity.OrderTerms{QuoteQuantity: decimal("5")}
+	market := entity.OrderTerms{Quantity: decimal("0.001")}
+
+	// act
+	limitErr := rules().Check(limit)
+	quoteSizedErr := rules().Check(quoteSized)
+	marketErr := rules().Check(market)
+
+	// assert
+	assert.Equal(t, []string{entity.RejectionMinNotional}, rejectionCodes(t, limitErr))
+	var violation *entity.RuleViolationError
+	assert.ErrorAs(t, quoteSizedErr, &violation)
+	assert.Equal(t, "quote_quantity", violation.Rejections[0].Field)
+	assert.NoError(t, marketErr)
+}
+
+func TestTradingRules_Check_BinaryRounding(t *testing.T) {
+	// arrange
+	terms := entity.OrderTerms{Price: big.NewFloat(0.1), Quantity: big.NewFloat(300)}
+	r := entity.TradingRules{TickSize: decimal("0.01")}
+
+	// act
+	err := r.Check(terms)
+
+	// assert
+	assert.NoError(t, err)
+}
+
+func TestTradingRules_Check_Error(t *testing.T) {
+	// arrange
+	terms := entity.OrderTerms{Price: decimal("100.001"), Quantity: decimal("1")}
+
+	// act
+	err := rules().Check(terms)
+
+	// assert
+	assert.True(t, errors.Is(err, ierr.ErrInvalidInput))
+	assert.EqualError(t, err, "order violates trading rules: price must be a multiple of the tick size 0.01")
+}
+
+func TestTradingRules_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules entity.TradingRules
+		err   string
+	}{
+		{"valid", rules(), ""},
+		{"zero tick size", entity.TradingRules{TickSize: decimal("0")}, "tick_size must be positive"},
+		{"negative min notional", entity.TradingRules{MinNotional: decimal("-1")}, "min_notional must be positive"},
+		{"min above max", entity.TradingRules{MinQuantity: decimal("10"), MaxQuantity: decimal("1")}, "min_quantity cannot be greater than max_quantity"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			err := tt.rules.Validate()
+
+			// assert
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestToEntity_InvalidTradingRules(t *testing.T) {
+	// arrange
+	req := dto.CreateInstrumentRequest{
+		BaseAsset:   "BTC",
+		QuoteAsset:  "USDT",
+		MinQuantity: decimal("5"),
+		MaxQuantity: decimal("1"),
+	}
+
+	// act
+	inst, err := entity.ToEntity(req)
+
+	// assert
+	assert.Error(t, err)
+	assert.Nil(t, inst)
+}
+
+func TestInstrument_ToDTO_TradingRules(t *testing.T) {
+	// arrange
+	inst := entity.Instrument{ID: "uuid-1", BaseAsset: "BTC", QuoteAsset: "USDT", TradingRules: rules()}
+
+	// act
+	result := inst.ToDTO()
+
+	// assert
+	assert.Equal(t, "0.01", result.TickSize.Text('f', 2))
+	assert.Equal(t, 0, result.MinNotional.Cmp(decimal("10")))
+}
@@ -0,0 +1,135 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/domain/dto"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/ierr"
+)
+
+// InstrumentStatus is where an instrument is in its trading lifecycle.
+type InstrumentStatus string
+
+const (
+	// StatusPreOpen instruments are listed but not open yet: only cancels are
+	// accepted.
+	StatusPreOpen InstrumentStatus = "PRE_OPEN"
+	// StatusTrading instruments accept and match every order.
+	StatusTrading InstrumentStatus = "TRADING"
+	// StatusHalted instruments keep their book but only accept cancels.
+	StatusHalted InstrumentStatus = "HALTED"
+	// StatusPostOnly instruments only accept limit orders that add liquidity.
+	StatusPostOnly InstrumentStatus = "POST_ONLY"
+	// StatusAuction instruments are in the call phase of an auction: limit
+	// orders collect without matching until the book uncrosses at a single
+	// price.
+	StatusAuction InstrumentStatus = "AUCTION"
+	// StatusDelisted instruments no longer trade and never will again.
+	StatusDelisted InstrumentStatus = "DELISTED"
+)
+
+// AcceptsOrders reports whether new orders and amendments are accepted.
+func (s InstrumentStatus) AcceptsOrders() bool {
+	return s == StatusTrading || s == StatusPostOnly || s == StatusAuction
+}
+
+// CheckOrder returns an ErrConflict when the status does not accept a new
+// order: nothing is accepted unless the instrument trades, only post-only
+// limit orders while it is POST_ONLY and only limit orders that can rest,
+// without post-only, during an auction.
+func (s InstrumentStatus) CheckOrder(postOnlyLimit, restingLimit bool) error {
+	if !s.AcceptsOrders() {
+		return fmt.Errorf("instrument is %s and only accepts cancels: %w", s, ierr.ErrConflict)
+	}
+	if s == StatusPostOnly && !postOnlyLimit {
+		return fmt.Errorf("instrument is %s and only accepts post-only limit orders: %w", s, ierr.ErrConflict)
+	}
+	if s == StatusAuction && (!restingLimit || postOnlyLimit) {
+		return fmt.Errorf("instrument is in an auction and only accepts GTC or GTD limit orders: %w", ierr.ErrConflict)
+	}
+	return nil
+}
+
+// StatusChange records a status transition of an instrument and why it
+// happened. From is empty for the status an instrument was listed with.
+type StatusChange struct {
+	ID           string
+	InstrumentID string
+	From         InstrumentStatus
+	To           InstrumentStatus
+	Reason       string
+	CreatedAt    time.Time
+	// AuctionEndsAt is when the auction ends, for changes to AUCTION.
+	AuctionEndsAt *time.Time
+}
+
+// Transition returns the change that moves the instrument to status. Delisted
+// instruments cannot change status and an instrument cannot move to the
+// status it already has.
+func (i *Instrument) Transition(to InstrumentStatus, reason string) (StatusChange, error) {
+	if i.Status == StatusDelisted {
+		return StatusChange{}, fmt.Errorf("instrument is %s: %w", i.Status, ierr.ErrConflict)
+	}
+	if i.Status == to {
+		return StatusChange{}, fmt.Errorf("instrument is already %s: %w", to, ierr.ErrConflict)
+	}
+	return StatusChange{InstrumentID: i.ID, From: i.Status, To: to, Reason: reason}, nil
+}
+
+// Halt returns the change that halts a trading instrument. An auction cannot
+// be halted, as its book may cross until it uncrosses.
+func (i *Instrument) Halt(reason string) (StatusChange, error) {
+	if i.Status == StatusPreOpen || i.Status == StatusAuction {
+		return StatusChange{}, fmt.Errorf("instrument is %s: %w", i.Status, ierr.ErrConflict)
+	}
+	return i.Transition(StatusHalted, reason)
+}
+
+// Resume returns the change that opens a halted or pre-open instrument, to
+// TRADING or POST_ONLY.
+func (i *Instrument) Resume(to InstrumentStatus, reason string) (StatusChange, error) {
+	if i.Status != StatusHalted && i.Status != StatusPreOpen {
+		return StatusChange{}, fmt.Errorf("instrument is %s, not halted: %w", i.Status, ierr.ErrConflict)
+	}
+	return i.Transition(to, reason)
+}
+
+// StartAuction returns the change that puts the instrument in an auction
+// ending at endsAt. Any status but DELISTED can start one, e.g. PRE_OPEN to
+// list a new instrument at a fair opening price or HALTED to reopen one.
+func (i *Instrument) StartAuction(endsAt time.Time, reason string) (StatusChange, error) {
+	change, err := i.Transition(StatusAuction, reason)
+	if err != nil {
+		return StatusChange{}, err
+	}
+	change.AuctionEndsAt = &endsAt
+	return change, nil
+}
+
+// EndAuction returns the change that opens an instrument to TRADING once its
+// auction has uncrossed.
+func (i *Instrument) EndAuction(reason string) (StatusChange, error) {
+	if i.Status != StatusAuction {
+		return StatusChange{}, fmt.Errorf("instrument is %s, not in an auction: %w", i.Status, ierr.ErrConflict)
+	}
+	return i.Transition(StatusTrading, reason)
+}
+
+func (c StatusChange) ToDTO() dto.StatusChangeDTO {
+	return dto.StatusChangeDTO{
+		ID:        c.ID,
+		From:      string(c.From),
+		To:        string(c.To),
+		Reason:    c.Reason,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+func ToStatusHistoryDTO(changes []StatusChange) []dto.StatusChangeDTO {
+	dtos := make([]dto.StatusChangeDTO, len(changes))
+	for i, c := range changes {
+		dtos[i] = c.ToDTO()
+	}
+	return dtos
+}
@@ -0,0 +1,177 @@
+package entity_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/domain/dto"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/domain/entity"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/ierr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstrumentStatus_CheckOrder(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        entity.InstrumentStatus
+		postOnlyLimit bool
+		restingLimit  bool
+		accepted      bool
+	}{
+		{"trading", entity.StatusTrading, false, false, true},
+		{"trading post-only", entity.StatusTrading, true, true, true},
+		{"post-only", entity.StatusPostOnly, true, true, true},
+		{"post-only taker", entity.StatusPostOnly, false, true, false},
+		{"auction limit", entity.StatusAuction, false, true, true},
+		{"auction market", entity.StatusAuction, false, false, false},
+		{"auction post-only", entity.StatusAuction, true, true, false},
+		{"halted", entity.StatusHalted, true, true, false},
+		{"pre-open", entity.StatusPreOpen, true, true, false},
+		{"delisted", entity.StatusDelisted, true, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			err := tt.status.CheckOrder(tt.postOnlyLimit, tt.restingLimit)
+
+			// assert
+			if tt.accepted {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, ierr.ErrConflict))
+		})
+	}
+}
+
+func TestInstrument_Halt(t *testing.T) {
+	// arrange
+	inst := entity.Instrument{ID: "uuid-1", Status: entity.StatusTrading}
+
+	// act
+	change, err := inst.Halt("volatility")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "uuid-1", change.InstrumentID)
+	assert.Equal(t, entity.StatusTrading, change.From)
+	assert.Equal(t, entity.StatusHalted, change.To)
+	assert.Equal(t, "volatility", change.Reason)
+}
+
+func TestInstrument_Halt_Rejected(t *testing.T) {
+	for _, status := range []entity.InstrumentStatus{entity.StatusHalted, entity.StatusPreOpen, entity.StatusAuction, entity.StatusDelisted} {
+		t.Run(string(status), func(t *testing.T) {
+			// arrange
+			inst := entity.Instrument{Status: status}
+
+			// act
+			_, err := inst.Halt("volatility")
+
+			// assert
+			assert.True(t, errors.Is(err, ierr.ErrConflict))
+		})
+	}
+}
+
+func TestInstrument_Resume(t *testing.T) {
+	// arrange
+	halted := entity.Instrument{Status: entity.StatusHalted}
+	preOpen := entity.Instrument{Status: entity.StatusPreOpen}
+	trading := entity.Instrument{Status: entity.StatusTrading}
+
+	// act
+	fromHalt, haltErr := halted.Resume(entity.StatusPostOnly, "recovered")
+	fromPreOpen, preOpenErr := preOpen.Resume(entity.StatusTrading, "open")
+	_, tradingErr := trading.Resume(entity.StatusTrading, "open")
+
+	// assert
+	assert.NoError(t, haltErr)
+	assert.Equal(t, entity.StatusPostOnly, fromHalt.To)
+	assert.NoError(t, preOpenErr)
+	assert.Equal(t, entity.StatusTrading, fromPreOpen.To)
+	assert.True(t, errors.Is(tradingErr, ierr.ErrConflict))
+}
+
+func TestInstrument_Transition_Delisted(t *testing.T) {
+	// arrange
+	inst := entity.Instrument{Status: entity.StatusDelisted}
+
+	// act
+	_, err := inst.Transition(entity.StatusTrading, "relist")
+
+	// assert
+	assert.True(t, errors.Is(err, ierr.ErrConflict))
+}
+
+func TestInstrument_StartAuction(t *testing.T) {
+	// arrange
+	endsAt := time.Now().Add(time.Minute)
+	preOpen := entity.Instrument{ID: "uuid-1", Status: entity.StatusPreOpen}
+	delisted := entity.Instrument{Status: entity.StatusDelisted}
+
+	// act
+	change, err := preOpen.StartAuction(endsAt, "opening auction")
+	_, delistedErr := delisted.StartAuction(endsAt, "opening auction")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, entity.StatusPreOpen, change.From)
+	assert.Equal(t, entity.StatusAuction, change.To)
+	assert.Equal(t, endsAt, *change.AuctionEndsAt)
+	assert.True(t, errors.Is(delistedErr, ierr.ErrConflict))
+}
+
+func TestInstrument_EndAuction(t *testing.T) {
+	// arrange
+	endsAt := time.Now()
+	inAuction := entity.Instrument{ID: "uuid-1", Status: entity.StatusAuction, AuctionEndsAt: &endsAt}
+	trading := entity.Instrument{Status: entity.StatusTrading}
+
+	// act
+	change, err := inAuction.EndAuction("auction uncrossed")
+	_, tradingErr := trading.EndAuction("auction uncrossed")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, entity.StatusTrading, change.To)
+	assert.Nil(t, change.AuctionEndsAt)
+	assert.True(t, errors.Is(tradingErr, ierr.ErrConflict))
+}
+
+func TestToEntity_Status(t *testing.T) {
+	// arrange
+	byDefault := dto.CreateInstrumentRequest{BaseAsset: "BTC", QuoteAsset: "USDT"}
+	preOpen := dto.CreateInstrumentRequest{BaseAsset: "BTC", QuoteAsset: "USDT", Status: "PRE_OPEN"}
+
+	// act
+	defaultInst, defaultErr := entity.ToEntity(byDefault)
+	preOpenInst, preOpenErr := entity.ToEntity(preOpen)
+
+	// assert
+	assert.NoError(t, defaultErr)
+	assert.Equal(t, entity.StatusTrading, defaultInst.Status)
+	assert.NoError(t, preOpenErr)
+	assert.Equal(t, entity.StatusPreOpen, preOpenInst.Status)
+}
+
+func TestToStatusHistoryDTO(t *testing.T) {
+	// arrange
+	now := time.Now()
+	changes := []entity.StatusChange{
+		{ID: "c1", To: entity.StatusTrading, Reason: "instrument listed", CreatedAt: now},
+		{ID: "c2", From: entity.StatusTrading, To: entity.StatusHalted, Reason: "volatility", CreatedAt: now},
+	}
+
+	// act
+	dtos := entity.ToStatusHistoryDTO(changes)
+
+	// assert
+	assert.Len(t, dtos, 2)
+	assert.Empty(t, dtos[0].From)
+	assert.Equal(t, "TRADING", dtos[0].To)
+	assert.Equal(t, "TRADING", dtos[1].From)
+	assert.Equal(t, "HALTED", dtos[1].To)
+	assert.Equal(t, "volatility", dtos[1].Reason)
+}
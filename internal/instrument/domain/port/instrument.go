@@ -13,4 +13,7 @@ type InstrumentRepository interface {
 	FindByID(ctx context.Context, id string) (*entity.Instrument, error)
 	FindAll(ctx context.Context, filter *entity.InstrumentFilter) ([]*entity.Instrument, error)
 	FindByAssets(ctx context.Context, baseAsset, quoteAsset string) (*entity.Instrument, error)
+	UpdateStatus(ctx context.Context, change entity.StatusChange) error
+	CreateStatusChange(ctx context.Context, change entity.StatusChange) (entity.StatusChange, error)
+	FindStatusHistory(ctx context.Context, id string) ([]entity.StatusChange, error)
 }
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/app"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/domain/dto"
+)
+
+type Book interface {
+	GetBook(ctx echo.Context) error
+	RegisterRoutes(g *echo.Group)
+}
+
+type book struct {
+	engine app.Engine
+}
+
+func NewBookHandler(engine app.Engine) Book {
+	return &book{
+		engine: engine,
+	}
+}
+
+func (h *book) RegisterRoutes(g *echo.Group) {
+	g.GET("/:instrument_id", h.GetBook)
+}
+
+// GetBook godoc
+// @Summary      Retorna o livro de ofertas de um instrumento
+// @Description  Níveis de preço agregados, do melhor para o pior. Ordens iceberg mostram apenas a parte visível. Durante um leilão, indicative_price e indicative_quantity trazem o cruzamento indicativo.
+// @Tags         book
+// @Produce      json
+// @Param        instrument_id  path   string  true   "Instrument ID"
+// @Param        depth          query  int     false  "Número máximo de níveis por lado"
+// @Success      200  {object}  dto.BookDTO
+// @Failure      400  {object}  map[string]string
+// @Router       /v1/book/{instrument_id} [get]
+func (h *book) GetBook(ctx echo.Context) error {
+	instrumentID := ctx.Param("instrument_id")
+	if instrumentID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "instrument_id is required")
+	}
+
+	depth := 0
+	if raw := ctx.QueryParam("depth"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "depth must be a non-negative integer")
+		}
+		depth = parsed
+	}
+
+	var response dto.BookDTO = h.engine.Snapshot(instrumentID, depth).ToDTO()
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,276 @@
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/domain/entity"
+)
+
+// maxBatch caps how many Append calls share a single write and fsync.
+const maxBatch = 256
+
+// ErrClosed is returned by Append once the journal is closed.
+var ErrClosed = errors.New("journal is closed")
+
+var checksumTable = crc32.MakeTable(crc32.Castagnoli)
+
+type appendRequest struct {
+	entries []entity.JournalEntry
+	done    chan error
+}
+
+// FileJournal is a journal kept in a single append-only file, one entry per
+// line: the CRC-32C checksum of the entry in hex, a space and the entry as
+// JSON. A single writer numbers the entries and writes whatever Append calls
+// are waiting as one batch, followed by one fsync.
+type FileJournal struct {
+	file *os.File
+	// size is the length of the file up to the last whole entry.
+	size     int64
+	sequence uint64
+	requests chan appendRequest
+	closed   chan struct{}
+	stopped  chan struct{}
+}
+
+// NewFileJournal opens the journal at path, creating the file and its
+// directory when missing, and starts its writer. Numbering continues from
+// the last entry in the file. An entry torn at the end of the file by a crash
+// in the middle of a write is cut off; any other damaged entry fails.
+func NewFileJournal(path string) (*FileJournal, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	j := &FileJournal{
+		file:     file,
+		requests: make(chan appendRequest),
+		closed:   make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	if err := j.load(); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	go j.run()
+	return j, nil
+}
+
+// Append writes the entries, numbered in the order given, and returns once
+// they are synced to disk.
+func (j *FileJournal) Append(ctx context.Context, entries ...entity.JournalEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	request := appendRequest{entries: entries, done: make(chan error, 1)}
+	select {
+	case j.requests <- request:
+	case <-j.closed:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-request.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops the writer once the batch it is writing is done and closes the
+// file.
+func (j *FileJournal) Close() error {
+	close(j.closed)
+	<-j.stopped
+	return j.file.Close()
+}
+
+func (j *FileJournal) run() {
+	defer close(j.stopped)
+	for {
+		select {
+		case <-j.closed:
+			return
+		case request := <-j.requests:
+			batch := []appendRequest{request}
+		collect:
+			for len(batch) < maxBatch {
+				select {
+				case request := <-j.requests:
+					batch = append(batch, request)
+				default:
+					break collect
+				}
+			}
+
+			err := j.write(batch)
+			for _, request := range batch {
+				request.done <- err
+			}
+		}
+	}
+}
+
+// write appends the entries of a batch and syncs the file once. A batch that
+// fails is cut off again, so the file only ever holds whole entries.
+func (j *FileJournal) write(batch []appendRequest) error {
+	var buf bytes.Buffer
+	sequence := j.sequence
+	for _, request := range batch {
+		for _, entry := range request.entries {
+			sequence++
+			entry.Sequence = sequence
+			line, err := encode(entry)
+			if err != nil {
+				return err
+			}
+			buf.Write(line)
+		}
+	}
+
+	if _, err := j.file.Write(buf.Bytes()); err != nil {
+		return j.rollback(err)
+	}
+	if err := j.file.Sync(); err != nil {
+		return j.rollback(err)
+	}
+	j.sequence = sequence
+	j.size += int64(buf.Len())
+	return nil
+}
+
+func (j *FileJournal) rollback(err error) error {
+	if truncErr := j.file.Truncate(j.size); truncErr != nil {
+		return errors.Join(err, truncErr)
+	}
+	return err
+}
+
+// load reads the file back to find the last sequence number and where the
+// last whole entry ends.
+func (j *FileJournal) load() error {
+	reader := bufio.NewReader(j.file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(line) > 0 {
+				return j.cutTornTail(errors.New("entry is incomplete"))
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		model, err := decode(line)
+		if err != nil {
+			if _, peekErr := reader.Peek(1); errors.Is(peekErr, io.EOF) {
+				return j.cutTornTail(err)
+			}
+			return fmt.Errorf("journal damaged after sequence %d: %w", j.sequence, err)
+		}
+		if model.Sequence != j.sequence+1 {
+			return fmt.Errorf("journal out of sequence: %d follows %d", model.Sequence, j.sequence)
+		}
+		j.sequence = model.Sequence
+		j.size += int64(len(line))
+	}
+}
+
+// Replay reads the journal at path and calls fn, in order, for every entry
+// numbered from onwards. Each entry's checksum is verified and the numbering
+// must run on from 1 without gaps; a damaged entry stops the replay with an
+// error. The file is only read, so it can be replayed while the engine keeps
+// writing to it: an entry still being written at the end is left out.
+func Replay(path string, from uint64, fn func(EntryModel) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var sequence uint64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		model, err := decode(line)
+		if err != nil {
+			return fmt.Errorf("journal damaged after sequence %d: %w", sequence, err)
+		}
+		if model.Sequence != sequence+1 {
+			return fmt.Errorf("journal out of sequence: %d follows %d", model.Sequence, sequence)
+		}
+		sequence = model.Sequence
+		if sequence < from {
+			continue
+		}
+		if err := fn(model); err != nil {
+			return err
+		}
+	}
+}
+
+func (j *FileJournal) cutTornTail(cause error) error {
+	slog.Warn("cutting torn entry off the end of the journal", "sequence", j.sequence, "error", cause)
+	if err := j.file.Truncate(j.size); err != nil {
+		return err
+	}
+	return j.file.Sync()
+}
+
+// encode frames an entry as a journal line.
+func encode(entry entity.JournalEntry) ([]byte, error) {
+	body, err := json.Marshal(ToEntryModel(entry))
+	if err != nil {
+		return nil, err
+	}
+	line := fmt.Appendf(nil, "%08x ", crc32.Checksum(body, checksumTable))
+	line = append(line, body...)
+	return append(line, '\n'), nil
+}
+
+// decode checks the checksum of a journal line and returns its entry.
+func decode(line []byte) (EntryModel, error) {
+	checksum, body, ok := bytes.Cut(bytes.TrimSuffix(line, []byte("\n")), []byte(" "))
+	if !ok || len(checksum) != 8 {
+		return EntryModel{}, errors.New("entry is malformed")
+	}
+	want, err := strconv.ParseUint(string(checksum), 16, 32)
+	if err != nil {
+		return EntryModel{}, errors.New("entry is malformed")
+	}
+	if crc32.Checksum(body, checksumTable) != uint32(want) {
+		return EntryModel{}, errors.New("entry checksum does not match")
+	}
+
+	var model EntryModel
+	if err := json.Unmarshal(body, &model); err != nil {
+		return EntryModel{}, err
+	}
+	return model, nil
+}
@@ -0,0 +1,224 @@
+package journal_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/adapters/journal"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/domain/entity"
+	orderEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cancelEntry(orderID string) entity.JournalEntry {
+	return entity.JournalEntry{Type: entity.JournalCancel, OrderID: orderID, RecordedAt: time.Now()}
+}
+
+// appendEntries opens the journal at path, appends one CANCEL entry per order
+// ID and closes it again.
+func appendEntries(t *testing.T, path string, orderIDs ...string) {
+	t.Helper()
+	j, err := journal.NewFileJournal(path)
+	require.NoError(t, err)
+	for _, orderID := range orderIDs {
+		require.NoError(t, j.Append(context.Background(), cancelEntry(orderID)))
+	}
+	require.NoError(t, j.Close())
+}
+
+// readEntries returns the entries of every line of the journal at path.
+func readEntries(t *testing.T, path string) []journal.EntryModel {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var entries []journal.EntryModel
+	for _, line := range bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n")) {
+		_, body, ok := bytes.Cut(line, []byte(" "))
+		require.True(t, ok)
+		var entry journal.EntryModel
+		require.NoError(t, json.Unmarshal(body, &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// replayEntries returns the entries the journal at path replays from the
+// given sequence number.
+func replayEntries(t *testing.T, path string, from uint64) []journal.EntryModel {
+	t.Helper()
+	var entries []journal.EntryModel
+	require.NoError(t, journal.Replay(path, from, func(entry journal.EntryModel) error {
+		entries = append(entries, entry)
+		return nil
+	}))
+	return entries
+}
+
+// damageLine flips a byte in the body of the nth line (counted from zero) of
+// the journal at path, so its checksum no longer matches.
+func damageLine(t *testing.T, path string, n int) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	lines[n] = bytes.Replace(lines[n], []byte("order-"), []byte("ORDER-"), 1)
+	require.NoError(t, os.WriteFile(path, bytes.Join(lines, nil), 0o644))
+}
+
+func TestFileJournal_ContinuesSequenceAfterReopen(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "engine.journal")
+	appendEntries(t, path, "order-1", "order-2")
+
+	// act
+	appendEntries(t, path, "order-3")
+
+	// assert
+	entries := readEntries(t, path)
+	require.Len(t, entries, 3)
+	for i, entry := range entries {
+		assert.Equal(t, uint64(i+1), entry.Sequence)
+		assert.Equal(t, string(entity.JournalCancel), entry.Type)
+	}
+	assert.Equal(t, "order-3", entries[2].OrderID)
+}
+
+func TestFileJournal_CutsTornTail(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "engine.journal")
+	appendEntries(t, path, "order-1", "order-2")
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	require.NoError(t, err)
+	_, err = file.WriteString(`1a2b3c4d {"sequence":3,"type":"CAN`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	// act
+	appendEntries(t, path, "order-3")
+
+	// assert
+	entries := readEntries(t, path)
+	require.Len(t, entries, 3)
+	assert.Equal(t, uint64(3), entries[2].Sequence)
+	assert.Equal(t, "order-3", entries[2].OrderID)
+}
+
+func TestFileJournal_ChecksumMismatchAtTheEndIsCut(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "engine.journal")
+	appendEntries(t, path, "order-1", "order-2", "order-3")
+	damageLine(t, path, 2)
+
+	// act
+	appendEntries(t, path, "order-4")
+
+	// assert
+	entries := readEntries(t, path)
+	require.Len(t, entries, 3)
+	assert.Equal(t, uint64(3), entries[2].Sequence)
+	assert.Equal(t, "order-4", entries[2].OrderID)
+}
+
+func TestFileJournal_ChecksumMismatchInTheMiddleFails(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "engine.journal")
+	appendEntries(t, path, "order-1", "order-2", "order-3")
+	damageLine(t, path, 1)
+	before, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	// act
+	_, err = journal.NewFileJournal(path)
+
+	// assert
+	assert.ErrorContains(t, err, "journal damaged after sequence 1")
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, before, after, "a damaged journal is left as it is")
+}
+
+func TestFileJournal_ReplayReturnsAppendedEntries(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "engine.journal")
+	order := orderEntity.Order{
+		ID:                "order-2",
+		AccountID:         "account-1",
+		InstrumentID:      "instrument-1",
+		Type:              orderEntity.OrderTypeBuy,
+		Kind:              orderEntity.OrderKindLimit,
+		Price:             big.NewFloat(100),
+		Quantity:          big.NewFloat(1.5),
+		RemainingQuantity: big.NewFloat(1.5),
+	}
+	j, err := journal.NewFileJournal(path)
+	require.NoError(t, err)
+	require.NoError(t, j.Append(context.Background(),
+		cancelEntry("order-1"),
+		entity.OrderEntry(entity.JournalNewOrder, order, "", time.Now()),
+	))
+	require.NoError(t, j.Close())
+	appendEntries(t, path, "order-3")
+
+	// act
+	all := replayEntries(t, path, 1)
+	tail := replayEntries(t, path, 2)
+
+	// assert
+	require.Len(t, all, 3)
+	for i, entry := range all {
+		assert.Equal(t, uint64(i+1), entry.Sequence)
+	}
+	assert.Equal(t, "order-1", all[0].OrderID)
+	assert.Equal(t, string(entity.JournalNewOrder), all[1].Type)
+	require.NotNil(t, all[1].Order)
+	assert.Equal(t, "account-1", all[1].Order.AccountID)
+	require.NotNil(t, all[1].Order.Price)
+	assert.Equal(t, "100.0000000000", *all[1].Order.Price)
+	assert.Equal(t, "1.500000000000000000", all[1].Order.Quantity)
+	assert.Equal(t, "order-3", all[2].OrderID)
+	assert.Equal(t, all[1:], tail)
+}
+
+func TestFileJournal_ReplayLeavesOutEntryBeingWritten(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "engine.journal")
+	appendEntries(t, path, "order-1", "order-2")
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	require.NoError(t, err)
+	_, err = file.WriteString(`1a2b3c4d {"sequence":3,"type":"CAN`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	// act
+	entries := replayEntries(t, path, 1)
+
+	// assert
+	require.Len(t, entries, 2)
+	assert.Equal(t, "order-2", entries[1].OrderID)
+}
+
+func TestFileJournal_ReplayFailsOnChecksumMismatch(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "engine.journal")
+	appendEntries(t, path, "order-1", "order-2", "order-3")
+	damageLine(t, path, 1)
+	var replayed []string
+
+	// act
+	err := journal.Replay(path, 1, func(entry journal.EntryModel) error {
+		replayed = append(replayed, entry.OrderID)
+		return nil
+	})
+
+	// assert
+	assert.ErrorContains(t, err, "journal damaged after sequence 1")
+	assert.Equal(t, []string{"order-1"}, replayed)
+}
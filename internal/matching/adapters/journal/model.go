@@ -0,0 +1,83 @@
+package journal
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/domain/entity"
+	orderRepo "github.com/mthpedrosa/financial-exchange-challenge/internal/order/adapters/repository"
+)
+
+// EntryModel is the JSON form of a journal entry. Orders, amendments and
+// cancel filters are written as their queue messages.
+type EntryModel struct {
+	Sequence     uint64                    `json:"sequence"`
+	Type         string                    `json:"type"`
+	InstrumentID string                    `json:"instrument_id,omitempty"`
+	OrderID      string                    `json:"order_id,omitempty"`
+	Order        *orderRepo.OrderModel     `json:"order,omitempty"`
+	Amendment    *orderRepo.AmendModel     `json:"amendment,omitempty"`
+	CancelFilter *orderRepo.CancelAllModel `json:"cancel_filter,omitempty"`
+	Fill         *FillModel                `json:"fill,omitempty"`
+	Status       string                    `json:"status,omitempty"`
+	Reason       string                    `json:"reason,omitempty"`
+	RecordedAt   time.Time                 `json:"recorded_at"`
+}
+
+// FillModel is the JSON form of a fill.
+type FillModel struct {
+	MakerOrderID  string  `json:"maker_order_id"`
+	TakerOrderID  string  `json:"taker_order_id"`
+	Price         string  `json:"price"`
+	Quantity      string  `json:"quantity"`
+	MakerFee      *string `json:"maker_fee,omitempty"`
+	MakerFeeAsset string  `json:"maker_fee_asset,omitempty"`
+	TakerFee      *string `json:"taker_fee,omitempty"`
+	TakerFeeAsset string  `json:"taker_fee_asset,omitempty"`
+}
+
+func ToEntryModel(entry entity.JournalEntry) EntryModel {
+	model := EntryModel{
+		Sequence:     entry.Sequence,
+		Type:         string(entry.Type),
+		InstrumentID: entry.InstrumentID,
+		OrderID:      entry.OrderID,
+		Status:       string(entry.Status),
+		Reason:       entry.Reason,
+		RecordedAt:   entry.RecordedAt,
+	}
+	if entry.Order != nil {
+		model.Order = orderRepo.ToModel(*entry.Order)
+	}
+	if entry.Amendment != nil {
+		model.Amendment = orderRepo.ToAmendModel(*entry.Amendment)
+	}
+	if entry.CancelFilter != nil {
+		model.CancelFilter = orderRepo.ToCancelAllModel(*entry.CancelFilter)
+	}
+	if entry.Fill != nil {
+		model.Fill = ToFillModel(*entry.Fill)
+	}
+	return model
+}
+
+func ToFillModel(fill entity.Fill) *FillModel {
+	return &FillModel{
+		MakerOrderID:  fill.Maker.ID,
+		TakerOrderID:  fill.Taker.ID,
+		Price:         fill.Price.Text('f', 10),
+		Quantity:      fill.Quantity.Text('f', 18),
+		MakerFee:      formatOptional(fill.MakerFee),
+		MakerFeeAsset: fill.MakerFeeAsset,
+		TakerFee:      formatOptional(fill.TakerFee),
+		TakerFeeAsset: fill.TakerFeeAsset,
+	}
+}
+
+func formatOptional(value *big.Float) *string {
+	if value == nil {
+		return nil
+	}
+	s := value.Text('f', 18)
+	return &s
+}
@@ -0,0 +1,179 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/app"
+	orderRepo "github.com/mthpedrosa/financial-exchange-challenge/internal/order/adapters/repository"
+	orderEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type OrderConsumer struct {
+	channel         *amqp.Channel
+	queue           string
+	deadLetterQueue string
+	engine          app.Engine
+}
+
+func NewOrderConsumer(channel *amqp.Channel, queue, deadLetterQueue string, engine app.Engine) *OrderConsumer {
+	return &OrderConsumer{
+		channel:         channel,
+		queue:           queue,
+		deadLetterQueue: deadLetterQueue,
+		engine:          engine,
+	}
+}
+
+// Start consumes the orders queue and feeds every message to the matching
+// engine, one at a time. It blocks until ctx is cancelled or the delivery
+// channel is closed.
+func (c *OrderConsumer) Start(ctx context.Context) error {
+	deliveries, err := c.channel.Consume(
+		c.queue,           // queue
+		"matching-engine", // consumer
+		false,             // auto-ack
+		false,             // exclusive
+		false,             // no-local
+		false,             // no-wait
+		nil,               // args
+	)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case delivery, ok := <-deliveries:
+			if !ok {
+				return errors.New("order deliveries channel closed")
+			}
+			c.handle(ctx, delivery)
+		}
+	}
+}
+
+func (c *OrderConsumer) handle(ctx context.Context, delivery amqp.Delivery) {
+	var err error
+	switch delivery.Type {
+	case orderRepo.MessageTypeAmend:
+		err = c.handleAmend(ctx, delivery.Body)
+	case orderRepo.MessageTypeCancel:
+		err = c.handleCancel(ctx, delivery)
+	case orderRepo.MessageTypeCancelAll:
+		err = c.handleCancelAll(ctx, delivery)
+	case orderRepo.MessageTypeOrder, "":
+		err = c.handleOrder(ctx, delivery.Body)
+	default:
+		err = fmt.Errorf("unknown message type %q", delivery.Type)
+	}
+	if err != nil {
+		slog.Error("error processing order message", "type", delivery.Type, "redelivered", delivery.Redelivered, "error", err)
+		c.fail(ctx, delivery, err)
+		return
+	}
+
+	_ = delivery.Ack(false)
+}
+
+// fail puts a message the engine could not process back on the queue once,
+// for orders and amendments the engine can apply again, and otherwise moves
+// it to the dead letter queue with the error. Cancels were already answered
+// and are not tried again.
+func (c *OrderConsumer) fail(ctx context.Context, delivery amqp.Delivery, cause error) {
+	retry := delivery.Type == orderRepo.MessageTypeOrder || delivery.Type == "" || delivery.Type == orderRepo.MessageTypeAmend
+	if retry && !delivery.Redelivered {
+		_ = delivery.Nack(false, true)
+		return
+	}
+
+	err := c.channel.PublishWithContext(ctx, "", c.deadLetterQueue, false, false, amqp.Publishing{
+		Headers:       amqp.Table{"x-error": cause.Error()},
+		ContentType:   delivery.ContentType,
+		DeliveryMode:  amqp.Persistent,
+		Type:          delivery.Type,
+		CorrelationId: delivery.CorrelationId,
+		Body:          delivery.Body,
+	})
+	if err != nil {
+		slog.Error("error dead-lettering order message", "type", delivery.Type, "error", err)
+		_ = delivery.Nack(false, true)
+		return
+	}
+	_ = delivery.Ack(false)
+}
+
+func (c *OrderConsumer) handleOrder(ctx context.Context, body []byte) error {
+	var model orderRepo.OrderModel
+	if err := json.Unmarshal(body, &model); err != nil {
+		return fmt.Errorf("invalid order message: %w", err)
+	}
+	return c.engine.Submit(ctx, model.ToEntity())
+}
+
+func (c *OrderConsumer) handleAmend(ctx context.Context, body []byte) error {
+	var model orderRepo.AmendModel
+	if err := json.Unmarshal(body, &model); err != nil {
+		return fmt.Errorf("invalid amend message: %w", err)
+	}
+	return c.engine.Amend(ctx, model.ToEntity())
+}
+
+// handleCancel cancels the order and answers on the delivery's reply queue.
+// When the engine fails the sender is told the order was not cancelled.
+func (c *OrderConsumer) handleCancel(ctx context.Context, delivery amqp.Delivery) error {
+	var model orderRepo.CancelModel
+	if err := json.Unmarshal(delivery.Body, &model); err != nil {
+		return fmt.Errorf("invalid cancel message: %w", err)
+	}
+
+	result, err := c.engine.Cancel(ctx, model.OrderID)
+	if err != nil {
+		result = orderEntity.CancelResult{OrderID: model.OrderID, Reason: "engine error"}
+	}
+	if replyErr := c.reply(ctx, delivery, orderRepo.ToCancelResultModel(result)); replyErr != nil {
+		slog.Error("error replying to cancel", "order_id", model.OrderID, "error", replyErr)
+	}
+	return err
+}
+
+// handleCancelAll cancels every matching order and answers with their IDs on
+// the delivery's reply queue.
+func (c *OrderConsumer) handleCancelAll(ctx context.Context, delivery amqp.Delivery) error {
+	var model orderRepo.CancelAllModel
+	if err := json.Unmarshal(delivery.Body, &model); err != nil {
+		return fmt.Errorf("invalid cancel all message: %w", err)
+	}
+
+	ids, err := c.engine.CancelAll(ctx, model.ToEntity())
+	result := orderRepo.CancelAllResultModel{CancelledOrderIDs: ids}
+	if err != nil {
+		result.Error = "engine error"
+	}
+	if replyErr := c.reply(ctx, delivery, result); replyErr != nil {
+		slog.Error("error replying to cancel all", "error", replyErr)
+	}
+	return err
+}
+
+func (c *OrderConsumer) reply(ctx context.Context, delivery amqp.Delivery, message any) error {
+	if delivery.ReplyTo == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return c.channel.PublishWithContext(ctx, "", delivery.ReplyTo, false, false, amqp.Publishing{
+		ContentType:   "application/json",
+		CorrelationId: delivery.CorrelationId,
+		Body:          body,
+	})
+}
@@ -0,0 +1,70 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/domain/entity"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/domain/port"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// EventModel is the JSON body of an engine event.
+type EventModel struct {
+	Type          string     `json:"type"`
+	InstrumentID  string     `json:"instrument_id"`
+	Status        string     `json:"status"`
+	Reason        string     `json:"reason"`
+	LastPrice     *string    `json:"last_price,omitempty"`
+	MovePercent   *string    `json:"move_percent,omitempty"`
+	AuctionEndsAt *time.Time `json:"auction_ends_at,omitempty"`
+	OccurredAt    time.Time  `json:"occurred_at"`
+}
+
+func ToEventModel(event entity.Event) EventModel {
+	model := EventModel{
+		Type:          string(event.Type),
+		InstrumentID:  event.InstrumentID,
+		Status:        event.Status,
+		Reason:        event.Reason,
+		AuctionEndsAt: event.AuctionEndsAt,
+		OccurredAt:    event.OccurredAt,
+	}
+	if event.LastPrice != nil {
+		price := event.LastPrice.Text('f', 10)
+		model.LastPrice = &price
+	}
+	if event.MovePercent != nil {
+		move := event.MovePercent.Text('f', 4)
+		model.MovePercent = &move
+	}
+	return model
+}
+
+type eventPublisher struct {
+	channel  *amqp.Channel
+	exchange string
+}
+
+// NewEventPublisher publishes engine events to a RabbitMQ exchange, routed by
+// event type.
+func NewEventPublisher(channel *amqp.Channel, exchange string) port.EventPublisher {
+	return &eventPublisher{
+		channel:  channel,
+		exchange: exchange,
+	}
+}
+
+func (p *eventPublisher) Publish(ctx context.Context, event entity.Event) error {
+	body, err := json.Marshal(ToEventModel(event))
+	if err != nil {
+		return err
+	}
+	return p.channel.PublishWithContext(ctx, p.exchange, string(event.Type), false, false, amqp.Publishing{
+		ContentType: "application/json",
+		Type:        string(event.Type),
+		Timestamp:   event.OccurredAt,
+		Body:        body,
+	})
+}
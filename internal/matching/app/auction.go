@@ -0,0 +1,38 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// AuctionWorker periodically asks the engine to publish the indicative
+// uncross of every auction and to uncross those whose call phase has ended.
+type AuctionWorker struct {
+	engine   Engine
+	interval time.Duration
+}
+
+func NewAuctionWorker(engine Engine, interval time.Duration) *AuctionWorker {
+	return &AuctionWorker{
+		engine:   engine,
+		interval: interval,
+	}
+}
+
+// Run checks the auctions every interval until ctx is cancelled.
+func (w *AuctionWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := w.engine.Auctions(ctx, now); err != nil {
+				slog.Error("failed to run auctions", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,1353 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	balanceApp "github.com/mthpedrosa/financial-exchange-challenge/internal/balance/app"
+	balancePort "github.com/mthpedrosa/financial-exchange-challenge/internal/balance/domain/port"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/db"
+	feeApp "github.com/mthpedrosa/financial-exchange-challenge/internal/fee/app"
+	feeEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/fee/domain/entity"
+	instrumentEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/domain/entity"
+	instrumentPort "github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/domain/port"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/domain/entity"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/domain/port"
+	orderEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+	orderPort "github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/port"
+	tradePort "github.com/mthpedrosa/financial-exchange-challenge/internal/trade/domain/port"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/decimal"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/ierr"
+)
+
+type Engine interface {
+	Recover(ctx context.Context) error
+	Submit(ctx context.Context, order orderEntity.Order) error
+	Amend(ctx context.Context, amendment orderEntity.Amendment) error
+	Cancel(ctx context.Context, orderID string) (orderEntity.CancelResult, error)
+	CancelAll(ctx context.Context, filter orderEntity.CancelFilter) ([]string, error)
+	Expire(ctx context.Context, now time.Time) error
+	Auctions(ctx context.Context, now time.Time) error
+	Snapshot(instrumentID string, depth int) entity.Snapshot
+}
+
+// auctionEndedReason is recorded as the reason an instrument trades again
+// after its auction uncrossed.
+const auctionEndedReason = "auction uncrossed"
+
+// circuitBreakerReason is recorded as the reason an instrument was paused by
+// its circuit breaker.
+const circuitBreakerReason = "circuit breaker tripped"
+
+// priceTick is the smallest price step the orders table can store. Post-only
+// orders of instruments without a tick size are re-priced by this much when
+// they would cross the book.
+var priceTick, _ = decimal.Parse("0.0000000001")
+
+// Journal writes that failed and the entries they lost, published with the
+// other expvar metrics at /debug/vars.
+var (
+	journalFailures    = expvar.NewInt("engine_journal_failures")
+	journalLostEntries = expvar.NewInt("engine_journal_lost_entries")
+)
+
+type engine struct {
+	mu             sync.Mutex
+	books          map[string]*entity.OrderBook
+	triggers       map[string]*entity.TriggerBook
+	auctions       map[string]entity.Auction
+	windows        map[string]*entity.PriceWindow
+	orderRepo      orderPort.OrderRepository
+	orderListRepo  orderPort.OrderListRepository
+	settlement     balanceApp.Settlement
+	balanceRepo    balancePort.BalanceRepository
+	instrumentRepo instrumentPort.InstrumentRepository
+	tradeRepo      tradePort.TradeRepository
+	fees           feeApp.Fee
+	events         port.EventPublisher
+	journal        port.Journal
+	txManager      db.TxManager
+	// journaled holds the journal entries of the running call until flush.
+	journaled []entity.JournalEntry
+	// stale holds the instruments whose books were dropped after a failed
+	// call; they are rebuilt from the stored orders before the next call.
+	stale map[string]bool
+}
+
+func NewEngine(
+	orderRepo orderPort.OrderRepository,
+	orderListRepo orderPort.OrderListRepository,
+	settlement balanceApp.Settlement,
+	balanceRepo balancePort.BalanceRepository,
+	instrumentRepo instrumentPort.InstrumentRepository,
+	tradeRepo tradePort.TradeRepository,
+	fees feeApp.Fee,
+	events port.EventPublisher,
+	journal port.Journal,
+	txManager db.TxManager,
+) Engine {
+	return &engine{
+		books:          make(map[string]*entity.OrderBook),
+		triggers:       make(map[string]*entity.TriggerBook),
+		auctions:       make(map[string]entity.Auction),
+		windows:        make(map[string]*entity.PriceWindow),
+		stale:          make(map[string]bool),
+		orderRepo:      orderRepo,
+		orderListRepo:  orderListRepo,
+		settlement:     settlement,
+		balanceRepo:    balanceRepo,
+		instrumentRepo: instrumentRepo,
+		tradeRepo:      tradeRepo,
+		fees:           fees,
+		events:         events,
+		journal:        journal,
+		txManager:      txManager,
+	}
+}
+
+// Recover rebuilds the books from the stored orders when the engine starts,
+// before any message is consumed. Orders the engine had booked rest again at
+// their recorded place in the time priority and waiting stop orders return to
+// the trigger book, trailing from where they were. Every other active order
+// never reached the books, or was cut short on its way there, and is
+// submitted again in arrival order; its queue message is skipped once it is
+// redelivered. Each book gets back its last trade price, which may trigger
+// stops right away.
+func (e *engine) Recover(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	defer e.flush(ctx)
+
+	orders, err := e.orderRepo.FindActive(ctx)
+	if err != nil {
+		return err
+	}
+	restored, waiting, resubmitted, err := e.rebuild(ctx, orders)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("order books recovered",
+		"instruments", len(e.books),
+		"resting", restored,
+		"waiting", waiting,
+		"resubmitted", resubmitted,
+	)
+	return nil
+}
+
+// rebuild puts stored active orders, given in the order FindActive returns
+// them, back into books that do not exist yet, as Recover describes. It
+// returns how many orders rest again, wait for their trigger and were
+// submitted again.
+func (e *engine) rebuild(ctx context.Context, orders []orderEntity.Order) (int, int, int, error) {
+	var restored, waiting int
+	var instrumentIDs []string
+	var pending []orderEntity.Order
+	for i := range orders {
+		order := &orders[i]
+		if _, ok := e.books[order.InstrumentID]; !ok {
+			instrumentIDs = append(instrumentIDs, order.InstrumentID)
+		}
+		book, err := e.restoreBook(ctx, order.InstrumentID)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		switch {
+		case order.AwaitsTrigger():
+			if order.Trail(book.LastPrice()) {
+				if err := e.orderRepo.Update(ctx, *order); err != nil {
+					return 0, 0, 0, err
+				}
+			}
+			e.triggerBook(order.InstrumentID).Add(order)
+			waiting++
+		case order.BookSequence > 0:
+			book.Restore(order)
+			restored++
+		default:
+			pending = append(pending, *order)
+		}
+	}
+
+	// stops the restored last trade price already reached
+	sort.Strings(instrumentIDs)
+	for _, instrumentID := range instrumentIDs {
+		status, err := e.status(ctx, instrumentID)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		if err := e.activateStops(ctx, e.books[instrumentID], e.triggerBook(instrumentID), status); err != nil {
+			return 0, 0, 0, err
+		}
+	}
+
+	for _, order := range pending {
+		// triggered stops may have finished the order's list since it was read
+		stored, err := e.orderRepo.FindByID(ctx, order.ID)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		if !stored.IsActive() {
+			continue
+		}
+		if err := e.submit(ctx, e.book(stored.InstrumentID), e.triggerBook(stored.InstrumentID), stored); err != nil {
+			return 0, 0, 0, err
+		}
+	}
+	return restored, waiting, len(pending), nil
+}
+
+// invalidate drops the books of instruments a failed call may have left out
+// of step with the stored orders: the call changed them before its
+// transaction rolled back. refresh rebuilds them before the next call.
+func (e *engine) invalidate(instrumentIDs ...string) {
+	for _, instrumentID := range instrumentIDs {
+		delete(e.books, instrumentID)
+		delete(e.triggers, instrumentID)
+		delete(e.auctions, instrumentID)
+		e.stale[instrumentID] = true
+	}
+	slog.Warn("order books dropped after a failed call, rebuilding from storage", "instrument_ids", instrumentIDs)
+}
+
+// refresh rebuilds the books invalidate dropped from the stored orders, the
+// way Recover does after a restart. A book that fails to rebuild stays
+// dropped and is tried again before the next call.
+func (e *engine) refresh(ctx context.Context) {
+	instrumentIDs := make([]string, 0, len(e.stale))
+	for instrumentID := range e.stale {
+		instrumentIDs = append(instrumentIDs, instrumentID)
+	}
+	sort.Strings(instrumentIDs)
+
+	for _, instrumentID := range instrumentIDs {
+		orders, err := e.orderRepo.FindActiveByInstrumentID(ctx, instrumentID)
+		if err != nil {
+			slog.Error("error rebuilding order book", "instrument_id", instrumentID, "error", err)
+			continue
+		}
+		delete(e.stale, instrumentID)
+		restored, waiting, resubmitted, err := e.rebuild(ctx, orders)
+		if err != nil {
+			e.invalidate(instrumentID)
+			slog.Error("error rebuilding order book", "instrument_id", instrumentID, "error", err)
+			continue
+		}
+		slog.Info("order book rebuilt",
+			"instrument_id", instrumentID,
+			"resting", restored,
+			"waiting", waiting,
+			"resubmitted", resubmitted,
+		)
+	}
+}
+
+// ready fails while the book of the instrument, or of any instrument when
+// instrumentID is empty, is waiting to be rebuilt.
+func (e *engine) ready(instrumentID string) error {
+	for stale := range e.stale {
+		if instrumentID == "" || stale == instrumentID {
+			return fmt.Errorf("order book of instrument %s is being rebuilt", stale)
+		}
+	}
+	return nil
+}
+
+// restoreBook returns the book of an instrument, creating it with the price
+// of the instrument's last trade when it does not exist yet.
+func (e *engine) restoreBook(ctx context.Context, instrumentID string) (*entity.OrderBook, error) {
+	if book, ok := e.books[instrumentID]; ok {
+		return book, nil
+	}
+	instrument, err := e.instrumentRepo.FindByID(ctx, instrumentID)
+	if err != nil {
+		return nil, err
+	}
+	lastPrice, err := e.tradeRepo.LastPrice(ctx, instrument.BaseAsset, instrument.QuoteAsset)
+	if err != nil && !errors.Is(err, ierr.ErrNotFound) {
+		return nil, err
+	}
+
+	book := e.book(instrumentID)
+	book.RestoreLastPrice(lastPrice)
+	return book, nil
+}
+
+// Submit routes an incoming order: stop orders wait in the trigger book until
+// the last trade price reaches their stop price, trailing stops starting to
+// trail from the last trade price, and everything else is executed
+// against the order book right away. Trades can in turn trigger stop orders,
+// which are executed before Submit returns. Orders the instrument's status no
+// longer accepts, e.g. because it was halted while they were queued, are
+// cancelled. When Submit fails its outputs are left out of the journal and
+// the instrument's books are rebuilt from storage, so the order can be
+// submitted again.
+func (e *engine) Submit(ctx context.Context, order orderEntity.Order) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	defer e.flush(ctx)
+	e.record(entity.OrderEntry(entity.JournalNewOrder, order, "", time.Now()))
+
+	e.refresh(ctx)
+	if err := e.ready(order.InstrumentID); err != nil {
+		return err
+	}
+	recorded := len(e.journaled)
+	if err := e.receive(ctx, order); err != nil {
+		// the outputs of the failed call are not journaled
+		e.journaled = e.journaled[:recorded]
+		e.invalidate(order.InstrumentID)
+		return err
+	}
+	return nil
+}
+
+// receive submits an order from the queue unless it was already processed.
+func (e *engine) receive(ctx context.Context, order orderEntity.Order) error {
+	book := e.book(order.InstrumentID)
+	triggers := e.triggerBook(order.InstrumentID)
+	if book.Contains(order.ID) || triggers.Contains(order.ID) {
+		// redelivered message for an order that is already resting
+		return nil
+	}
+
+	// the stored order is the source of truth; skip anything already processed
+	taker, err := e.orderRepo.FindByID(ctx, order.ID)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			slog.Warn("order from queue not found, skipping", "order_id", order.ID)
+			return nil
+		}
+		return err
+	}
+	if taker.Status != orderEntity.OrderStatusOpen {
+		slog.Warn("order is not open, skipping", "order_id", taker.ID, "status", taker.Status)
+		return nil
+	}
+	return e.submit(ctx, book, triggers, taker)
+}
+
+// submit routes an order that has not reached the books yet.
+func (e *engine) submit(ctx context.Context, book *entity.OrderBook, triggers *entity.TriggerBook, taker orderEntity.Order) error {
+	status, err := e.status(ctx, taker.InstrumentID)
+	if err != nil {
+		return err
+	}
+	if err := status.CheckOrder(taker.IsPostOnlyLimit(), taker.IsRestingLimit()); err != nil {
+		slog.Info("instrument does not accept the order, cancelling", "order_id", taker.ID, "status", status)
+		e.record(entity.OrderEntry(entity.JournalRejected, taker, err.Error(), time.Now()))
+		taker.Status = orderEntity.OrderStatusCancelled
+		return e.persist(ctx, &taker, nil, nil)
+	}
+	e.record(entity.OrderEntry(entity.JournalAccepted, taker, "", time.Now()))
+
+	if taker.AwaitsTrigger() && !taker.IsExpired(time.Now()) {
+		if taker.Trail(book.LastPrice()) {
+			if err := e.orderRepo.Update(ctx, taker); err != nil {
+				return err
+			}
+		}
+		triggers.Add(&taker)
+		// a stop price already reached by the last trade triggers right away
+		return e.activateStops(ctx, book, triggers, status)
+	}
+
+	if err := e.execute(ctx, book, &taker, status); err != nil {
+		return err
+	}
+	return e.activateStops(ctx, book, triggers, status)
+}
+
+// status returns the trading status of an instrument.
+func (e *engine) status(ctx context.Context, instrumentID string) (instrumentEntity.InstrumentStatus, error) {
+	instrument, err := e.instrumentRepo.FindByID(ctx, instrumentID)
+	if err != nil {
+		return "", err
+	}
+	return instrument.Status, nil
+}
+
+// execute matches an active order against the book, rests any remainder its
+// time in force allows and persists the outcome of the match. Whatever cannot
+// rest (market and IOC remainders) is cancelled, FOK orders that cannot fill
+// completely are cancelled without trading and GTD orders that are already
+// past their deadline never reach the book. Post-only orders that would cross
+// are cancelled or re-priced so they only ever add liquidity; while the
+// instrument is POST_ONLY every order is handled as one. During an auction
+// orders rest without matching until the book uncrosses. Trailing stops
+// follow the trades, which are then checked against the instrument's circuit
+// breaker.
+func (e *engine) execute(ctx context.Context, book *entity.OrderBook, taker *orderEntity.Order, status instrumentEntity.InstrumentStatus) error {
+	if taker.IsExpired(time.Now()) {
+		slog.Info("order expired before reaching the book", "order_id", taker.ID)
+		e.record(entity.OrderEntry(entity.JournalRejected, *taker, "order expired", time.Now()))
+		taker.Status = orderEntity.OrderStatusCancelled
+		return e.persist(ctx, taker, nil, nil)
+	}
+	if status == instrumentEntity.StatusAuction {
+		book.Add(taker)
+		return e.orderRepo.Update(ctx, *taker)
+	}
+	postOnly := taker.PostOnly || status == instrumentEntity.StatusPostOnly
+	if postOnly && book.Crosses(taker) {
+		instrument, err := e.instrumentRepo.FindByID(ctx, taker.InstrumentID)
+		if err != nil {
+			return err
+		}
+		price := book.MakerPrice(taker, tickSize(instrument))
+		if !taker.RepriceOnCross || price == nil {
+			slog.Info("post-only order would take liquidity, cancelling", "order_id", taker.ID)
+			e.record(entity.OrderEntry(entity.JournalRejected, *taker, "post-only order would take liquidity", time.Now()))
+			taker.Status = orderEntity.OrderStatusCancelled
+			return e.persist(ctx, taker, nil, nil)
+		}
+		return e.reprice(ctx, book, taker, price, instrument)
+	}
+	if taker.TimeInForce == orderEntity.TimeInForceFOK && !book.CanFill(taker) {
+		slog.Info("fill-or-kill order cannot be filled completely, cancelling", "order_id", taker.ID)
+		e.record(entity.OrderEntry(entity.JournalRejected, *taker, "fill-or-kill order cannot be filled completely", time.Now()))
+		taker.Status = orderEntity.OrderStatusCancelled
+		return e.persist(ctx, taker, nil, nil)
+	}
+
+	fills, preventions := book.Match(taker)
+	if taker.IsActive() {
+		if taker.CanRest() {
+			book.Add(taker)
+		} else {
+			taker.Status = orderEntity.OrderStatusCancelled
+		}
+	}
+
+	if err := e.persist(ctx, taker, fills, preventions); err != nil {
+		return err
+	}
+	if err := e.trail(ctx, e.triggerBook(taker.InstrumentID), fills); err != nil {
+		return err
+	}
+	return e.guard(ctx, taker.InstrumentID, fills)
+}
+
+// trail moves the waiting trailing stops along with the price of every fill
+// and stores the trigger prices that moved.
+func (e *engine) trail(ctx context.Context, triggers *entity.TriggerBook, fills []entity.Fill) error {
+	var moved []*orderEntity.Order
+	seen := make(map[string]bool)
+	for _, fill := range fills {
+		for _, order := range triggers.Trail(fill.Price) {
+			if !seen[order.ID] {
+				seen[order.ID] = true
+				moved = append(moved, order)
+			}
+		}
+	}
+
+	for _, order := range moved {
+		slog.Info("trailing stop moved",
+			"order_id", order.ID,
+			"water_mark", order.WaterMark.Text('f', 10),
+			"trigger_price", order.StopPrice.Text('f', 10),
+		)
+		if err := e.orderRepo.Update(ctx, *order); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// guard records the prices of new fills and pauses the instrument when they
+// moved more than its circuit breaker allows within its window: it enters a
+// short auction or is halted, and the pause is published as an event.
+func (e *engine) guard(ctx context.Context, instrumentID string, fills []entity.Fill) error {
+	if len(fills) == 0 {
+		return nil
+	}
+	instrument, err := e.instrumentRepo.FindByID(ctx, instrumentID)
+	if err != nil {
+		return err
+	}
+	if !instrument.HasCircuitBreaker() {
+		return nil
+	}
+
+	now := time.Now()
+	window, ok := e.windows[instrumentID]
+	if !ok {
+		window = &entity.PriceWindow{}
+		e.windows[instrumentID] = window
+	}
+	for _, fill := range fills {
+		window.Record(now, fill.Price)
+	}
+	move := window.Move(now.Add(-instrument.CircuitBreakerWindow))
+	if move.Cmp(instrument.CircuitBreakerPercent) <= 0 {
+		return nil
+	}
+
+	change, err := instrument.TripCircuitBreaker(now, circuitBreakerReason)
+	if err != nil {
+		return err
+	}
+	err = e.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := e.instrumentRepo.UpdateStatus(ctx, change); err != nil {
+			return err
+		}
+		_, err := e.instrumentRepo.CreateStatusChange(ctx, change)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	window.Reset()
+
+	lastPrice := fills[len(fills)-1].Price
+	slog.Warn("circuit breaker tripped",
+		"instrument_id", instrumentID,
+		"status", change.To,
+		"last_price", lastPrice.Text('f', 10),
+		"move_percent", move.Text('f', 4),
+	)
+	event := entity.Event{
+		Type:          entity.EventCircuitBreakerTripped,
+		InstrumentID:  instrumentID,
+		Status:        string(change.To),
+		Reason:        change.Reason,
+		LastPrice:     lastPrice,
+		MovePercent:   move,
+		AuctionEndsAt: change.AuctionEndsAt,
+		OccurredAt:    now,
+	}
+	if err := e.events.Publish(ctx, event); err != nil {
+		// the pause stands; only its announcement is lost
+		slog.Error("error publishing circuit breaker event", "instrument_id", instrumentID, "error", err)
+	}
+	return nil
+}
+
+// activateStops triggers every stop order whose stop price the last trade
+// reached, records the TRIGGERED transition and executes the order. Trades
+// made by triggered orders can trigger further stops, so it repeats until no
+// stop is left to trigger. Stops only trigger while the instrument trades
+// freely; once a triggered order trips the circuit breaker, the stops not yet
+// executed go back to wait. Triggering a stop order of an OCO list cancels the
+// other order of the list.
+func (e *engine) activateStops(ctx context.Context, book *entity.OrderBook, triggers *entity.TriggerBook, status instrumentEntity.InstrumentStatus) error {
+	if status != instrumentEntity.StatusTrading {
+		return nil
+	}
+	for {
+		triggered := triggers.Triggered(book.LastPrice())
+		if len(triggered) == 0 {
+			return nil
+		}
+
+		for i, order := range triggered {
+			if !order.IsActive() {
+				// cancelled along with an order of its list
+				continue
+			}
+
+			// an earlier trade may have tripped the circuit breaker
+			instrument, err := e.instrumentRepo.FindByID(ctx, book.InstrumentID)
+			if err != nil {
+				return err
+			}
+			if status = instrument.Status; status != instrumentEntity.StatusTrading {
+				for _, waiting := range triggered[i:] {
+					triggers.Add(waiting)
+				}
+				return nil
+			}
+
+			order.Trigger(time.Now())
+			slog.Info("stop order triggered",
+				"order_id", order.ID,
+				"stop_price", order.StopPrice.Text('f', 10),
+				"last_price", book.LastPrice().Text('f', 10),
+			)
+			var sibling *orderEntity.Order
+			err = e.txManager.WithTx(ctx, func(ctx context.Context) error {
+				if err := e.orderRepo.Update(ctx, *order); err != nil {
+					return err
+				}
+				sibling, err = e.finishList(ctx, order, instrument)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			if sibling != nil {
+				e.record(entity.DoneEntry(*sibling, time.Now()))
+			}
+			if err := e.execute(ctx, book, order, status); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Amend applies an amendment to a working order and settles the difference in
+// its reservation: Amendment.Reserved was locked up front by the API, anything
+// the amended order needs beyond that is reserved here and anything it no
+// longer needs is released. A quantity reduction at the same price keeps the
+// order's place in the queue; any other change re-enters it as if it had just
+// arrived, so it may trade right away. Amendments that can no longer be
+// applied are dropped and their up-front reservation is released, and an
+// amendment that was already applied is skipped. When Amend fails its
+// outputs are left out of the journal and the instrument's books are rebuilt
+// from storage, so the amendment can be applied again.
+func (e *engine) Amend(ctx context.Context, amendment orderEntity.Amendment) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	defer e.flush(ctx)
+	e.record(entity.JournalEntry{
+		Type:         entity.JournalAmend,
+		InstrumentID: amendment.InstrumentID,
+		OrderID:      amendment.OrderID,
+		Amendment:    &amendment,
+		RecordedAt:   time.Now(),
+	})
+
+	e.refresh(ctx)
+	if err := e.ready(amendment.InstrumentID); err != nil {
+		return err
+	}
+	recorded := len(e.journaled)
+	if err := e.amend(ctx, amendment); err != nil {
+		// the outputs of the failed call are not journaled
+		e.journaled = e.journaled[:recorded]
+		e.invalidate(amendment.InstrumentID)
+		return err
+	}
+	return nil
+}
+
+func (e *engine) amend(ctx context.Context, amendment orderEntity.Amendment) error {
+	stored, err := e.orderRepo.FindByID(ctx, amendment.OrderID)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			slog.Warn("amended order not found, skipping", "order_id", amendment.OrderID)
+			return nil
+		}
+		return err
+	}
+	if amendment.ID != "" && stored.AmendmentID == amendment.ID {
+		// redelivered message for an amendment that is already applied
+		slog.Warn("amendment already applied, skipping", "order_id", stored.ID, "amendment_id", amendment.ID)
+		return nil
+	}
+	instrument, err := e.instrumentRepo.FindByID(ctx, stored.InstrumentID)
+	if err != nil {
+		return err
+	}
+	asset := stored.ReservedAsset(instrument.BaseAsset, instrument.QuoteAsset)
+
+	book := e.book(stored.InstrumentID)
+	triggers := e.triggerBook(stored.InstrumentID)
+	order, inBook := book.Order(stored.ID)
+	if !inBook {
+		var waiting bool
+		if order, waiting = triggers.Order(stored.ID); !waiting {
+			order = &stored
+		}
+	}
+
+	reject := func(reason string) error {
+		slog.Info("amendment rejected", "order_id", stored.ID, "reason", reason)
+		e.record(entity.JournalEntry{
+			Type:         entity.JournalRejected,
+			InstrumentID: stored.InstrumentID,
+			OrderID:      stored.ID,
+			Amendment:    &amendment,
+			Reason:       reason,
+			RecordedAt:   time.Now(),
+		})
+		if amendment.Reserved.Sign() <= 0 {
+			return nil
+		}
+		return e.balanceRepo.Release(ctx, stored.AccountID, asset, amendment.Reserved)
+	}
+
+	if !stored.IsActive() {
+		return reject("order is " + string(stored.Status))
+	}
+	if !instrument.Status.AcceptsOrders() {
+		return reject("instrument is " + string(instrument.Status))
+	}
+	amended, err := order.Amended(amendment)
+	if err != nil {
+		return reject(err.Error())
+	}
+	amended.AmendmentID = amendment.ID
+	if inBook && !order.KeepsPriority(amended) {
+		// stored without a place in the book, a rebuild submits it again
+		amended.BookSequence = 0
+	}
+
+	// what the order holds now plus the up-front reservation, against what the
+	// amended order needs
+	held := new(big.Float).Add(order.ReservedAmount(), amendment.Reserved)
+	delta := new(big.Float).Sub(amended.ReservedAmount(), held)
+	err = e.txManager.WithTx(ctx, func(ctx context.Context) error {
+		switch delta.Sign() {
+		case 1:
+			if err := e.balanceRepo.Reserve(ctx, stored.AccountID, asset, delta); err != nil {
+				return err
+			}
+		case -1:
+			if err := e.balanceRepo.Release(ctx, stored.AccountID, asset, new(big.Float).Neg(delta)); err != nil {
+				return err
+			}
+		}
+		return e.orderRepo.Update(ctx, amended)
+	})
+	if err != nil {
+		if errors.Is(err, ierr.ErrInsufficientBalance) {
+			return reject(err.Error())
+		}
+		return err
+	}
+
+	slog.Info("order amended",
+		"order_id", stored.ID,
+		"price", amended.Price.Text('f', 10),
+		"quantity", amended.Quantity.Text('f', 18),
+	)
+	e.record(entity.OrderEntry(entity.JournalAccepted, amended, "", time.Now()))
+
+	if !inBook || order.KeepsPriority(amended) {
+		*order = amended
+		return nil
+	}
+
+	// losing priority: take the order out and let it arrive again
+	book.Remove(order.ID)
+	*order = amended
+	if err := e.execute(ctx, book, order, instrument.Status); err != nil {
+		return err
+	}
+	return e.activateStops(ctx, book, triggers, instrument.Status)
+}
+
+// Cancel takes an active order out of the book or the trigger book, marks it
+// CANCELLED and releases its reservation. Orders that already reached a
+// terminal state are left alone and reported as not cancelled. Cancelling an
+// order of an OCO list cancels the other order too.
+func (e *engine) Cancel(ctx context.Context, orderID string) (orderEntity.CancelResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	defer e.flush(ctx)
+	e.record(entity.JournalEntry{Type: entity.JournalCancel, OrderID: orderID, RecordedAt: time.Now()})
+	e.refresh(ctx)
+
+	stored, err := e.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return orderEntity.CancelResult{OrderID: orderID, Reason: "order not found"}, nil
+		}
+		return orderEntity.CancelResult{}, err
+	}
+	if err := e.ready(stored.InstrumentID); err != nil {
+		return orderEntity.CancelResult{}, err
+	}
+	if stored.IsTerminal() {
+		// a fill or an earlier cancel won the race
+		return orderEntity.CancelResult{OrderID: orderID, Status: stored.Status, Reason: "order is " + string(stored.Status)}, nil
+	}
+
+	book := e.book(stored.InstrumentID)
+	triggers := e.triggerBook(stored.InstrumentID)
+	order, ok := book.Order(orderID)
+	if !ok {
+		if order, ok = triggers.Order(orderID); !ok {
+			order = &stored
+		}
+	}
+
+	cancelled := *order
+	if err := cancelled.Cancel(); err != nil {
+		return orderEntity.CancelResult{OrderID: orderID, Status: stored.Status, Reason: err.Error()}, nil
+	}
+	recorded := len(e.journaled)
+	if err := e.persist(ctx, &cancelled, nil, nil); err != nil {
+		// finishing the order's list may have changed the books
+		e.journaled = e.journaled[:recorded]
+		e.invalidate(stored.InstrumentID)
+		return orderEntity.CancelResult{}, err
+	}
+
+	book.Remove(orderID)
+	triggers.Remove(orderID)
+	*order = cancelled
+	slog.Info("order cancelled", "order_id", orderID)
+
+	return orderEntity.CancelResult{OrderID: orderID, Cancelled: true, Status: cancelled.Status}, nil
+}
+
+// CancelAll cancels every resting and waiting order matching the filter in a
+// single transaction and returns their IDs. Either all of them are cancelled
+// or, on error, none are and the books they rest in are rebuilt from storage.
+func (e *engine) CancelAll(ctx context.Context, filter orderEntity.CancelFilter) ([]string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	defer e.flush(ctx)
+	e.record(entity.JournalEntry{
+		Type:         entity.JournalCancelAll,
+		InstrumentID: filter.InstrumentID,
+		CancelFilter: &filter,
+		RecordedAt:   time.Now(),
+	})
+	recorded := len(e.journaled)
+
+	e.refresh(ctx)
+	if err := e.ready(filter.InstrumentID); err != nil {
+		return nil, err
+	}
+
+	var selected []*orderEntity.Order
+	for _, instrumentID := range e.instrumentIDs() {
+		if filter.InstrumentID != "" && instrumentID != filter.InstrumentID {
+			continue
+		}
+		orders := append(e.books[instrumentID].Orders(), e.triggerBook(instrumentID).Orders()...)
+		for _, order := range orders {
+			if filter.Matches(order) && order.IsActive() {
+				selected = append(selected, order)
+			}
+		}
+	}
+
+	cancelled := make([]orderEntity.Order, len(selected))
+	err := e.txManager.WithTx(ctx, func(ctx context.Context) error {
+		for i, order := range selected {
+			cancelled[i] = *order
+			if !order.IsActive() {
+				// cancelled along with an earlier order of its list
+				continue
+			}
+			if err := cancelled[i].Cancel(); err != nil {
+				return err
+			}
+			if err := e.persist(ctx, &cancelled[i], nil, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// none of the orders was cancelled after all, but finishing their
+		// lists already took the other orders out of the books
+		e.journaled = e.journaled[:recorded]
+		instrumentIDs := make(map[string]bool)
+		for _, order := range selected {
+			if !instrumentIDs[order.InstrumentID] {
+				instrumentIDs[order.InstrumentID] = true
+				e.invalidate(order.InstrumentID)
+			}
+		}
+		return nil, err
+	}
+
+	ids := make([]string, len(selected))
+	for i, order := range selected {
+		e.books[order.InstrumentID].Remove(order.ID)
+		e.triggerBook(order.InstrumentID).Remove(order.ID)
+		*order = cancelled[i]
+		ids[i] = order.ID
+	}
+	slog.Info("orders cancelled in bulk",
+		"account_id", filter.AccountID,
+		"instrument_id", filter.InstrumentID,
+		"side", filter.Side,
+		"count", len(ids),
+	)
+	return ids, nil
+}
+
+// Expire cancels every resting or waiting GTD order whose deadline has passed
+// at now and releases what it still had reserved. When Expire fails the
+// instrument's books are rebuilt from storage and its orders expire on the
+// next run.
+func (e *engine) Expire(ctx context.Context, now time.Time) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	defer e.flush(ctx)
+	e.refresh(ctx)
+
+	for instrumentID, book := range e.books {
+		triggers := e.triggerBook(instrumentID)
+		expired := append(book.Expired(now), triggers.Expired(now)...)
+		for _, order := range expired {
+			if !order.IsActive() {
+				// cancelled along with an earlier order of its list
+				continue
+			}
+			book.Remove(order.ID)
+			triggers.Remove(order.ID)
+			order.Status = orderEntity.OrderStatusCancelled
+			slog.Info("order expired", "order_id", order.ID, "expires_at", order.ExpiresAt)
+			if err := e.persist(ctx, order, nil, nil); err != nil {
+				e.invalidate(instrumentID)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Auctions publishes the indicative uncross of every instrument in an
+// auction and uncrosses those whose call phase has ended at now, after which
+// they trade.
+func (e *engine) Auctions(ctx context.Context, now time.Time) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	defer e.flush(ctx)
+	e.refresh(ctx)
+
+	instruments, err := e.instrumentRepo.FindAll(ctx, &instrumentEntity.InstrumentFilter{Status: instrumentEntity.StatusAuction})
+	if err != nil {
+		return err
+	}
+
+	// instruments that left the auction no longer publish an indicative price
+	auctions := make(map[string]entity.Auction, len(instruments))
+	defer func() { e.auctions = auctions }()
+
+	for _, instrument := range instruments {
+		if e.ready(instrument.ID) != nil {
+			// uncrossed once its book is rebuilt
+			continue
+		}
+		book := e.book(instrument.ID)
+		if instrument.AuctionEndsAt != nil && now.Before(*instrument.AuctionEndsAt) {
+			auction := book.Indicative()
+			auctions[instrument.ID] = auction
+			slog.Info("indicative uncross",
+				"instrument_id", instrument.ID,
+				"price", formatPrice(auction.Price),
+				"quantity", auction.Quantity.Text('f', 18),
+				"ends_at", instrument.AuctionEndsAt,
+			)
+			continue
+		}
+		if err := e.uncross(ctx, book, instrument); err != nil {
+			// the book already executed the uncross the transaction undid
+			e.invalidate(instrument.ID)
+			return err
+		}
+	}
+	return nil
+}
+
+// uncross ends an instrument's auction: the book executes at its clearing
+// price through the usual settlement and the instrument moves to TRADING in
+// the same transaction. Stops the uncross reached trigger right after. When
+// uncross fails the book is rebuilt from storage, still in its auction, and
+// uncrossed again on the next run.
+func (e *engine) uncross(ctx context.Context, book *entity.OrderBook, instrument *instrumentEntity.Instrument) error {
+	change, err := instrument.EndAuction(auctionEndedReason)
+	if err != nil {
+		return err
+	}
+
+	auction, fills, preventions := book.Uncross()
+	recorded := len(e.journaled)
+	err = e.txManager.WithTx(ctx, func(ctx context.Context) error {
+		for _, outcome := range byTaker(fills, preventions) {
+			if err := e.persist(ctx, outcome.taker, outcome.fills, outcome.preventions); err != nil {
+				return err
+			}
+		}
+		if err := e.instrumentRepo.UpdateStatus(ctx, change); err != nil {
+			return err
+		}
+		_, err := e.instrumentRepo.CreateStatusChange(ctx, change)
+		return err
+	})
+	if err != nil {
+		e.journaled = e.journaled[:recorded]
+		return err
+	}
+
+	slog.Info("auction uncrossed",
+		"instrument_id", instrument.ID,
+		"price", formatPrice(auction.Price),
+		"quantity", auction.Quantity.Text('f', 18),
+		"fills", len(fills),
+	)
+	triggers := e.triggerBook(instrument.ID)
+	if err := e.trail(ctx, triggers, fills); err != nil {
+		return err
+	}
+	return e.activateStops(ctx, book, triggers, instrumentEntity.StatusTrading)
+}
+
+// takerOutcome is what an uncross did to one taker.
+type takerOutcome struct {
+	taker       *orderEntity.Order
+	fills       []entity.Fill
+	preventions []entity.Prevention
+}
+
+// byTaker groups the fills and preventions of an uncross by taker, in the
+// order each taker first appears, so every taker is persisted, and its
+// leftover released, exactly once.
+func byTaker(fills []entity.Fill, preventions []entity.Prevention) []*takerOutcome {
+	var outcomes []*takerOutcome
+	index := make(map[string]*takerOutcome)
+	outcome := func(taker *orderEntity.Order) *takerOutcome {
+		if o, ok := index[taker.ID]; ok {
+			return o
+		}
+		o := &takerOutcome{taker: taker}
+		index[taker.ID] = o
+		outcomes = append(outcomes, o)
+		return o
+	}
+	for _, fill := range fills {
+		o := outcome(fill.Taker)
+		o.fills = append(o.fills, fill)
+	}
+	for _, prevention := range preventions {
+		o := outcome(prevention.Taker)
+		o.preventions = append(o.preventions, prevention)
+	}
+	return outcomes
+}
+
+// Snapshot returns the public view of an instrument's order book, with its
+// indicative uncross while it is in an auction.
+func (e *engine) Snapshot(instrumentID string, depth int) entity.Snapshot {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	book, ok := e.books[instrumentID]
+	if !ok {
+		return entity.NewOrderBook(instrumentID).Snapshot(depth)
+	}
+	snapshot := book.Snapshot(depth)
+	if snapshot.LastPrice != nil {
+		snapshot.LastPrice = new(big.Float).Set(snapshot.LastPrice)
+	}
+	if auction, ok := e.auctions[instrumentID]; ok {
+		snapshot.Auction = &auction
+	}
+	return snapshot
+}
+
+// persist charges fees on and settles every fill, applies every self-trade
+// prevention, records the order updates produced by a match and releases
+// whatever the taker still has reserved once it is done, all in a single
+// transaction. Orders of an OCO list that traded or are done finish their
+// list in the same transaction.
+func (e *engine) persist(ctx context.Context, taker *orderEntity.Order, fills []entity.Fill, preventions []entity.Prevention) error {
+	if len(fills) == 0 && len(preventions) == 0 && taker.IsActive() {
+		// nothing traded; only the order's place in the book is new
+		return e.orderRepo.Update(ctx, *taker)
+	}
+
+	instrument, err := e.instrumentRepo.FindByID(ctx, taker.InstrumentID)
+	if err != nil {
+		return err
+	}
+
+	rates := make(map[string]feeEntity.Rates)
+	ratesFor := func(accountID string) (feeEntity.Rates, error) {
+		if r, ok := rates[accountID]; ok {
+			return r, nil
+		}
+		r, err := e.fees.Rates(ctx, accountID, taker.InstrumentID)
+		if err != nil {
+			return feeEntity.Rates{}, err
+		}
+		rates[accountID] = r
+		return r, nil
+	}
+	for i := range fills {
+		makerRates, err := ratesFor(fills[i].Maker.AccountID)
+		if err != nil {
+			return err
+		}
+		takerRates, err := ratesFor(fills[i].Taker.AccountID)
+		if err != nil {
+			return err
+		}
+		fills[i].ChargeFees(instrument.BaseAsset, instrument.QuoteAsset, makerRates.MakerBps, takerRates.TakerBps)
+	}
+
+	// other orders of OCO lists cancelled on the way, journaled once committed
+	var siblings []*orderEntity.Order
+	err = e.txManager.WithTx(ctx, func(ctx context.Context) error {
+		for _, fill := range fills {
+			slog.Info("order matched",
+				"instrument_id", taker.InstrumentID,
+				"maker_order_id", fill.Maker.ID,
+				"taker_order_id", fill.Taker.ID,
+				"price", fill.Price.Text('f', 10),
+				"quantity", fill.Quantity.Text('f', 18),
+				"maker_fee", fill.MakerFee.Text('f', 18),
+				"taker_fee", fill.TakerFee.Text('f', 18),
+			)
+			settlement := fill.ToSettlement(instrument.BaseAsset, instrument.QuoteAsset)
+			if _, err := e.settlement.Settle(ctx, settlement, fill.ToTrade()); err != nil {
+				return err
+			}
+			if err := e.orderRepo.Update(ctx, *fill.Maker); err != nil {
+				return err
+			}
+		}
+
+		for _, prevention := range preventions {
+			if err := e.prevent(ctx, prevention, instrument.BaseAsset, instrument.QuoteAsset); err != nil {
+				return err
+			}
+		}
+
+		if !taker.IsActive() {
+			if err := e.releaseLeftover(ctx, taker, instrument.BaseAsset, instrument.QuoteAsset); err != nil {
+				return err
+			}
+		}
+
+		if err := e.orderRepo.Update(ctx, *taker); err != nil {
+			return err
+		}
+
+		var finished []*orderEntity.Order
+		if len(fills) > 0 || !taker.IsActive() {
+			finished = append(finished, taker)
+		}
+		for _, fill := range fills {
+			finished = append(finished, fill.Maker)
+		}
+		for _, prevention := range preventions {
+			if !prevention.Maker.IsActive() {
+				finished = append(finished, prevention.Maker)
+			}
+		}
+		for _, order := range finished {
+			sibling, err := e.finishList(ctx, order, instrument)
+			if err != nil {
+				return err
+			}
+			if sibling != nil {
+				siblings = append(siblings, sibling)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	e.record(entity.MatchEntries(taker, fills, preventions, now)...)
+	for _, sibling := range siblings {
+		e.record(entity.DoneEntry(*sibling, now))
+	}
+	return nil
+}
+
+// finishList ends the OCO list of an order that traded, was triggered or is
+// done: the other order of the list is taken out of the books and cancelled,
+// releasing what it holds less the part of the reservation both orders share,
+// and the list is ALL_DONE. Orders outside a list and lists that are already
+// done are left alone. It returns the other order when it cancelled it, for
+// the caller to journal once the transaction commits.
+func (e *engine) finishList(ctx context.Context, order *orderEntity.Order, instrument *instrumentEntity.Instrument) (*orderEntity.Order, error) {
+	if order.OrderListID == "" {
+		return nil, nil
+	}
+	list, err := e.orderListRepo.FindForUpdate(ctx, order.OrderListID)
+	if err != nil {
+		return nil, err
+	}
+	if !list.IsExecuting() {
+		return nil, nil
+	}
+
+	siblingID := list.Sibling(order.ID)
+	book := e.book(order.InstrumentID)
+	triggers := e.triggerBook(order.InstrumentID)
+	sibling, ok := book.Order(siblingID)
+	if !ok {
+		if sibling, ok = triggers.Order(siblingID); !ok {
+			// not submitted yet: the engine skips it once it arrives cancelled
+			stored, err := e.orderRepo.FindByID(ctx, siblingID)
+			if err != nil {
+				return nil, err
+			}
+			sibling = &stored
+		}
+	}
+
+	if !sibling.IsActive() {
+		return nil, e.orderListRepo.UpdateStatus(ctx, list.ID, orderEntity.OrderListStatusAllDone)
+	}
+	released := list.Unshared(sibling.ReservedAmount())
+	sibling.Status = orderEntity.OrderStatusCancelled
+	if released.Sign() > 0 {
+		asset := sibling.ReservedAsset(instrument.BaseAsset, instrument.QuoteAsset)
+		if err := e.balanceRepo.Release(ctx, sibling.AccountID, asset, released); err != nil {
+			return nil, err
+		}
+	}
+	if err := e.orderRepo.Update(ctx, *sibling); err != nil {
+		return nil, err
+	}
+	book.Remove(sibling.ID)
+	triggers.Remove(sibling.ID)
+	slog.Info("order list done, other order cancelled",
+		"order_list_id", list.ID,
+		"order_id", order.ID,
+		"cancelled_order_id", sibling.ID,
+	)
+
+	return sibling, e.orderListRepo.UpdateStatus(ctx, list.ID, orderEntity.OrderListStatusAllDone)
+}
+
+// prevent records a self-trade prevention: it releases what the decremented
+// orders no longer need and what a cancelled maker still had reserved. The
+// taker is left to persist.
+func (e *engine) prevent(ctx context.Context, prevention entity.Prevention, baseAsset, quoteAsset string) error {
+	maker, taker := prevention.Maker, prevention.Taker
+	slog.Info("self-trade prevented",
+		"account_id", taker.AccountID,
+		"mode", prevention.Mode,
+		"maker_order_id", maker.ID,
+		"taker_order_id", taker.ID,
+	)
+
+	if prevention.MakerReleased != nil && prevention.MakerReleased.Sign() > 0 {
+		asset := maker.ReservedAsset(baseAsset, quoteAsset)
+		if err := e.balanceRepo.Release(ctx, maker.AccountID, asset, prevention.MakerReleased); err != nil {
+			return err
+		}
+	}
+	if prevention.TakerReleased != nil && prevention.TakerReleased.Sign() > 0 {
+		asset := taker.ReservedAsset(baseAsset, quoteAsset)
+		if err := e.balanceRepo.Release(ctx, taker.AccountID, asset, prevention.TakerReleased); err != nil {
+			return err
+		}
+	}
+	if !maker.IsActive() {
+		if err := e.releaseLeftover(ctx, maker, baseAsset, quoteAsset); err != nil {
+			return err
+		}
+	}
+	return e.orderRepo.Update(ctx, *maker)
+}
+
+// reprice moves an order to price, rests it in the book and, for buys,
+// releases the part of the reservation the lower price no longer needs. The
+// price is kept on the instrument's tick grid, behind the opposite side.
+func (e *engine) reprice(ctx context.Context, book *entity.OrderBook, order *orderEntity.Order, price *big.Float, instrument *instrumentEntity.Instrument) error {
+	// a best opposite price off the grid may leave no tick to round a buy down to
+	if rounded := entity.RoundToTick(order, price, tickSize(instrument)); rounded != nil {
+		price = rounded
+	}
+	// round to the stored scale so the book and the table agree on the price
+	price = decimal.Round(price, decimal.PriceScale)
+	reservedBefore := order.ReservedAmount()
+	slog.Info("post-only order re-priced", "order_id", order.ID, "from", order.Price.Text('f', 10), "to", price.Text('f', 10))
+	order.Price = price
+	excess := new(big.Float).Sub(reservedBefore, order.ReservedAmount())
+	book.Add(order)
+
+	return e.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if excess.Sign() > 0 {
+			asset := order.ReservedAsset(instrument.BaseAsset, instrument.QuoteAsset)
+			if err := e.balanceRepo.Release(ctx, order.AccountID, asset, excess); err != nil {
+				return err
+			}
+		}
+		return e.orderRepo.Update(ctx, *order)
+	})
+}
+
+// releaseLeftover unlocks whatever a finished order still has reserved, such
+// as the remainder of a cancelled order or the dust of a quote budget.
+func (e *engine) releaseLeftover(ctx context.Context, order *orderEntity.Order, baseAsset, quoteAsset string) error {
+	leftover := order.ReservedAmount()
+	if leftover.Sign() <= 0 {
+		return nil
+	}
+	asset := order.ReservedAsset(baseAsset, quoteAsset)
+	return e.balanceRepo.Release(ctx, order.AccountID, asset, leftover)
+}
+
+// record keeps journal entries of the running call; they are written together
+// by flush once the call returns.
+func (e *engine) record(entries ...entity.JournalEntry) {
+	e.journaled = append(e.journaled, entries...)
+}
+
+// flush writes the journal entries of the call that just ran in a single
+// batch, even when the call's context was cancelled on its way out. Postgres,
+// not the journal, is the record of what the engine did: like an event, an
+// entry that cannot be written is only lost from the journal and what the
+// engine did stands. Lost entries are counted in the journal metrics.
+func (e *engine) flush(ctx context.Context) {
+	if len(e.journaled) == 0 {
+		return
+	}
+	if err := e.journal.Append(context.WithoutCancel(ctx), e.journaled...); err != nil {
+		journalFailures.Add(1)
+		journalLostEntries.Add(int64(len(e.journaled)))
+		slog.Error("error writing engine journal", "entries", len(e.journaled), "error", err)
+	}
+	e.journaled = nil
+}
+
+// tickSize returns the price step of an instrument: its tick size, or
+// priceTick when it has none.
+func tickSize(instrument *instrumentEntity.Instrument) *big.Float {
+	if instrument.TickSize != nil && instrument.TickSize.Sign() > 0 {
+		return instrument.TickSize
+	}
+	return priceTick
+}
+
+// formatPrice formats a price for logging; prices may be unset.
+func formatPrice(price *big.Float) string {
+	if price == nil {
+		return ""
+	}
+	return price.Text('f', 10)
+}
+
+func (e *engine) book(instrumentID string) *entity.OrderBook {
+	book, ok := e.books[instrumentID]
+	if !ok {
+		book = entity.NewOrderBook(instrumentID)
+		e.books[instrumentID] = book
+	}
+	return book
+}
+
+// instrumentIDs returns the instruments with a book, sorted so bulk
+// operations always walk them in the same order.
+func (e *engine) instrumentIDs() []string {
+	ids := make([]string, 0, len(e.books))
+	for id := range e.books {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (e *engine) triggerBook(instrumentID string) *entity.TriggerBook {
+	triggers, ok := e.triggers[instrumentID]
+	if !ok {
+		triggers = entity.NewTriggerBook(instrumentID)
+		e.triggers[instrumentID] = triggers
+	}
+	return triggers
+}
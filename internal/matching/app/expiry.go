@@ -0,0 +1,38 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// ExpiryWorker periodically asks the engine to cancel GTD orders whose
+// deadline has passed.
+type ExpiryWorker struct {
+	engine   Engine
+	interval time.Duration
+}
+
+func NewExpiryWorker(engine Engine, interval time.Duration) *ExpiryWorker {
+	return &ExpiryWorker{
+		engine:   engine,
+		interval: interval,
+	}
+}
+
+// Run checks for expired orders every interval until ctx is cancelled.
+func (w *ExpiryWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := w.engine.Expire(ctx, now); err != nil {
+				slog.Error("failed to expire orders", "error", err)
+			}
+		}
+	}
+}
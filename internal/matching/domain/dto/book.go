@@ -0,0 +1,22 @@
+package dto
+
+import "math/big"
+
+// BookDTO is a public snapshot of an instrument's order book. Iceberg orders
+// only contribute their visible slice. While the instrument is in an auction,
+// IndicativePrice and IndicativeQuantity are the uncross it would produce at
+// the last check; IndicativePrice is left out when the book does not cross.
+type BookDTO struct {
+	InstrumentID       string          `json:"instrument_id"`
+	Bids               []PriceLevelDTO `json:"bids"`
+	Asks               []PriceLevelDTO `json:"asks"`
+	LastPrice          *big.Float      `json:"last_price,omitempty"`
+	IndicativePrice    *big.Float      `json:"indicative_price,omitempty"`
+	IndicativeQuantity *big.Float      `json:"indicative_quantity,omitempty"`
+}
+
+type PriceLevelDTO struct {
+	Price    big.Float `json:"price"`
+	Quantity big.Float `json:"quantity"`
+	Orders   int       `json:"orders"`
+}
@@ -0,0 +1,172 @@
+package entity
+
+import (
+	"math/big"
+	"sort"
+
+	orderEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+)
+
+// Auction is the uncross an auction book would produce right now: the single
+// clearing price and the quantity that would execute at it. Price is nil when
+// the book does not cross.
+type Auction struct {
+	InstrumentID string
+	Price        *big.Float
+	Quantity     *big.Float
+}
+
+// Indicative returns the auction the book would uncross at right now without
+// changing it. The clearing price is the limit price that executes the most
+// quantity; ties go to the price that leaves the smallest imbalance between
+// demand and supply, then to the one closest to the last trade price and
+// finally to the lowest.
+func (b *OrderBook) Indicative() Auction {
+	auction := Auction{InstrumentID: b.InstrumentID, Quantity: new(big.Float)}
+	var bestImbalance *big.Float
+	for _, price := range b.limitPrices() {
+		demand, supply := b.bids.volumeAt(price), b.asks.volumeAt(price)
+		volume := minFloat(demand, supply)
+		if volume.Sign() <= 0 {
+			continue
+		}
+		imbalance := new(big.Float).Abs(new(big.Float).Sub(demand, supply))
+
+		better := auction.Price == nil
+		if !better {
+			switch volume.Cmp(auction.Quantity) {
+			case 1:
+				better = true
+			case 0:
+				switch imbalance.Cmp(bestImbalance) {
+				case -1:
+					better = true
+				case 0:
+					better = b.closerToLast(price, auction.Price)
+				}
+			}
+		}
+		if better {
+			auction.Price = price
+			auction.Quantity = volume
+			bestImbalance = imbalance
+		}
+	}
+	if auction.Price != nil {
+		auction.Price = new(big.Float).Set(auction.Price)
+	}
+	return auction
+}
+
+// Uncross executes the auction at its clearing price: bids and asks are
+// matched in price-time priority, every fill at the clearing price, until the
+// auction quantity is done. Of each pair the order that arrived last is the
+// taker. Iceberg orders take part with their whole size. Orders keep their
+// place in the book with whatever they have left.
+//
+// Two orders of the same account whose newest has a self-trade prevention
+// policy never trade; the newest is cancelled whatever its policy, as the
+// uncross cannot keep matching around it. It is reported as a Prevention.
+func (b *OrderBook) Uncross() (Auction, []Fill, []Prevention) {
+	auction := b.Indicative()
+	if auction.Price == nil {
+		return auction, nil, nil
+	}
+
+	var fills []Fill
+	var preventions []Prevention
+	left := new(big.Float).Set(auction.Quantity)
+	for left.Sign() > 0 {
+		bidLevel, askLevel := b.bids.best(), b.asks.best()
+		if bidLevel == nil || askLevel == nil ||
+			bidLevel.price.Cmp(auction.Price) < 0 || askLevel.price.Cmp(auction.Price) > 0 {
+			break
+		}
+
+		maker, taker := bidLevel.orders[0], askLevel.orders[0]
+		if taker.CreatedAt.Before(maker.CreatedAt) {
+			maker, taker = taker, maker
+		}
+
+		if taker.PreventsSelfTrade(maker) {
+			taker.Status = orderEntity.OrderStatusCancelled
+			b.Remove(taker.ID)
+			preventions = append(preventions, Prevention{Maker: maker, Taker: taker, Mode: taker.SelfTradePrevention})
+			continue
+		}
+
+		quantity := minFloat(minFloat(maker.RemainingQuantity, taker.RemainingQuantity), left)
+		makerLocked := maker.Fill(quantity, auction.Price)
+		takerLocked := taker.Fill(quantity, auction.Price)
+		left.Sub(left, quantity)
+		fills = append(fills, Fill{
+			Maker:       maker,
+			Taker:       taker,
+			Price:       new(big.Float).Set(auction.Price),
+			Quantity:    quantity,
+			MakerLocked: makerLocked,
+			TakerLocked: takerLocked,
+		})
+
+		for _, order := range []*orderEntity.Order{maker, taker} {
+			if order.RemainingQuantity.Sign() == 0 {
+				b.Remove(order.ID)
+				continue
+			}
+			order.Refill()
+		}
+	}
+
+	if len(fills) > 0 {
+		b.lastPrice = new(big.Float).Set(auction.Price)
+	}
+	return auction, fills, preventions
+}
+
+// limitPrices returns every distinct price resting in the book, lowest first.
+func (b *OrderBook) limitPrices() []*big.Float {
+	var prices []*big.Float
+	for _, side := range []*bookSide{b.bids, b.asks} {
+		for _, level := range side.levels {
+			prices = append(prices, level.price)
+		}
+	}
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].Cmp(prices[j]) < 0
+	})
+
+	distinct := prices[:0]
+	for _, price := range prices {
+		if len(distinct) == 0 || distinct[len(distinct)-1].Cmp(price) != 0 {
+			distinct = append(distinct, price)
+		}
+	}
+	return distinct
+}
+
+// closerToLast reports whether price is strictly closer to the last trade
+// price than current. Without a last trade price, or at the same distance,
+// the lower price wins; prices are walked lowest first, so current already is.
+func (b *OrderBook) closerToLast(price, current *big.Float) bool {
+	if b.lastPrice == nil {
+		return false
+	}
+	distance := new(big.Float).Abs(new(big.Float).Sub(price, b.lastPrice))
+	currentDistance := new(big.Float).Abs(new(big.Float).Sub(current, b.lastPrice))
+	return distance.Cmp(currentDistance) < 0
+}
+
+// volumeAt returns the quantity of the side willing to trade at price: every
+// bid at or above it, or every ask at or below it.
+func (s *bookSide) volumeAt(price *big.Float) *big.Float {
+	volume := new(big.Float)
+	for _, level := range s.levels {
+		if s.better(price, level.price) {
+			break
+		}
+		for _, order := range level.orders {
+			volume.Add(volume, order.RemainingQuantity)
+		}
+	}
+	return volume
+}
@@ -0,0 +1,121 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/domain/entity"
+	orderEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+// newArrival returns an order that arrived at the given second.
+func newArrival(id string, orderType orderEntity.OrderType, price, quantity string, second int) *orderEntity.Order {
+	order := newOrder(id, orderType, price, quantity)
+	order.CreatedAt = time.Unix(int64(second), 0)
+	return order
+}
+
+func TestOrderBook_Indicative_MaximizesVolume(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	book.Add(newArrival("bid-1", orderEntity.OrderTypeBuy, "102", "3", 1))
+	book.Add(newArrival("bid-2", orderEntity.OrderTypeBuy, "100", "2", 2))
+	book.Add(newArrival("ask-1", orderEntity.OrderTypeSell, "99", "1", 3))
+	book.Add(newArrival("ask-2", orderEntity.OrderTypeSell, "101", "3", 4))
+
+	// act
+	auction := book.Indicative()
+
+	// assert
+	assertFloat(t, "101", auction.Price)
+	assertFloat(t, "3", auction.Quantity)
+	assert.Len(t, book.Orders(), 4)
+}
+
+func TestOrderBook_Indicative_NoCross(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	book.Add(newArrival("bid-1", orderEntity.OrderTypeBuy, "99", "1", 1))
+	book.Add(newArrival("ask-1", orderEntity.OrderTypeSell, "100", "1", 2))
+
+	// act
+	auction := book.Indicative()
+
+	// assert
+	assert.Nil(t, auction.Price)
+	assertFloat(t, "0", auction.Quantity)
+}
+
+func TestOrderBook_Indicative_TieGoesToSmallestImbalance(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	book.Add(newArrival("bid-1", orderEntity.OrderTypeBuy, "101", "2", 1))
+	book.Add(newArrival("bid-2", orderEntity.OrderTypeBuy, "100", "1", 2))
+	book.Add(newArrival("ask-1", orderEntity.OrderTypeSell, "100", "2", 3))
+
+	// act
+	auction := book.Indicative()
+
+	// assert
+	assertFloat(t, "101", auction.Price)
+	assertFloat(t, "2", auction.Quantity)
+}
+
+func TestOrderBook_Uncross(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	bid1 := newArrival("bid-1", orderEntity.OrderTypeBuy, "102", "3", 1)
+	bid2 := newArrival("bid-2", orderEntity.OrderTypeBuy, "100", "2", 2)
+	ask1 := newArrival("ask-1", orderEntity.OrderTypeSell, "99", "1", 3)
+	ask2 := newArrival("ask-2", orderEntity.OrderTypeSell, "101", "3", 4)
+	for _, order := range []*orderEntity.Order{bid1, bid2, ask1, ask2} {
+		book.Add(order)
+	}
+
+	// act
+	auction, fills, preventions := book.Uncross()
+
+	// assert
+	assertFloat(t, "101", auction.Price)
+	assert.Empty(t, preventions)
+	assert.Len(t, fills, 2)
+	for _, fill := range fills {
+		assertFloat(t, "101", fill.Price)
+		assert.Equal(t, "bid-1", fill.Maker.ID)
+	}
+	assert.Equal(t, "ask-1", fills[0].Taker.ID)
+	assertFloat(t, "1", fills[0].Quantity)
+	assert.Equal(t, "ask-2", fills[1].Taker.ID)
+	assertFloat(t, "2", fills[1].Quantity)
+
+	assert.Equal(t, orderEntity.OrderStatusFilled, bid1.Status)
+	assert.Equal(t, orderEntity.OrderStatusPartiallyFilled, ask2.Status)
+	assertFloat(t, "1", ask2.RemainingQuantity)
+	assertFloat(t, "101", book.LastPrice())
+	assertFloat(t, "100", book.BestBid())
+	assertFloat(t, "101", book.BestAsk())
+}
+
+func TestOrderBook_Uncross_SelfTradeCancelsNewest(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	bid := newArrival("bid-1", orderEntity.OrderTypeBuy, "100", "1", 1)
+	bid.AccountID = "acc-1"
+	ask := newArrival("ask-1", orderEntity.OrderTypeSell, "100", "1", 2)
+	ask.AccountID = "acc-1"
+	ask.SelfTradePrevention = orderEntity.SelfTradePreventionCancelOldest
+	book.Add(bid)
+	book.Add(ask)
+
+	// act
+	_, fills, preventions := book.Uncross()
+
+	// assert
+	assert.Empty(t, fills)
+	assert.Len(t, preventions, 1)
+	assert.Equal(t, orderEntity.OrderStatusCancelled, ask.Status)
+	assert.Equal(t, orderEntity.OrderStatusOpen, bid.Status)
+	assert.False(t, book.Contains("ask-1"))
+	assert.True(t, book.Contains("bid-1"))
+}
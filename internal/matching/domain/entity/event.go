@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"math/big"
+	"time"
+)
+
+// EventType tells what an engine event reports.
+type EventType string
+
+const (
+	// EventCircuitBreakerTripped reports that a sudden price move paused an
+	// instrument.
+	EventCircuitBreakerTripped EventType = "CIRCUIT_BREAKER_TRIPPED"
+)
+
+// Event is something the engine did that other systems may react to. Status
+// is the instrument's status after the event. LastPrice and MovePercent
+// describe the move that tripped a circuit breaker.
+type Event struct {
+	Type          EventType
+	InstrumentID  string
+	Status        string
+	Reason        string
+	LastPrice     *big.Float
+	MovePercent   *big.Float
+	AuctionEndsAt *time.Time
+	OccurredAt    time.Time
+}
@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"math/big"
+
+	balanceEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/balance/domain/entity"
+	feeEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/fee/domain/entity"
+	orderEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+	tradeEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/trade/domain/entity"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/decimal"
+)
+
+// Fill is a single execution between a resting (maker) order and an incoming
+// (taker) order. Continuous matching executes at the maker's price, an
+// auction uncross at its clearing price. MakerFee and
+// TakerFee are charged on the asset each side receives and stay nil until
+// ChargeFees is called. MakerLocked and TakerLocked are the parts of each
+// order's reservation the fill used, as Order.Fill returned them.
+type Fill struct {
+	Maker         *orderEntity.Order
+	Taker         *orderEntity.Order
+	Price         *big.Float
+	Quantity      *big.Float
+	MakerLocked   *big.Float
+	TakerLocked   *big.Float
+	MakerFee      *big.Float
+	MakerFeeAsset string
+	TakerFee      *big.Float
+	TakerFeeAsset string
+}
+
+// ChargeFees computes the maker and taker fees of the fill at the given rates,
+// in basis points. Buyers pay in the base asset they receive and sellers in
+// the quote asset they receive.
+func (f *Fill) ChargeFees(baseAsset, quoteAsset string, makerBps, takerBps int) {
+	notional := f.notional()
+	charge := func(order *orderEntity.Order, bps int) (*big.Float, string) {
+		if order.Type == orderEntity.OrderTypeBuy {
+			return feeEntity.Charge(f.Quantity, bps), baseAsset
+		}
+		return feeEntity.Charge(notional, bps), quoteAsset
+	}
+
+	f.MakerFee, f.MakerFeeAsset = charge(f.Maker, makerBps)
+	f.TakerFee, f.TakerFeeAsset = charge(f.Taker, takerBps)
+}
+
+// ToTrade converts a Fill into the Trade record that is persisted for it.
+func (f Fill) ToTrade() tradeEntity.Trade {
+	return tradeEntity.Trade{
+		InstrumentID:   f.Taker.InstrumentID,
+		MakerOrderID:   f.Maker.ID,
+		TakerOrderID:   f.Taker.ID,
+		MakerAccountID: f.Maker.AccountID,
+		TakerAccountID: f.Taker.AccountID,
+		AggressorSide:  tradeEntity.Side(f.Taker.Type),
+		Price:          f.Price,
+		Quantity:       f.Quantity,
+		Fee:            orZero(f.TakerFee),
+		FeeAsset:       f.TakerFeeAsset,
+		MakerFee:       orZero(f.MakerFee),
+		MakerFeeAsset:  f.MakerFeeAsset,
+	}
+}
+
+// ToSettlement describes the balance movements of the fill. The buyer unlocks
+// what its order reserved for the executed quantity, which can be more than
+// the notional when the fill happens below the buyer's limit price. Each
+// side's fee comes out of what it receives.
+func (f Fill) ToSettlement(baseAsset, quoteAsset string) balanceEntity.Settlement {
+	buyer, seller := f.Taker, f.Maker
+	buyerLocked, sellerLocked := f.TakerLocked, f.MakerLocked
+	buyerFee, sellerFee := orZero(f.TakerFee), orZero(f.MakerFee)
+	if f.Maker.Type == orderEntity.OrderTypeBuy {
+		buyer, seller = f.Maker, f.Taker
+		buyerLocked, sellerLocked = sellerLocked, buyerLocked
+		buyerFee, sellerFee = sellerFee, buyerFee
+	}
+
+	return balanceEntity.Settlement{
+		BuyerAccountID:  buyer.AccountID,
+		SellerAccountID: seller.AccountID,
+		BaseAsset:       baseAsset,
+		QuoteAsset:      quoteAsset,
+		Quantity:        f.Quantity,
+		Notional:        f.notional(),
+		BuyerLocked:     buyerLocked,
+		SellerLocked:    sellerLocked,
+		BuyerFee:        buyerFee,
+		SellerFee:       sellerFee,
+	}
+}
+
+// notional is what the fill is worth in the quote asset, rounded to the scale
+// balances are stored with.
+func (f Fill) notional() *big.Float {
+	return decimal.Round(new(big.Float).SetPrec(decimal.Precision).Mul(f.Price, f.Quantity), decimal.AmountScale)
+}
+
+func orZero(amount *big.Float) *big.Float {
+	if amount == nil {
+		return new(big.Float)
+	}
+	return amount
+}
@@ -0,0 +1,101 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/domain/entity"
+	orderEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+	tradeEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/trade/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFill_ToTrade(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	maker := newOrder("ask-1", orderEntity.OrderTypeSell, "99", "1")
+	maker.AccountID = "acc-seller"
+	book.Add(maker)
+	taker := newOrder("bid-1", orderEntity.OrderTypeBuy, "100", "1")
+	taker.AccountID = "acc-buyer"
+
+	// act
+	fills, _ := book.Match(taker)
+	trade := fills[0].ToTrade()
+
+	// assert
+	assert.Equal(t, "inst-1", trade.InstrumentID)
+	assert.Equal(t, "ask-1", trade.MakerOrderID)
+	assert.Equal(t, "bid-1", trade.TakerOrderID)
+	assert.Equal(t, "acc-seller", trade.MakerAccountID)
+	assert.Equal(t, "acc-buyer", trade.TakerAccountID)
+	assert.Equal(t, tradeEntity.SideBuy, trade.AggressorSide)
+	assertFloat(t, "99", trade.Price)
+	assertFloat(t, "1", trade.Quantity)
+}
+
+func TestFill_ToSettlement(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	maker := newOrder("ask-1", orderEntity.OrderTypeSell, "99", "2")
+	maker.AccountID = "acc-seller"
+	book.Add(maker)
+	taker := newOrder("bid-1", orderEntity.OrderTypeBuy, "100", "2")
+	taker.AccountID = "acc-buyer"
+
+	// act
+	fills, _ := book.Match(taker)
+	settlement := fills[0].ToSettlement("BTC", "USDT")
+
+	// assert
+	assert.Equal(t, "acc-buyer", settlement.BuyerAccountID)
+	assert.Equal(t, "acc-seller", settlement.SellerAccountID)
+	assert.Equal(t, "BTC", settlement.BaseAsset)
+	assert.Equal(t, "USDT", settlement.QuoteAsset)
+	assertFloat(t, "2", settlement.Quantity)
+	assertFloat(t, "198", settlement.Notional)
+	assertFloat(t, "200", settlement.BuyerLocked)
+	assertFloat(t, "2", settlement.SellerLocked)
+}
+
+func TestFill_ChargeFees(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	maker := newOrder("ask-1", orderEntity.OrderTypeSell, "100", "2")
+	maker.AccountID = "acc-seller"
+	book.Add(maker)
+	taker := newOrder("bid-1", orderEntity.OrderTypeBuy, "100", "2")
+	taker.AccountID = "acc-buyer"
+	fills, _ := book.Match(taker)
+
+	// act
+	fills[0].ChargeFees("BTC", "USDT", 10, 25)
+	settlement := fills[0].ToSettlement("BTC", "USDT")
+	trade := fills[0].ToTrade()
+
+	// assert
+	assertFloat(t, "0.2", fills[0].MakerFee)
+	assert.Equal(t, "USDT", fills[0].MakerFeeAsset)
+	assertFloat(t, "0.005", fills[0].TakerFee)
+	assert.Equal(t, "BTC", fills[0].TakerFeeAsset)
+	assertFloat(t, "0.005", settlement.BuyerFee)
+	assertFloat(t, "0.2", settlement.SellerFee)
+	assertFloat(t, "0.005", trade.Fee)
+	assert.Equal(t, "BTC", trade.FeeAsset)
+	assertFloat(t, "0.2", trade.MakerFee)
+	assert.Equal(t, "USDT", trade.MakerFeeAsset)
+}
+
+func TestFill_ToSettlement_NoFees(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	book.Add(newOrder("bid-1", orderEntity.OrderTypeBuy, "100", "1"))
+	taker := newOrder("ask-1", orderEntity.OrderTypeSell, "100", "1")
+
+	// act
+	fills, _ := book.Match(taker)
+	settlement := fills[0].ToSettlement("BTC", "USDT")
+
+	// assert
+	assert.Zero(t, settlement.BuyerFee.Sign())
+	assert.Zero(t, settlement.SellerFee.Sign())
+}
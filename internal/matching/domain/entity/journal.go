@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"time"
+
+	orderEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+)
+
+// JournalEntryType tells what a journal entry records: an input the engine
+// received or an output it produced.
+type JournalEntryType string
+
+const (
+	// JournalNewOrder records an order handed to the engine.
+	JournalNewOrder JournalEntryType = "NEW_ORDER"
+	// JournalAmend records an amendment handed to the engine.
+	JournalAmend JournalEntryType = "AMEND"
+	// JournalCancel records a request to cancel an order.
+	JournalCancel JournalEntryType = "CANCEL"
+	// JournalCancelAll records a request to cancel every matching order.
+	JournalCancelAll JournalEntryType = "CANCEL_ALL"
+
+	// JournalAccepted records an order or amendment the engine took on.
+	JournalAccepted JournalEntryType = "ACCEPTED"
+	// JournalRejected records an order or amendment the engine turned down
+	// before it could trade, with the reason.
+	JournalRejected JournalEntryType = "REJECTED"
+	// JournalFill records an execution between two orders.
+	JournalFill JournalEntryType = "FILL"
+	// JournalDone records an order that reached its final status.
+	JournalDone JournalEntryType = "DONE"
+)
+
+// JournalEntry is a record of the engine journal, the append-only history of
+// everything the engine received and produced, in the order it happened.
+// Sequence is assigned by the journal when the entry is written. Order,
+// Amendment, CancelFilter and Fill carry the input or output the entry is
+// about; Status is the final status of a DONE order.
+type JournalEntry struct {
+	Sequence     uint64
+	Type         JournalEntryType
+	InstrumentID string
+	OrderID      string
+	Order        *orderEntity.Order
+	Amendment    *orderEntity.Amendment
+	CancelFilter *orderEntity.CancelFilter
+	Fill         *Fill
+	Status       orderEntity.OrderStatus
+	Reason       string
+	RecordedAt   time.Time
+}
+
+// OrderEntry records an order as it stands at now.
+func OrderEntry(entryType JournalEntryType, order orderEntity.Order, reason string, now time.Time) JournalEntry {
+	return JournalEntry{
+		Type:         entryType,
+		InstrumentID: order.InstrumentID,
+		OrderID:      order.ID,
+		Order:        &order,
+		Reason:       reason,
+		RecordedAt:   now,
+	}
+}
+
+// MatchEntries records the outcome of a match: a FILL for every fill, then a
+// DONE for every order the match finished, the taker last.
+func MatchEntries(taker *orderEntity.Order, fills []Fill, preventions []Prevention, now time.Time) []JournalEntry {
+	var entries []JournalEntry
+	for i := range fills {
+		fill := fills[i]
+		entries = append(entries, JournalEntry{
+			Type:         JournalFill,
+			InstrumentID: taker.InstrumentID,
+			OrderID:      fill.Taker.ID,
+			Fill:         &fill,
+			RecordedAt:   now,
+		})
+	}
+
+	done := make(map[string]bool)
+	finish := func(order *orderEntity.Order) {
+		if order.IsActive() || done[order.ID] {
+			return
+		}
+		done[order.ID] = true
+		entries = append(entries, DoneEntry(*order, now))
+	}
+	for _, fill := range fills {
+		finish(fill.Maker)
+	}
+	for _, prevention := range preventions {
+		finish(prevention.Maker)
+	}
+	finish(taker)
+	return entries
+}
+
+// DoneEntry records an order that reached its final status at now.
+func DoneEntry(order orderEntity.Order, now time.Time) JournalEntry {
+	return JournalEntry{
+		Type:         JournalDone,
+		InstrumentID: order.InstrumentID,
+		OrderID:      order.ID,
+		Status:       order.Status,
+		RecordedAt:   now,
+	}
+}
@@ -0,0 +1,53 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/domain/entity"
+	orderEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchEntries(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	book.Add(newOrder("ask-1", orderEntity.OrderTypeSell, "100", "1"))
+	book.Add(newOrder("ask-2", orderEntity.OrderTypeSell, "101", "2"))
+	taker := newOrder("bid-1", orderEntity.OrderTypeBuy, "101", "2")
+	fills, preventions := book.Match(taker)
+	now := time.Now()
+
+	// act
+	entries := entity.MatchEntries(taker, fills, preventions, now)
+
+	// assert
+	types := make([]entity.JournalEntryType, len(entries))
+	for i, entry := range entries {
+		types[i] = entry.Type
+		assert.Equal(t, "inst-1", entry.InstrumentID)
+		assert.Equal(t, now, entry.RecordedAt)
+	}
+	assert.Equal(t, []entity.JournalEntryType{
+		entity.JournalFill, entity.JournalFill, entity.JournalDone, entity.JournalDone,
+	}, types)
+	assert.Equal(t, "ask-1", entries[0].Fill.Maker.ID)
+	assert.Equal(t, "ask-2", entries[1].Fill.Maker.ID)
+	assert.Equal(t, "ask-1", entries[2].OrderID, "ask-2 is only partially filled")
+	assert.Equal(t, orderEntity.OrderStatusFilled, entries[2].Status)
+	assert.Equal(t, "bid-1", entries[3].OrderID)
+}
+
+func TestMatchEntries_CancelledWithoutTrading(t *testing.T) {
+	// arrange
+	taker := newOrder("bid-1", orderEntity.OrderTypeBuy, "100", "1")
+	taker.Status = orderEntity.OrderStatusCancelled
+
+	// act
+	entries := entity.MatchEntries(taker, nil, nil, time.Now())
+
+	// assert
+	assert.Len(t, entries, 1)
+	assert.Equal(t, entity.JournalDone, entries[0].Type)
+	assert.Equal(t, orderEntity.OrderStatusCancelled, entries[0].Status)
+}
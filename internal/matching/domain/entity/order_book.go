@@ -0,0 +1,428 @@
+package entity
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	orderEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+)
+
+// OrderBook is a limit order book for a single instrument. Resting orders are
+// matched with price-time priority: best price first and, within the same
+// price, the order that arrived first.
+type OrderBook struct {
+	InstrumentID string
+	bids         *bookSide
+	asks         *bookSide
+	orders       map[string]*orderEntity.Order
+	lastPrice    *big.Float
+	// sequence is the BookSequence of the order queued last.
+	sequence int64
+}
+
+type bookSide struct {
+	levels []*priceLevel
+	// better reports whether price a has priority over price b on this side.
+	better func(a, b *big.Float) bool
+}
+
+type priceLevel struct {
+	price  *big.Float
+	orders []*orderEntity.Order
+}
+
+func NewOrderBook(instrumentID string) *OrderBook {
+	return &OrderBook{
+		InstrumentID: instrumentID,
+		bids: &bookSide{better: func(a, b *big.Float) bool {
+			return a.Cmp(b) > 0
+		}},
+		asks: &bookSide{better: func(a, b *big.Float) bool {
+			return a.Cmp(b) < 0
+		}},
+		orders: make(map[string]*orderEntity.Order),
+	}
+}
+
+// Match executes the incoming order against the opposite side of the book
+// until it is filled or no resting order crosses its price. Market orders
+// sweep the book until they are filled or liquidity runs out. Both the taker
+// and the touched makers are updated in place. The unfilled remainder is not
+// added to the book; call Add for that.
+//
+// A resting order of the taker's own account is never traded against when the
+// taker has a self-trade prevention policy; the policy is applied instead and
+// reported as a Prevention. Match stops early when it cancels the taker.
+func (b *OrderBook) Match(taker *orderEntity.Order) ([]Fill, []Prevention) {
+	opposite := b.opposite(taker.Type)
+
+	var fills []Fill
+	var preventions []Prevention
+	for taker.IsActive() {
+		level := opposite.best()
+		if level == nil || !crosses(taker, level.price) {
+			break
+		}
+
+		maker := level.orders[0]
+		quantity := minFloat(taker.ExecutableQuantity(level.price), maker.ShownQuantity())
+		if quantity.Sign() <= 0 {
+			break
+		}
+
+		if taker.PreventsSelfTrade(maker) {
+			preventions = append(preventions, b.preventSelfTrade(taker, maker, level, quantity))
+			continue
+		}
+
+		makerLocked := maker.Fill(quantity, level.price)
+		takerLocked := taker.Fill(quantity, level.price)
+
+		b.lastPrice = new(big.Float).Set(level.price)
+		fills = append(fills, Fill{
+			Maker:       maker,
+			Taker:       taker,
+			Price:       new(big.Float).Set(level.price),
+			Quantity:    quantity,
+			MakerLocked: makerLocked,
+			TakerLocked: takerLocked,
+		})
+
+		if maker.RemainingQuantity.Sign() == 0 {
+			opposite.remove(maker)
+			delete(b.orders, maker.ID)
+			continue
+		}
+		if maker.Refill() {
+			// a refilled iceberg slice loses its time priority
+			level.orders = append(level.orders[1:], maker)
+			b.enqueue(maker)
+		}
+	}
+	return fills, preventions
+}
+
+// preventSelfTrade applies the taker's self-trade prevention policy to a maker
+// of the same account at the front of level. Cancelled makers leave the book.
+func (b *OrderBook) preventSelfTrade(taker, maker *orderEntity.Order, level *priceLevel, quantity *big.Float) Prevention {
+	prevention := Prevention{Maker: maker, Taker: taker, Mode: taker.SelfTradePrevention}
+	switch taker.SelfTradePrevention {
+	case orderEntity.SelfTradePreventionCancelNewest:
+		taker.Status = orderEntity.OrderStatusCancelled
+	case orderEntity.SelfTradePreventionCancelOldest:
+		maker.Status = orderEntity.OrderStatusCancelled
+	case orderEntity.SelfTradePreventionCancelBoth:
+		taker.Status = orderEntity.OrderStatusCancelled
+		maker.Status = orderEntity.OrderStatusCancelled
+	case orderEntity.SelfTradePreventionDecrementAndCancel:
+		prevention.Quantity = quantity
+		prevention.MakerReleased = maker.Decrement(quantity, level.price)
+		prevention.TakerReleased = taker.Decrement(quantity, level.price)
+	}
+
+	if maker.Status == orderEntity.OrderStatusCancelled {
+		b.side(maker.Type).remove(maker)
+		delete(b.orders, maker.ID)
+	} else if maker.Refill() {
+		level.orders = append(level.orders[1:], maker)
+		b.enqueue(maker)
+	}
+	return prevention
+}
+
+// Crosses reports whether the order would trade against the book on arrival.
+func (b *OrderBook) Crosses(order *orderEntity.Order) bool {
+	level := b.opposite(order.Type).best()
+	return level != nil && crosses(order, level.price)
+}
+
+// MakerPrice returns the price one tick behind the best opposite price, the
+// most aggressive price at which the order still rests without trading. It
+// returns nil when the opposite side is empty or no positive price is left.
+func (b *OrderBook) MakerPrice(order *orderEntity.Order, tick *big.Float) *big.Float {
+	level := b.opposite(order.Type).best()
+	if level == nil {
+		return nil
+	}
+
+	price := new(big.Float)
+	if order.Type == orderEntity.OrderTypeBuy {
+		price.Sub(level.price, tick)
+	} else {
+		price.Add(level.price, tick)
+	}
+	if price.Sign() <= 0 {
+		return nil
+	}
+	return price
+}
+
+// tickTolerance is how far, in ticks, a price may be from a whole number of
+// ticks and still count as on the tick grid.
+var tickTolerance = big.NewFloat(1e-9)
+
+// RoundToTick rounds the price an order is re-priced to onto the tick grid,
+// down for buys and up for sells, so it stays behind the opposite side of the
+// book. It returns nil when no positive price is left.
+func RoundToTick(order *orderEntity.Order, price, tick *big.Float) *big.Float {
+	steps := new(big.Float).SetPrec(256).Quo(price, tick)
+	ticks, _ := steps.Int(nil)
+	nearest, _ := new(big.Float).SetPrec(256).Add(steps, big.NewFloat(0.5)).Int(nil)
+	diff := new(big.Float).SetPrec(256).Sub(steps, new(big.Float).SetInt(nearest))
+	switch {
+	case diff.Abs(diff).Cmp(tickTolerance) <= 0:
+		ticks = nearest
+	case order.Type == orderEntity.OrderTypeSell:
+		ticks.Add(ticks, big.NewInt(1))
+	}
+
+	rounded := new(big.Float).SetPrec(256).Mul(new(big.Float).SetInt(ticks), tick)
+	if rounded.Sign() <= 0 {
+		return nil
+	}
+	return rounded
+}
+
+// CanFill reports whether the book holds enough crossing liquidity to fill
+// the incoming order completely. Neither the order nor the book is changed.
+// Resting orders of the taker's own account only count when they would not be
+// self-trade prevented; cancel-oldest skips them, every other policy stops the
+// taker before it is filled.
+func (b *OrderBook) CanFill(taker *orderEntity.Order) bool {
+	simulated := *taker
+	for _, level := range b.opposite(taker.Type).levels {
+		if !crosses(taker, level.price) {
+			break
+		}
+		for _, maker := range level.orders {
+			if taker.PreventsSelfTrade(maker) {
+				if taker.SelfTradePrevention == orderEntity.SelfTradePreventionCancelOldest {
+					continue
+				}
+				return false
+			}
+			quantity := minFloat(simulated.ExecutableQuantity(level.price), maker.RemainingQuantity)
+			if quantity.Sign() <= 0 {
+				break
+			}
+			simulated.Fill(quantity, level.price)
+			if simulated.Status == orderEntity.OrderStatusFilled {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Expired returns the resting orders whose deadline has passed at now.
+func (b *OrderBook) Expired(now time.Time) []*orderEntity.Order {
+	var expired []*orderEntity.Order
+	for _, order := range b.orders {
+		if order.IsExpired(now) {
+			expired = append(expired, order)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].ExpiresAt.Before(*expired[j].ExpiresAt)
+	})
+	return expired
+}
+
+// Add rests an order on its side of the book behind every order already
+// queued at the same price and records that place in its BookSequence.
+// Iceberg orders rest with their first slice shown.
+func (b *OrderBook) Add(order *orderEntity.Order) {
+	order.Refill()
+	b.side(order.Type).add(order)
+	b.orders[order.ID] = order
+	b.enqueue(order)
+}
+
+// Restore rests an order rebuilt from storage at the place its BookSequence
+// records. Orders must be restored in BookSequence order; orders added later
+// queue behind all of them.
+func (b *OrderBook) Restore(order *orderEntity.Order) {
+	order.Refill()
+	b.side(order.Type).add(order)
+	b.orders[order.ID] = order
+	if order.BookSequence > b.sequence {
+		b.sequence = order.BookSequence
+	}
+}
+
+// RestoreLastPrice sets the last trade price of a book rebuilt from storage.
+func (b *OrderBook) RestoreLastPrice(price *big.Float) {
+	b.lastPrice = price
+}
+
+// enqueue gives an order that just joined the back of its price level the
+// next place in the book's time priority.
+func (b *OrderBook) enqueue(order *orderEntity.Order) {
+	b.sequence++
+	order.BookSequence = b.sequence
+}
+
+// Remove takes a resting order out of the book. It returns the removed order
+// and false when the order is not resting.
+func (b *OrderBook) Remove(orderID string) (*orderEntity.Order, bool) {
+	order, ok := b.orders[orderID]
+	if !ok {
+		return nil, false
+	}
+	b.side(order.Type).remove(order)
+	delete(b.orders, orderID)
+	return order, true
+}
+
+// Order returns the resting order with the given ID.
+func (b *OrderBook) Order(orderID string) (*orderEntity.Order, bool) {
+	order, ok := b.orders[orderID]
+	return order, ok
+}
+
+// Orders returns every resting order, bids before asks and each side in
+// priority order.
+func (b *OrderBook) Orders() []*orderEntity.Order {
+	orders := make([]*orderEntity.Order, 0, len(b.orders))
+	for _, side := range []*bookSide{b.bids, b.asks} {
+		for _, level := range side.levels {
+			orders = append(orders, level.orders...)
+		}
+	}
+	return orders
+}
+
+// Contains reports whether the order is resting in the book.
+func (b *OrderBook) Contains(orderID string) bool {
+	_, ok := b.orders[orderID]
+	return ok
+}
+
+// LastPrice returns the price of the most recent fill, or nil if the book has
+// not traded yet.
+func (b *OrderBook) LastPrice() *big.Float {
+	return b.lastPrice
+}
+
+// Snapshot returns up to depth price levels per side, best first, with the
+// quantity the public may see: iceberg orders only count their visible slice.
+// A depth of zero or less returns every level.
+func (b *OrderBook) Snapshot(depth int) Snapshot {
+	return Snapshot{
+		InstrumentID: b.InstrumentID,
+		Bids:         b.bids.snapshot(depth),
+		Asks:         b.asks.snapshot(depth),
+		LastPrice:    b.lastPrice,
+	}
+}
+
+// BestBid returns the highest resting buy price, or nil if there are no bids.
+func (b *OrderBook) BestBid() *big.Float {
+	if level := b.bids.best(); level != nil {
+		return level.price
+	}
+	return nil
+}
+
+// BestAsk returns the lowest resting sell price, or nil if there are no asks.
+func (b *OrderBook) BestAsk() *big.Float {
+	if level := b.asks.best(); level != nil {
+		return level.price
+	}
+	return nil
+}
+
+func (b *OrderBook) side(orderType orderEntity.OrderType) *bookSide {
+	if orderType == orderEntity.OrderTypeBuy {
+		return b.bids
+	}
+	return b.asks
+}
+
+func (b *OrderBook) opposite(orderType orderEntity.OrderType) *bookSide {
+	if orderType == orderEntity.OrderTypeBuy {
+		return b.asks
+	}
+	return b.bids
+}
+
+func (s *bookSide) best() *priceLevel {
+	if len(s.levels) == 0 {
+		return nil
+	}
+	return s.levels[0]
+}
+
+func (s *bookSide) snapshot(depth int) []Level {
+	levels := s.levels
+	if depth > 0 && len(levels) > depth {
+		levels = levels[:depth]
+	}
+
+	snapshot := make([]Level, len(levels))
+	for i, level := range levels {
+		quantity := new(big.Float)
+		for _, order := range level.orders {
+			quantity.Add(quantity, order.ShownQuantity())
+		}
+		snapshot[i] = Level{
+			Price:    new(big.Float).Set(level.price),
+			Quantity: quantity,
+			Orders:   len(level.orders),
+		}
+	}
+	return snapshot
+}
+
+func (s *bookSide) add(order *orderEntity.Order) {
+	i := sort.Search(len(s.levels), func(i int) bool {
+		return !s.better(s.levels[i].price, order.Price)
+	})
+	if i < len(s.levels) && s.levels[i].price.Cmp(order.Price) == 0 {
+		s.levels[i].orders = append(s.levels[i].orders, order)
+		return
+	}
+
+	level := &priceLevel{price: order.Price, orders: []*orderEntity.Order{order}}
+	s.levels = append(s.levels, nil)
+	copy(s.levels[i+1:], s.levels[i:])
+	s.levels[i] = level
+}
+
+func (s *bookSide) remove(order *orderEntity.Order) {
+	for i, level := range s.levels {
+		if level.price.Cmp(order.Price) != 0 {
+			continue
+		}
+		for j, o := range level.orders {
+			if o.ID == order.ID {
+				level.orders = append(level.orders[:j], level.orders[j+1:]...)
+				break
+			}
+		}
+		if len(level.orders) == 0 {
+			s.levels = append(s.levels[:i], s.levels[i+1:]...)
+		}
+		return
+	}
+}
+
+// crosses reports whether the taker is willing to trade at the given price.
+// Market orders take any price.
+func crosses(taker *orderEntity.Order, price *big.Float) bool {
+	if taker.IsMarket() {
+		return true
+	}
+	if taker.Type == orderEntity.OrderTypeBuy {
+		return price.Cmp(taker.Price) <= 0
+	}
+	return price.Cmp(taker.Price) >= 0
+}
+
+func minFloat(a, b *big.Float) *big.Float {
+	if a.Cmp(b) <= 0 {
+		return new(big.Float).Set(a)
+	}
+	return new(big.Float).Set(b)
+}
@@ -0,0 +1,385 @@
+package entity_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/domain/entity"
+	orderEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOrder(id string, orderType orderEntity.OrderType, price, quantity string) *orderEntity.Order {
+	p, _ := new(big.Float).SetString(price)
+	q, _ := new(big.Float).SetString(quantity)
+	return &orderEntity.Order{
+		ID:                id,
+		InstrumentID:      "inst-1",
+		Type:              orderType,
+		Status:            orderEntity.OrderStatusOpen,
+		Price:             p,
+		Quantity:          q,
+		RemainingQuantity: q,
+	}
+}
+
+func assertFloat(t *testing.T, expected string, actual *big.Float) {
+	t.Helper()
+	e, _ := decimal.Parse(expected)
+	assert.Zero(t, e.Cmp(actual), "expected %s, got %s", expected, actual.String())
+}
+
+func TestOrderBook_Match_NoCross(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	book.Add(newOrder("ask-1", orderEntity.OrderTypeSell, "101", "1"))
+	taker := newOrder("bid-1", orderEntity.OrderTypeBuy, "100", "1")
+
+	// act
+	fills, _ := book.Match(taker)
+
+	// assert
+	assert.Empty(t, fills)
+	assert.Equal(t, orderEntity.OrderStatusOpen, taker.Status)
+	assertFloat(t, "1", taker.RemainingQuantity)
+}
+
+func TestOrderBook_Match_FullFillAtMakerPrice(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	maker := newOrder("ask-1", orderEntity.OrderTypeSell, "99", "2")
+	book.Add(maker)
+	taker := newOrder("bid-1", orderEntity.OrderTypeBuy, "100", "2")
+
+	// act
+	fills, _ := book.Match(taker)
+
+	// assert
+	assert.Len(t, fills, 1)
+	assertFloat(t, "99", fills[0].Price)
+	assertFloat(t, "2", fills[0].Quantity)
+	assert.Equal(t, orderEntity.OrderStatusFilled, taker.Status)
+	assert.Equal(t, orderEntity.OrderStatusFilled, maker.Status)
+	assert.False(t, book.Contains("ask-1"))
+	assert.Nil(t, book.BestAsk())
+}
+
+func TestOrderBook_Match_PartialFill(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	maker := newOrder("bid-1", orderEntity.OrderTypeBuy, "100", "5")
+	book.Add(maker)
+	taker := newOrder("ask-1", orderEntity.OrderTypeSell, "100", "2")
+
+	// act
+	fills, _ := book.Match(taker)
+
+	// assert
+	assert.Len(t, fills, 1)
+	assert.Equal(t, orderEntity.OrderStatusFilled, taker.Status)
+	assert.Equal(t, orderEntity.OrderStatusPartiallyFilled, maker.Status)
+	assertFloat(t, "3", maker.RemainingQuantity)
+	assert.True(t, book.Contains("bid-1"))
+}
+
+func TestOrderBook_Match_PriceTimePriority(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	book.Add(newOrder("ask-late", orderEntity.OrderTypeSell, "100", "1"))
+	book.Add(newOrder("ask-worse", orderEntity.OrderTypeSell, "101", "1"))
+	book.Add(newOrder("ask-best", orderEntity.OrderTypeSell, "99", "1"))
+	book.Add(newOrder("ask-later", orderEntity.OrderTypeSell, "100", "1"))
+	taker := newOrder("bid-1", orderEntity.OrderTypeBuy, "100", "10")
+
+	// act
+	fills, _ := book.Match(taker)
+
+	// assert
+	assert.Len(t, fills, 3)
+	assert.Equal(t, "ask-best", fills[0].Maker.ID)
+	assert.Equal(t, "ask-late", fills[1].Maker.ID)
+	assert.Equal(t, "ask-later", fills[2].Maker.ID)
+	assert.Equal(t, orderEntity.OrderStatusPartiallyFilled, taker.Status)
+	assertFloat(t, "7", taker.RemainingQuantity)
+	assertFloat(t, "101", book.BestAsk())
+}
+
+func TestOrderBook_Remove(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	book.Add(newOrder("bid-1", orderEntity.OrderTypeBuy, "100", "1"))
+	book.Add(newOrder("bid-2", orderEntity.OrderTypeBuy, "98", "1"))
+
+	// act
+	removed, ok := book.Remove("bid-1")
+	_, missing := book.Remove("unknown")
+
+	// assert
+	assert.True(t, ok)
+	assert.Equal(t, "bid-1", removed.ID)
+	assert.False(t, missing)
+	assertFloat(t, "98", book.BestBid())
+}
+
+func TestOrderBook_Match_MarketSellSweepsLevels(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	book.Add(newOrder("bid-1", orderEntity.OrderTypeBuy, "100", "1"))
+	book.Add(newOrder("bid-2", orderEntity.OrderTypeBuy, "90", "1"))
+	taker := newOrder("ask-1", orderEntity.OrderTypeSell, "0", "3")
+	taker.Kind = orderEntity.OrderKindMarket
+	taker.Price = nil
+
+	// act
+	fills, _ := book.Match(taker)
+
+	// assert
+	assert.Len(t, fills, 2)
+	assertFloat(t, "100", fills[0].Price)
+	assertFloat(t, "90", fills[1].Price)
+	assert.Equal(t, orderEntity.OrderStatusPartiallyFilled, taker.Status)
+	assertFloat(t, "1", taker.RemainingQuantity)
+	assert.Nil(t, book.BestBid())
+}
+
+func TestOrderBook_Match_MarketBuyByQuoteBudget(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	book.Add(newOrder("ask-1", orderEntity.OrderTypeSell, "100", "1"))
+	book.Add(newOrder("ask-2", orderEntity.OrderTypeSell, "200", "5"))
+	taker := &orderEntity.Order{
+		ID:                     "bid-1",
+		InstrumentID:           "inst-1",
+		Type:                   orderEntity.OrderTypeBuy,
+		Kind:                   orderEntity.OrderKindMarket,
+		Status:                 orderEntity.OrderStatusOpen,
+		Quantity:               new(big.Float),
+		RemainingQuantity:      new(big.Float),
+		QuoteQuantity:          big.NewFloat(300),
+		RemainingQuoteQuantity: big.NewFloat(300),
+	}
+
+	// act
+	fills, _ := book.Match(taker)
+
+	// assert
+	assert.Len(t, fills, 2)
+	assertFloat(t, "1", fills[0].Quantity)
+	assertFloat(t, "1", fills[1].Quantity)
+	assert.Equal(t, orderEntity.OrderStatusFilled, taker.Status)
+	assertFloat(t, "2", taker.Quantity)
+	assertFloat(t, "0", taker.RemainingQuoteQuantity)
+	assertFloat(t, "200", book.BestAsk())
+}
+
+func TestOrderBook_CanFill(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	book.Add(newOrder("ask-1", orderEntity.OrderTypeSell, "100", "1"))
+	book.Add(newOrder("ask-2", orderEntity.OrderTypeSell, "101", "1"))
+	book.Add(newOrder("ask-3", orderEntity.OrderTypeSell, "105", "5"))
+
+	// act & assert
+	assert.True(t, book.CanFill(newOrder("bid-1", orderEntity.OrderTypeBuy, "101", "2")))
+	assert.False(t, book.CanFill(newOrder("bid-2", orderEntity.OrderTypeBuy, "101", "2.5")))
+	assert.True(t, book.CanFill(newOrder("bid-3", orderEntity.OrderTypeBuy, "105", "7")))
+
+	taker := newOrder("bid-4", orderEntity.OrderTypeBuy, "101", "3")
+	book.CanFill(taker)
+	assert.Equal(t, orderEntity.OrderStatusOpen, taker.Status)
+	assertFloat(t, "3", taker.RemainingQuantity)
+	assertFloat(t, "100", book.BestAsk())
+}
+
+func TestOrderBook_Expired(t *testing.T) {
+	// arrange
+	now := time.Now()
+	soon, later := now.Add(time.Minute), now.Add(time.Hour)
+	book := entity.NewOrderBook("inst-1")
+
+	gtc := newOrder("bid-1", orderEntity.OrderTypeBuy, "100", "1")
+	first := newOrder("bid-2", orderEntity.OrderTypeBuy, "99", "1")
+	first.TimeInForce, first.ExpiresAt = orderEntity.TimeInForceGTD, &soon
+	second := newOrder("bid-3", orderEntity.OrderTypeBuy, "98", "1")
+	second.TimeInForce, second.ExpiresAt = orderEntity.TimeInForceGTD, &later
+	book.Add(gtc)
+	book.Add(second)
+	book.Add(first)
+
+	// act & assert
+	assert.Empty(t, book.Expired(now))
+
+	expired := book.Expired(later)
+	assert.Len(t, expired, 2)
+	assert.Equal(t, "bid-2", expired[0].ID)
+	assert.Equal(t, "bid-3", expired[1].ID)
+}
+
+func TestOrderBook_Crosses(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	book.Add(newOrder("ask-1", orderEntity.OrderTypeSell, "100", "1"))
+	book.Add(newOrder("bid-1", orderEntity.OrderTypeBuy, "98", "1"))
+
+	// act & assert
+	assert.True(t, book.Crosses(newOrder("bid-2", orderEntity.OrderTypeBuy, "100", "1")))
+	assert.False(t, book.Crosses(newOrder("bid-3", orderEntity.OrderTypeBuy, "99", "1")))
+	assert.True(t, book.Crosses(newOrder("ask-2", orderEntity.OrderTypeSell, "97", "1")))
+	assert.False(t, book.Crosses(newOrder("ask-3", orderEntity.OrderTypeSell, "99", "1")))
+}
+
+func TestOrderBook_MakerPrice(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	tick := big.NewFloat(0.5)
+
+	// act & assert
+	assert.Nil(t, book.MakerPrice(newOrder("bid-1", orderEntity.OrderTypeBuy, "101", "1"), tick))
+
+	book.Add(newOrder("ask-1", orderEntity.OrderTypeSell, "100", "1"))
+	book.Add(newOrder("bid-2", orderEntity.OrderTypeBuy, "98", "1"))
+
+	assertFloat(t, "99.5", book.MakerPrice(newOrder("bid-3", orderEntity.OrderTypeBuy, "101", "1"), tick))
+	assertFloat(t, "98.5", book.MakerPrice(newOrder("ask-2", orderEntity.OrderTypeSell, "97", "1"), tick))
+}
+
+func TestRoundToTick(t *testing.T) {
+	// arrange
+	tick := big.NewFloat(0.5)
+	buy := newOrder("bid-1", orderEntity.OrderTypeBuy, "101", "1")
+	sell := newOrder("ask-1", orderEntity.OrderTypeSell, "97", "1")
+
+	// act & assert
+	assertFloat(t, "99.5", entity.RoundToTick(buy, big.NewFloat(99.5), tick))
+	assertFloat(t, "99.5", entity.RoundToTick(buy, big.NewFloat(99.9), tick))
+	assertFloat(t, "100", entity.RoundToTick(sell, big.NewFloat(99.6), tick))
+	assertFloat(t, "99.5", entity.RoundToTick(sell, big.NewFloat(99.5), tick))
+	assert.Nil(t, entity.RoundToTick(buy, big.NewFloat(0.4), tick))
+}
+
+func TestOrderBook_LastPrice(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	book.Add(newOrder("ask-1", orderEntity.OrderTypeSell, "100", "1"))
+	book.Add(newOrder("ask-2", orderEntity.OrderTypeSell, "101", "1"))
+
+	// act & assert
+	assert.Nil(t, book.LastPrice())
+
+	book.Match(newOrder("bid-1", orderEntity.OrderTypeBuy, "101", "2"))
+	assertFloat(t, "101", book.LastPrice())
+}
+
+func newIceberg(id string, orderType orderEntity.OrderType, price, quantity, display string) *orderEntity.Order {
+	order := newOrder(id, orderType, price, quantity)
+	order.DisplayQuantity, _ = new(big.Float).SetString(display)
+	return order
+}
+
+func TestOrderBook_Match_IcebergRefillsAndLosesPriority(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	iceberg := newIceberg("ask-1", orderEntity.OrderTypeSell, "100", "5", "2")
+	other := newOrder("ask-2", orderEntity.OrderTypeSell, "100", "1")
+	book.Add(iceberg)
+	book.Add(other)
+
+	// act
+	fills, _ := book.Match(newOrder("bid-1", orderEntity.OrderTypeBuy, "100", "4"))
+
+	// assert
+	assert.Len(t, fills, 3)
+	assert.Equal(t, "ask-1", fills[0].Maker.ID)
+	assertFloat(t, "2", fills[0].Quantity)
+	assert.Equal(t, "ask-2", fills[1].Maker.ID, "the refilled slice goes behind ask-2")
+	assert.Equal(t, "ask-1", fills[2].Maker.ID)
+	assertFloat(t, "1", fills[2].Quantity)
+	assertFloat(t, "2", iceberg.RemainingQuantity)
+	assertFloat(t, "1", iceberg.VisibleQuantity)
+}
+
+func TestOrderBook_Snapshot(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	book.Add(newIceberg("ask-1", orderEntity.OrderTypeSell, "101", "50", "2"))
+	book.Add(newOrder("ask-2", orderEntity.OrderTypeSell, "101", "1"))
+	book.Add(newOrder("ask-3", orderEntity.OrderTypeSell, "102", "1"))
+	book.Add(newOrder("bid-1", orderEntity.OrderTypeBuy, "99", "3"))
+
+	// act
+	snapshot := book.Snapshot(1)
+
+	// assert
+	assert.Equal(t, "inst-1", snapshot.InstrumentID)
+	assert.Len(t, snapshot.Asks, 1)
+	assertFloat(t, "101", snapshot.Asks[0].Price)
+	assertFloat(t, "3", snapshot.Asks[0].Quantity)
+	assert.Equal(t, 2, snapshot.Asks[0].Orders)
+	assert.Len(t, snapshot.Bids, 1)
+	assertFloat(t, "3", snapshot.Bids[0].Quantity)
+	assert.Len(t, book.Snapshot(0).Asks, 2)
+}
+
+func TestOrderBook_Orders(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	book.Add(newOrder("ask-1", orderEntity.OrderTypeSell, "101", "1"))
+	book.Add(newOrder("bid-1", orderEntity.OrderTypeBuy, "99", "1"))
+	book.Add(newOrder("bid-2", orderEntity.OrderTypeBuy, "100", "1"))
+
+	// act
+	orders := book.Orders()
+
+	// assert
+	ids := make([]string, len(orders))
+	for i, o := range orders {
+		ids[i] = o.ID
+	}
+	assert.Equal(t, []string{"bid-2", "bid-1", "ask-1"}, ids)
+}
+
+func TestOrderBook_Add_RecordsBookSequence(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	iceberg := newIceberg("ask-1", orderEntity.OrderTypeSell, "100", "5", "2")
+	other := newOrder("ask-2", orderEntity.OrderTypeSell, "100", "1")
+
+	// act
+	book.Add(iceberg)
+	book.Add(other)
+
+	// assert
+	assert.Equal(t, int64(1), iceberg.BookSequence)
+	assert.Equal(t, int64(2), other.BookSequence)
+
+	book.Match(newOrder("bid-1", orderEntity.OrderTypeBuy, "100", "2"))
+	assert.Equal(t, int64(3), iceberg.BookSequence, "a refilled slice queues again")
+}
+
+func TestOrderBook_Restore(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	first := newOrder("ask-1", orderEntity.OrderTypeSell, "100", "1")
+	first.BookSequence = 4
+	second := newOrder("ask-2", orderEntity.OrderTypeSell, "100", "1")
+	second.BookSequence = 7
+
+	// act
+	book.Restore(first)
+	book.Restore(second)
+	book.RestoreLastPrice(big.NewFloat(99))
+	added := newOrder("ask-3", orderEntity.OrderTypeSell, "100", "1")
+	book.Add(added)
+
+	// assert
+	assert.Equal(t, int64(4), first.BookSequence)
+	assert.Equal(t, int64(8), added.BookSequence, "new orders queue behind the restored ones")
+	assertFloat(t, "99", book.LastPrice())
+	fills, _ := book.Match(newOrder("bid-1", orderEntity.OrderTypeBuy, "100", "3"))
+	assert.Len(t, fills, 3)
+	assert.Equal(t, "ask-1", fills[0].Maker.ID)
+	assert.Equal(t, "ask-2", fills[1].Maker.ID)
+	assert.Equal(t, "ask-3", fills[2].Maker.ID)
+}
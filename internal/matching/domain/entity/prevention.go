@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"math/big"
+
+	orderEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+)
+
+// Prevention records a match that did not happen because the taker and the
+// maker belong to the same account. Depending on the taker's policy either
+// order, or both, was cancelled or decremented by Quantity. MakerReleased and
+// TakerReleased are the reservations a decrement freed.
+type Prevention struct {
+	Maker         *orderEntity.Order
+	Taker         *orderEntity.Order
+	Mode          orderEntity.SelfTradePrevention
+	Quantity      *big.Float
+	MakerReleased *big.Float
+	TakerReleased *big.Float
+}
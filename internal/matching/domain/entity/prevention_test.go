@@ -0,0 +1,132 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/domain/entity"
+	orderEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAccountOrder(id, accountID string, orderType orderEntity.OrderType, price, quantity string) *orderEntity.Order {
+	order := newOrder(id, orderType, price, quantity)
+	order.AccountID = accountID
+	return order
+}
+
+func TestOrderBook_Match_SelfTradeCancelNewest(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	other := newAccountOrder("ask-1", "acc-2", orderEntity.OrderTypeSell, "99", "1")
+	own := newAccountOrder("ask-2", "acc-1", orderEntity.OrderTypeSell, "100", "1")
+	book.Add(other)
+	book.Add(own)
+	taker := newAccountOrder("bid-1", "acc-1", orderEntity.OrderTypeBuy, "100", "2")
+	taker.SelfTradePrevention = orderEntity.SelfTradePreventionCancelNewest
+
+	// act
+	fills, preventions := book.Match(taker)
+
+	// assert
+	assert.Len(t, fills, 1)
+	assert.Equal(t, "ask-1", fills[0].Maker.ID)
+	assert.Len(t, preventions, 1)
+	assert.Equal(t, orderEntity.OrderStatusCancelled, taker.Status)
+	assertFloat(t, "1", taker.RemainingQuantity)
+	assert.Equal(t, orderEntity.OrderStatusOpen, own.Status)
+	assert.True(t, book.Contains("ask-2"))
+}
+
+func TestOrderBook_Match_SelfTradeCancelOldest(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	own := newAccountOrder("ask-1", "acc-1", orderEntity.OrderTypeSell, "99", "1")
+	other := newAccountOrder("ask-2", "acc-2", orderEntity.OrderTypeSell, "100", "1")
+	book.Add(own)
+	book.Add(other)
+	taker := newAccountOrder("bid-1", "acc-1", orderEntity.OrderTypeBuy, "100", "1")
+	taker.SelfTradePrevention = orderEntity.SelfTradePreventionCancelOldest
+
+	// act
+	fills, preventions := book.Match(taker)
+
+	// assert
+	assert.Len(t, preventions, 1)
+	assert.Equal(t, orderEntity.OrderStatusCancelled, own.Status)
+	assert.False(t, book.Contains("ask-1"))
+	assert.Len(t, fills, 1)
+	assert.Equal(t, "ask-2", fills[0].Maker.ID)
+	assert.Equal(t, orderEntity.OrderStatusFilled, taker.Status)
+}
+
+func TestOrderBook_Match_SelfTradeCancelBoth(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	own := newAccountOrder("ask-1", "acc-1", orderEntity.OrderTypeSell, "99", "1")
+	book.Add(own)
+	taker := newAccountOrder("bid-1", "acc-1", orderEntity.OrderTypeBuy, "100", "1")
+	taker.SelfTradePrevention = orderEntity.SelfTradePreventionCancelBoth
+
+	// act
+	fills, preventions := book.Match(taker)
+
+	// assert
+	assert.Empty(t, fills)
+	assert.Len(t, preventions, 1)
+	assert.Equal(t, orderEntity.OrderStatusCancelled, own.Status)
+	assert.Equal(t, orderEntity.OrderStatusCancelled, taker.Status)
+	assert.False(t, book.Contains("ask-1"))
+}
+
+func TestOrderBook_Match_SelfTradeDecrementAndCancel(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	own := newAccountOrder("ask-1", "acc-1", orderEntity.OrderTypeSell, "99", "5")
+	book.Add(own)
+	taker := newAccountOrder("bid-1", "acc-1", orderEntity.OrderTypeBuy, "100", "2")
+	taker.SelfTradePrevention = orderEntity.SelfTradePreventionDecrementAndCancel
+
+	// act
+	fills, preventions := book.Match(taker)
+
+	// assert
+	assert.Empty(t, fills)
+	assert.Len(t, preventions, 1)
+	assertFloat(t, "2", preventions[0].Quantity)
+	assertFloat(t, "2", preventions[0].MakerReleased)
+	assertFloat(t, "200", preventions[0].TakerReleased)
+	assert.Equal(t, orderEntity.OrderStatusCancelled, taker.Status)
+	assertFloat(t, "3", own.RemainingQuantity)
+	assertFloat(t, "3", own.Quantity)
+	assert.True(t, book.Contains("ask-1"))
+}
+
+func TestOrderBook_Match_SelfTradeNone(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	book.Add(newAccountOrder("ask-1", "acc-1", orderEntity.OrderTypeSell, "99", "1"))
+	taker := newAccountOrder("bid-1", "acc-1", orderEntity.OrderTypeBuy, "100", "1")
+	taker.SelfTradePrevention = orderEntity.SelfTradePreventionNone
+
+	// act
+	fills, preventions := book.Match(taker)
+
+	// assert
+	assert.Len(t, fills, 1)
+	assert.Empty(t, preventions)
+}
+
+func TestOrderBook_CanFill_SelfTrade(t *testing.T) {
+	// arrange
+	book := entity.NewOrderBook("inst-1")
+	book.Add(newAccountOrder("ask-1", "acc-1", orderEntity.OrderTypeSell, "99", "1"))
+	book.Add(newAccountOrder("ask-2", "acc-2", orderEntity.OrderTypeSell, "100", "1"))
+	cancelOldest := newAccountOrder("bid-1", "acc-1", orderEntity.OrderTypeBuy, "100", "1")
+	cancelOldest.SelfTradePrevention = orderEntity.SelfTradePreventionCancelOldest
+	cancelNewest := newAccountOrder("bid-2", "acc-1", orderEntity.OrderTypeBuy, "100", "1")
+	cancelNewest.SelfTradePrevention = orderEntity.SelfTradePreventionCancelNewest
+
+	// act & assert
+	assert.True(t, book.CanFill(cancelOldest))
+	assert.False(t, book.CanFill(cancelNewest))
+}
@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"math/big"
+	"time"
+)
+
+// PriceWindow keeps the recent trade prices of an instrument so a sudden move
+// can trip its circuit breaker.
+type PriceWindow struct {
+	samples []priceSample
+}
+
+type priceSample struct {
+	at    time.Time
+	price *big.Float
+}
+
+// Record adds a trade price seen at the given time.
+func (w *PriceWindow) Record(at time.Time, price *big.Float) {
+	w.samples = append(w.samples, priceSample{at: at, price: new(big.Float).Set(price)})
+}
+
+// Move drops the prices recorded before since and returns, in percent, how
+// far the latest price is from the one furthest from it among the rest. It is
+// zero with fewer than two prices.
+func (w *PriceWindow) Move(since time.Time) *big.Float {
+	i := 0
+	for i < len(w.samples) && w.samples[i].at.Before(since) {
+		i++
+	}
+	w.samples = w.samples[i:]
+
+	move := new(big.Float)
+	if len(w.samples) < 2 {
+		return move
+	}
+	last := w.samples[len(w.samples)-1].price
+	for _, sample := range w.samples[:len(w.samples)-1] {
+		change := new(big.Float).Sub(last, sample.price)
+		change.Abs(change).Quo(change, sample.price).Mul(change, big.NewFloat(100))
+		if change.Cmp(move) > 0 {
+			move = change
+		}
+	}
+	return move
+}
+
+// Reset forgets every recorded price, so a paused instrument starts afresh.
+func (w *PriceWindow) Reset() {
+	w.samples = nil
+}
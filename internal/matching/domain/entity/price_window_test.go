@@ -0,0 +1,40 @@
+package entity_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/domain/entity"
+)
+
+func TestPriceWindow_Move(t *testing.T) {
+	// arrange
+	start := time.Unix(0, 0)
+	window := &entity.PriceWindow{}
+	window.Record(start, big.NewFloat(80))
+	window.Record(start.Add(time.Second), big.NewFloat(100))
+	window.Record(start.Add(2*time.Second), big.NewFloat(110))
+
+	// act
+	move := window.Move(start)
+	later := window.Move(start.Add(time.Second))
+
+	// assert
+	assertFloat(t, "37.5", move)
+	assertFloat(t, "10", later)
+}
+
+func TestPriceWindow_Reset(t *testing.T) {
+	// arrange
+	start := time.Unix(0, 0)
+	window := &entity.PriceWindow{}
+	window.Record(start, big.NewFloat(100))
+	window.Record(start, big.NewFloat(200))
+
+	// act
+	window.Reset()
+
+	// assert
+	assertFloat(t, "0", window.Move(start))
+}
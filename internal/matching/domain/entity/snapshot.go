@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"math/big"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/domain/dto"
+)
+
+// Snapshot is a point-in-time view of an order book as the public sees it.
+// Auction is the last indicative uncross published while the instrument is in
+// an auction, nil otherwise.
+type Snapshot struct {
+	InstrumentID string
+	Bids         []Level
+	Asks         []Level
+	LastPrice    *big.Float
+	Auction      *Auction
+}
+
+// Level aggregates the shown quantity of the orders resting at one price.
+type Level struct {
+	Price    *big.Float
+	Quantity *big.Float
+	Orders   int
+}
+
+func (s Snapshot) ToDTO() dto.BookDTO {
+	book := dto.BookDTO{
+		InstrumentID: s.InstrumentID,
+		Bids:         toLevelDTOs(s.Bids),
+		Asks:         toLevelDTOs(s.Asks),
+		LastPrice:    s.LastPrice,
+	}
+	if s.Auction != nil {
+		book.IndicativePrice = s.Auction.Price
+		book.IndicativeQuantity = s.Auction.Quantity
+	}
+	return book
+}
+
+func toLevelDTOs(levels []Level) []dto.PriceLevelDTO {
+	dtos := make([]dto.PriceLevelDTO, len(levels))
+	for i, l := range levels {
+		dtos[i] = dto.PriceLevelDTO{
+			Price:    *l.Price,
+			Quantity: *l.Quantity,
+			Orders:   l.Orders,
+		}
+	}
+	return dtos
+}
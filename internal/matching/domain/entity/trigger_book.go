@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	orderEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+)
+
+// TriggerBook holds the stop orders of a single instrument until the last
+// trade price reaches their stop price, which trailing stops move as the
+// price runs in their favour.
+type TriggerBook struct {
+	InstrumentID string
+	// orders keeps arrival order so stops triggered together keep time priority.
+	orders []*orderEntity.Order
+}
+
+func NewTriggerBook(instrumentID string) *TriggerBook {
+	return &TriggerBook{InstrumentID: instrumentID}
+}
+
+// Add parks a stop order until it is triggered.
+func (b *TriggerBook) Add(order *orderEntity.Order) {
+	b.orders = append(b.orders, order)
+}
+
+// Remove takes a stop order out of the trigger book. It returns the removed
+// order and false when the order is not waiting here.
+func (b *TriggerBook) Remove(orderID string) (*orderEntity.Order, bool) {
+	for i, order := range b.orders {
+		if order.ID == orderID {
+			b.orders = append(b.orders[:i], b.orders[i+1:]...)
+			return order, true
+		}
+	}
+	return nil, false
+}
+
+// Order returns the waiting stop order with the given ID.
+func (b *TriggerBook) Order(orderID string) (*orderEntity.Order, bool) {
+	for _, order := range b.orders {
+		if order.ID == orderID {
+			return order, true
+		}
+	}
+	return nil, false
+}
+
+// Orders returns every waiting stop order in arrival order.
+func (b *TriggerBook) Orders() []*orderEntity.Order {
+	return append([]*orderEntity.Order(nil), b.orders...)
+}
+
+// Contains reports whether the stop order is waiting in the trigger book.
+func (b *TriggerBook) Contains(orderID string) bool {
+	for _, order := range b.orders {
+		if order.ID == orderID {
+			return true
+		}
+	}
+	return false
+}
+
+// Triggered removes and returns every stop order whose stop price is reached
+// by lastPrice, in arrival order.
+func (b *TriggerBook) Triggered(lastPrice *big.Float) []*orderEntity.Order {
+	var triggered []*orderEntity.Order
+	waiting := b.orders[:0]
+	for _, order := range b.orders {
+		if order.ShouldTrigger(lastPrice) {
+			triggered = append(triggered, order)
+			continue
+		}
+		waiting = append(waiting, order)
+	}
+	b.orders = waiting
+	return triggered
+}
+
+// Trail follows a trade at price with every waiting trailing stop and returns
+// those whose trigger price moved, in arrival order.
+func (b *TriggerBook) Trail(price *big.Float) []*orderEntity.Order {
+	var moved []*orderEntity.Order
+	for _, order := range b.orders {
+		if order.Trail(price) {
+			moved = append(moved, order)
+		}
+	}
+	return moved
+}
+
+// Expired returns the waiting stop orders whose deadline has passed at now.
+func (b *TriggerBook) Expired(now time.Time) []*orderEntity.Order {
+	var expired []*orderEntity.Order
+	for _, order := range b.orders {
+		if order.IsExpired(now) {
+			expired = append(expired, order)
+		}
+	}
+	sort.SliceStable(expired, func(i, j int) bool {
+		return expired[i].ExpiresAt.Before(*expired[j].ExpiresAt)
+	})
+	return expired
+}
@@ -0,0 +1,96 @@
+package entity_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/domain/entity"
+	orderEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func newStop(id string, orderType orderEntity.OrderType, stopPrice string) *orderEntity.Order {
+	order := newOrder(id, orderType, "0", "1")
+	order.Kind = orderEntity.OrderKindStopMarket
+	order.Price = nil
+	order.StopPrice, _ = new(big.Float).SetString(stopPrice)
+	return order
+}
+
+func TestTriggerBook_Triggered(t *testing.T) {
+	// arrange
+	book := entity.NewTriggerBook("inst-1")
+	book.Add(newStop("sell-1", orderEntity.OrderTypeSell, "95"))
+	book.Add(newStop("buy-1", orderEntity.OrderTypeBuy, "105"))
+	book.Add(newStop("sell-2", orderEntity.OrderTypeSell, "97"))
+
+	// act & assert
+	assert.Empty(t, book.Triggered(nil))
+	assert.Empty(t, book.Triggered(big.NewFloat(100)))
+
+	triggered := book.Triggered(big.NewFloat(96))
+	assert.Len(t, triggered, 1)
+	assert.Equal(t, "sell-2", triggered[0].ID)
+	assert.False(t, book.Contains("sell-2"))
+
+	triggered = book.Triggered(big.NewFloat(94))
+	assert.Len(t, triggered, 1)
+	assert.Equal(t, "sell-1", triggered[0].ID)
+	assert.True(t, book.Contains("buy-1"))
+}
+
+func TestTriggerBook_Trail(t *testing.T) {
+	// arrange
+	trailing := newStop("sell-1", orderEntity.OrderTypeSell, "0")
+	trailing.Kind = orderEntity.OrderKindTrailingStopMarket
+	trailing.StopPrice = nil
+	trailing.TrailAmount = big.NewFloat(5)
+	book := entity.NewTriggerBook("inst-1")
+	book.Add(newStop("sell-2", orderEntity.OrderTypeSell, "90"))
+	book.Add(trailing)
+
+	// act & assert
+	moved := book.Trail(big.NewFloat(100))
+	assert.Len(t, moved, 1)
+	assert.Equal(t, "sell-1", moved[0].ID)
+	assert.Empty(t, book.Trail(big.NewFloat(99)))
+	assert.Empty(t, book.Triggered(big.NewFloat(96)))
+
+	triggered := book.Triggered(big.NewFloat(95))
+	assert.Len(t, triggered, 1)
+	assert.Equal(t, "sell-1", triggered[0].ID)
+}
+
+func TestTriggerBook_Remove(t *testing.T) {
+	// arrange
+	book := entity.NewTriggerBook("inst-1")
+	book.Add(newStop("sell-1", orderEntity.OrderTypeSell, "95"))
+
+	// act
+	removed, ok := book.Remove("sell-1")
+	_, again := book.Remove("sell-1")
+
+	// assert
+	assert.True(t, ok)
+	assert.Equal(t, "sell-1", removed.ID)
+	assert.False(t, again)
+	assert.Empty(t, book.Triggered(big.NewFloat(1)))
+}
+
+func TestTriggerBook_Expired(t *testing.T) {
+	// arrange
+	now := time.Now()
+	book := entity.NewTriggerBook("inst-1")
+	gtd := newStop("sell-1", orderEntity.OrderTypeSell, "95")
+	gtd.TimeInForce, gtd.ExpiresAt = orderEntity.TimeInForceGTD, &now
+	book.Add(gtd)
+	book.Add(newStop("sell-2", orderEntity.OrderTypeSell, "95"))
+
+	// act
+	expired := book.Expired(now)
+
+	// assert
+	assert.Len(t, expired, 1)
+	assert.Equal(t, "sell-1", expired[0].ID)
+}
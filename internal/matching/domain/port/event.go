@@ -0,0 +1,11 @@
+package port
+
+import (
+	"context"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/domain/entity"
+)
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event entity.Event) error
+}
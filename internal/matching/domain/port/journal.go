@@ -0,0 +1,14 @@
+package port
+
+import (
+	"context"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/matching/domain/entity"
+)
+
+// Journal is the engine's append-only record of its inputs and outputs.
+// Append numbers the entries in the order given and returns once they are
+// durably stored.
+type Journal interface {
+	Append(ctx context.Context, entries ...entity.JournalEntry) error
+}
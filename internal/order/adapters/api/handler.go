@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"net/http"
@@ -13,12 +14,18 @@ import (
 
 type Order interface {
 	Create(ctx echo.Context) error
-	Update(ctx echo.Context) error
+	CreateBatch(ctx echo.Context) error
+	CreateOrderList(ctx echo.Context) error
+	FindOrderListByID(ctx echo.Context) error
+	Amend(ctx echo.Context) error
 	FindByID(ctx echo.Context) error
+	FindByClientOrderID(ctx echo.Context) error
 	GetOrders(ctx echo.Context) error
 	CancelByID(ctx echo.Context) error
+	CancelAll(ctx echo.Context) error
 	FindByInstrument(ctx echo.Context) error
 	RegisterRoutes(g *echo.Group)
+	RegisterListRoutes(g *echo.Group)
 }
 
 type order struct {
@@ -33,23 +40,34 @@ func NewOrderHandler(orderApp app.Order) Order {
 
 func (h *order) RegisterRoutes(g *echo.Group) {
 	g.POST("", h.Create)
+	g.POST("/batch", h.CreateBatch)
+	g.POST("/oco", h.CreateOrderList)
+	g.GET("/by-client-id/:client_order_id", h.FindByClientOrderID)
 	g.GET("/:id", h.FindByID)
 	g.GET("", h.GetOrders)
-	g.PUT("/:id", h.Update)
+	g.PUT("/:id", h.Amend)
+	g.POST("/cancel-all", h.CancelAll)
 	g.POST("/:id/cancel", h.CancelByID)
 	g.GET("/instrument/:instrument_id", h.FindByInstrument)
 }
 
+// RegisterListRoutes registers the order list routes.
+func (h *order) RegisterListRoutes(g *echo.Group) {
+	g.GET("/:id", h.FindOrderListByID)
+}
+
 // Create godoc
 // @Summary      Cria uma nova ordem
-// @Description  Cria uma nova ordem e envia para a fila
+// @Description  Cria uma nova ordem LIMIT, MARKET, STOP_MARKET, STOP_LIMIT ou TRAILING_STOP_MARKET e envia para a fila. Ordens MARKET não têm preço; ordens TRAILING_STOP_MARKET usam trail_amount ou trail_percent no lugar de stop_price; compras MARKET usam quote_quantity (time_in_force: GTC, IOC, FOK ou GTD com expires_at). Uma nova tentativa com o mesmo Idempotency-Key retorna a ordem criada pela primeira, sem duplicá-la; reutilizar a chave para uma ordem diferente retorna 409. Ordens que violam as regras de negociação do instrumento (tick size, lot size, quantidade mínima/máxima, notional mínimo) são rejeitadas com a lista de motivos.
 // @Tags         orders
 // @Accept       json
 // @Produce      json
-// @Param        order  body      dto.CreateOrderRequest  true  "Order"
-// @Success      201    {object}  dto.OrderDTO
-// @Failure      400    {object}  map[string]string
-// @Failure      409    {object}  map[string]string
+// @Param        Idempotency-Key  header    string                  false  "Chave de idempotência, única por conta"
+// @Param        order            body      dto.CreateOrderRequest  true   "Order"
+// @Success      201              {object}  dto.CreateOrderResponse
+// @Failure      400              {object}  dto.RejectedOrderResponse "order breaks the instrument's trading rules"
+// @Failure      409              {object}  map[string]string "client_order_id already in use or instrument not trading"
+// @Failure      422    {object}  map[string]string "insufficient balance"
 // @Router       /v1/orders [post]
 func (h *order) Create(ctx echo.Context) error {
 	var request dto.CreateOrderRequest
@@ -57,6 +75,7 @@ func (h *order) Create(ctx echo.Context) error {
 	if err := ctx.Bind(&request); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid request payload: "+err.Error())
 	}
+	request.IdempotencyKey = ctx.Request().Header.Get("Idempotency-Key")
 
 	if err := request.Validate(); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "validation failed: "+err.Error())
@@ -64,11 +83,18 @@ func (h *order) Create(ctx echo.Context) error {
 
 	order, err := h.orderApp.Create(ctx.Request().Context(), request)
 	if err != nil {
+		if rejections := app.ToRejectionListDTO(err); rejections != nil {
+			return ctx.JSON(http.StatusBadRequest, dto.RejectedOrderResponse{Message: err.Error(), Rejections: rejections})
+		}
 		switch {
+		case errors.Is(err, ierr.ErrInvalidInput):
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 		case errors.Is(err, ierr.ErrConflict):
 			return echo.NewHTTPError(http.StatusConflict, err.Error())
 		case errors.Is(err, ierr.ErrNotFound):
 			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		case errors.Is(err, ierr.ErrInsufficientBalance):
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
 		default:
 			slog.Error("error creating order", "error", err)
 			return echo.NewHTTPError(http.StatusInternalServerError, "an unexpected error occurred")
@@ -78,44 +104,152 @@ func (h *order) Create(ctx echo.Context) error {
 	return ctx.JSON(http.StatusCreated, order)
 }
 
-// Update godoc
-// @Summary      Atualiza uma ordem
+// CreateBatch godoc
+// @Summary      Cria ordens em lote
+// @Description  Cria até 50 ordens numa única chamada e retorna, na ordem do pedido, o ID ou o erro de cada uma, com os motivos quando a ordem viola as regras de negociação. O saldo é reservado considerando o lote inteiro, então duas ordens nunca usam os mesmos fundos.
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        orders  body      dto.BatchCreateOrderRequest  true  "Orders"
+// @Success      200     {object}  dto.BatchCreateOrderResponse
+// @Failure      400     {object}  map[string]string
+// @Failure      409     {object}  map[string]string "client_order_id already in use"
+// @Router       /v1/orders/batch [post]
+func (h *order) CreateBatch(ctx echo.Context) error {
+	var request dto.BatchCreateOrderRequest
+	if err := ctx.Bind(&request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request payload: "+err.Error())
+	}
+
+	response, err := h.orderApp.CreateBatch(ctx.Request().Context(), request)
+	if err != nil {
+		switch {
+		case errors.Is(err, ierr.ErrInvalidInput):
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		case errors.Is(err, ierr.ErrConflict):
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		default:
+			slog.Error("error creating order batch", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "an unexpected error occurred")
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// CreateOrderList godoc
+// @Summary      Cria uma lista de ordens OCO
+// @Description  Cria duas ordens ligadas (one-cancels-other) com um ID de lista comum: uma ordem LIMIT e uma STOP_MARKET ou STOP_LIMIT da mesma conta, instrumento e lado, tipicamente um take-profit e um stop-loss. O preço LIMIT fica acima do stop_price nas vendas e abaixo nas compras. Uma única reserva de saldo, a maior entre as duas ordens, cobre ambas. Quando uma ordem é executada, total ou parcialmente, ou a ordem stop é disparada, o motor cancela a outra.
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        list  body      dto.CreateOrderListRequest  true  "Order list"
+// @Success      201   {object}  dto.CreateOrderListResponse
+// @Failure      400   {object}  dto.RejectedOrderResponse "order breaks the instrument's trading rules"
+// @Failure      409   {object}  map[string]string "client_order_id already in use or instrument not trading"
+// @Failure      422   {object}  map[string]string "insufficient balance"
+// @Router       /v1/orders/oco [post]
+func (h *order) CreateOrderList(ctx echo.Context) error {
+	var request dto.CreateOrderListRequest
+	if err := ctx.Bind(&request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request payload: "+err.Error())
+	}
+
+	list, err := h.orderApp.CreateOrderList(ctx.Request().Context(), request)
+	if err != nil {
+		if rejections := app.ToRejectionListDTO(err); rejections != nil {
+			return ctx.JSON(http.StatusBadRequest, dto.RejectedOrderResponse{Message: err.Error(), Rejections: rejections})
+		}
+		switch {
+		case errors.Is(err, ierr.ErrInvalidInput):
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		case errors.Is(err, ierr.ErrConflict):
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		case errors.Is(err, ierr.ErrNotFound):
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		case errors.Is(err, ierr.ErrInsufficientBalance):
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		default:
+			slog.Error("error creating order list", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "an unexpected error occurred")
+		}
+	}
+
+	return ctx.JSON(http.StatusCreated, list)
+}
+
+// FindOrderListByID godoc
+// @Summary      Busca uma lista de ordens por ID
+// @Description  Retorna a lista com seu status (EXECUTING enquanto as duas ordens estão ativas, ALL_DONE depois), a reserva compartilhada e as ordens.
+// @Tags         orders
+// @Produce      json
+// @Param        id   path      string  true  "Order list ID"
+// @Success      200  {object}  dto.OrderListDTO
+// @Failure      404  {object}  map[string]string
+// @Router       /v1/order-lists/{id} [get]
+func (h *order) FindOrderListByID(ctx echo.Context) error {
+	id := ctx.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "order list ID cannot be empty")
+	}
+
+	list, err := h.orderApp.FindOrderListByID(ctx.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, ierr.ErrNotFound)
+		}
+		slog.Error("error finding order list by ID", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "an unexpected error occurred")
+	}
+
+	return ctx.JSON(http.StatusOK, list)
+}
+
+// Amend godoc
+// @Summary      Altera uma ordem em aberto
+// @Description  Altera preço e/ou quantidade total de uma ordem ativa. Reduzir a quantidade mantém a prioridade; alterar o preço ou aumentar a quantidade a perde. A alteração é aplicada de forma assíncrona pelo motor de matching.
 // @Tags         orders
 // @Accept       json
 // @Produce      json
 // @Param        id     path      string                 true  "Order ID"
-// @Param        order  body      dto.CreateOrderRequest true  "Order"
-// @Success      204    "No Content"
-// @Failure      400    {object}  map[string]string
+// @Param        order  body      dto.AmendOrderRequest  true  "Amendment"
+// @Success      202    "Accepted"
+// @Failure      400    {object}  dto.RejectedOrderResponse "order breaks the instrument's trading rules"
 // @Failure      404    {object}  map[string]string
+// @Failure      409    {object}  map[string]string "order is no longer active"
+// @Failure      422    {object}  map[string]string "insufficient balance"
 // @Router       /v1/orders/{id} [put]
-func (h *order) Update(ctx echo.Context) error {
+func (h *order) Amend(ctx echo.Context) error {
 	id := ctx.Param("id")
 	if id == "" {
 		return echo.NewHTTPError(http.StatusBadRequest, "order ID cannot be empty")
 	}
 
-	var request dto.CreateOrderRequest
+	var request dto.AmendOrderRequest
 	if err := ctx.Bind(&request); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
-	if err := request.Validate(); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err)
-	}
-
-	// Aqui você pode adaptar para aceitar um DTO específico de update, se desejar.
-	if err := h.orderApp.Update(ctx.Request().Context(), request); err != nil {
+	if err := h.orderApp.Amend(ctx.Request().Context(), id, request); err != nil {
+		if rejections := app.ToRejectionListDTO(err); rejections != nil {
+			return ctx.JSON(http.StatusBadRequest, dto.RejectedOrderResponse{Message: err.Error(), Rejections: rejections})
+		}
 		switch {
 		case errors.Is(err, ierr.ErrNotFound):
 			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		case errors.Is(err, ierr.ErrInvalidInput):
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		case errors.Is(err, ierr.ErrConflict):
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		case errors.Is(err, ierr.ErrInsufficientBalance):
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
 		default:
-			slog.Error("error updating order", "error", err)
+			slog.Error("error amending order", "error", err)
 			return echo.NewHTTPError(http.StatusInternalServerError, "an unexpected error occurred")
 		}
 	}
 
-	return ctx.NoContent(http.StatusNoContent)
+	return ctx.NoContent(http.StatusAccepted)
 }
 
 // FindByID godoc
@@ -144,6 +278,35 @@ func (h *order) FindByID(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, order)
 }
 
+// FindByClientOrderID godoc
+// @Summary      Busca uma ordem pelo client_order_id
+// @Tags         orders
+// @Produce      json
+// @Param        client_order_id  path   string  true  "Client order ID"
+// @Param        account_id       query  string  true  "Account ID"
+// @Success      200  {object}  dto.OrderDTO
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /v1/orders/by-client-id/{client_order_id} [get]
+func (h *order) FindByClientOrderID(ctx echo.Context) error {
+	clientOrderID := ctx.Param("client_order_id")
+	accountID := ctx.QueryParam("account_id")
+	if clientOrderID == "" || accountID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "client_order_id and account_id are required")
+	}
+
+	order, err := h.orderApp.FindByClientOrderID(ctx.Request().Context(), accountID, clientOrderID)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, ierr.ErrNotFound)
+		}
+		slog.Error("error finding order by client order ID", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "an unexpected error occurred")
+	}
+
+	return ctx.JSON(http.StatusOK, order)
+}
+
 // GetOrders godoc
 // @Summary      Lista todas as ordens
 // @Tags         orders
@@ -160,11 +323,14 @@ func (h *order) GetOrders(ctx echo.Context) error {
 
 // CancelByID godoc
 // @Summary      Cancela uma ordem
+// @Description  Envia um comando de cancelamento ao motor de matching e aguarda a confirmação. A ordem só é considerada cancelada, e o saldo reservado liberado, após a confirmação; 409 indica que a ordem já estava executada ou cancelada.
 // @Tags         orders
 // @Produce      json
 // @Param        id   path      string  true  "Order ID"
 // @Success      204  "No Content"
 // @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string "order is no longer active"
+// @Failure      504  {object}  map[string]string "cancel was not confirmed in time"
 // @Router       /v1/orders/{id}/cancel [post]
 func (h *order) CancelByID(ctx echo.Context) error {
 	id := ctx.Param("id")
@@ -176,6 +342,10 @@ func (h *order) CancelByID(ctx echo.Context) error {
 		switch {
 		case errors.Is(err, ierr.ErrNotFound):
 			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		case errors.Is(err, ierr.ErrConflict):
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		case errors.Is(err, context.DeadlineExceeded):
+			return echo.NewHTTPError(http.StatusGatewayTimeout, "cancel was not confirmed in time")
 		default:
 			slog.Error("error cancelling order", "error", err)
 			return echo.NewHTTPError(http.StatusInternalServerError, "an unexpected error occurred")
@@ -185,6 +355,39 @@ func (h *order) CancelByID(ctx echo.Context) error {
 	return ctx.NoContent(http.StatusNoContent)
 }
 
+// CancelAll godoc
+// @Summary      Cancela ordens em massa
+// @Description  Cancela, numa única operação do motor de matching, todas as ordens ativas que atendem ao filtro (account_id e/ou instrument_id, opcionalmente side) e retorna os IDs cancelados.
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        filter  body      dto.CancelAllRequest  true  "Filter"
+// @Success      200     {object}  dto.CancelAllResponse
+// @Failure      400     {object}  map[string]string
+// @Failure      504     {object}  map[string]string "cancel was not confirmed in time"
+// @Router       /v1/orders/cancel-all [post]
+func (h *order) CancelAll(ctx echo.Context) error {
+	var request dto.CancelAllRequest
+	if err := ctx.Bind(&request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	response, err := h.orderApp.CancelAll(ctx.Request().Context(), request)
+	if err != nil {
+		switch {
+		case errors.Is(err, ierr.ErrInvalidInput):
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		case errors.Is(err, context.DeadlineExceeded):
+			return echo.NewHTTPError(http.StatusGatewayTimeout, "cancel was not confirmed in time")
+		default:
+			slog.Error("error cancelling orders", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "an unexpected error occurred")
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
 // FindByInstrument godoc
 // @Summary      Busca as orders por um Intrument
 // @Tags         orders
@@ -1,35 +1,222 @@
 package repository
 
 import (
+	"math/big"
 	"time"
 
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/decimal"
 )
 
 type OrderModel struct {
-	ID                string    `json:"id"`
-	AccountID         string    `json:"account_id"`
-	InstrumentID      string    `json:"instrument_id"`
-	Type              string    `json:"type"`
-	Status            string    `json:"status"`
-	Price             string    `json:"price"`
-	Quantity          string    `json:"quantity"`
-	RemainingQuantity string    `json:"remaining_quantity"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	ID                     string     `json:"id"`
+	AccountID              string     `json:"account_id"`
+	InstrumentID           string     `json:"instrument_id"`
+	Type                   string     `json:"type"`
+	Kind                   string     `json:"kind"`
+	Status                 string     `json:"status"`
+	Price                  *string    `json:"price,omitempty"`
+	StopPrice              *string    `json:"stop_price,omitempty"`
+	TrailAmount            *string    `json:"trail_amount,omitempty"`
+	TrailPercent           *string    `json:"trail_percent,omitempty"`
+	WaterMark              *string    `json:"water_mark,omitempty"`
+	Quantity               string     `json:"quantity"`
+	RemainingQuantity      string     `json:"remaining_quantity"`
+	QuoteQuantity          *string    `json:"quote_quantity,omitempty"`
+	RemainingQuoteQuantity *string    `json:"remaining_quote_quantity,omitempty"`
+	DisplayQuantity        *string    `json:"display_quantity,omitempty"`
+	VisibleQuantity        *string    `json:"visible_quantity,omitempty"`
+	TimeInForce            string     `json:"time_in_force"`
+	ExpiresAt              *time.Time `json:"expires_at,omitempty"`
+	TriggeredAt            *time.Time `json:"triggered_at,omitempty"`
+	PostOnly               bool       `json:"post_only"`
+	RepriceOnCross         bool       `json:"reprice_on_cross"`
+	ClientOrderID          string     `json:"client_order_id,omitempty"`
+	SelfTradePrevention    string     `json:"self_trade_prevention,omitempty"`
+	OrderListID            string     `json:"order_list_id,omitempty"`
+	CreatedAt              time.Time  `json:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at"`
 }
 
 func ToModel(entity entity.Order) *OrderModel {
 	return &OrderModel{
-		ID:                entity.ID,
-		AccountID:         entity.AccountID,
-		InstrumentID:      entity.InstrumentID,
-		Type:              string(entity.Type),
-		Status:            string(entity.Status),
-		Price:             entity.Price.String(),
-		Quantity:          entity.Quantity.String(),
-		RemainingQuantity: entity.RemainingQuantity.String(),
-		CreatedAt:         entity.CreatedAt,
-		UpdatedAt:         entity.UpdatedAt,
+		ID:                     entity.ID,
+		AccountID:              entity.AccountID,
+		InstrumentID:           entity.InstrumentID,
+		Type:                   string(entity.Type),
+		Kind:                   string(entity.Kind),
+		Status:                 string(entity.Status),
+		Price:                  formatOptional(entity.Price, 10),
+		StopPrice:              formatOptional(entity.StopPrice, 10),
+		TrailAmount:            formatOptional(entity.TrailAmount, 10),
+		TrailPercent:           formatOptional(entity.TrailPercent, 10),
+		WaterMark:              formatOptional(entity.WaterMark, 10),
+		Quantity:               entity.Quantity.Text('f', 18),
+		RemainingQuantity:      entity.RemainingQuantity.Text('f', 18),
+		QuoteQuantity:          formatOptional(entity.QuoteQuantity, 18),
+		RemainingQuoteQuantity: formatOptional(entity.RemainingQuoteQuantity, 18),
+		DisplayQuantity:        formatOptional(entity.DisplayQuantity, 18),
+		VisibleQuantity:        formatOptional(entity.VisibleQuantity, 18),
+		TimeInForce:            string(entity.TimeInForce),
+		ExpiresAt:              entity.ExpiresAt,
+		TriggeredAt:            entity.TriggeredAt,
+		PostOnly:               entity.PostOnly,
+		RepriceOnCross:         entity.RepriceOnCross,
+		ClientOrderID:          entity.ClientOrderID,
+		SelfTradePrevention:    string(entity.SelfTradePrevention),
+		OrderListID:            entity.OrderListID,
+		CreatedAt:              entity.CreatedAt,
+		UpdatedAt:              entity.UpdatedAt,
 	}
 }
+
+func (m *OrderModel) ToEntity() entity.Order {
+	quantity, _ := decimal.Parse(m.Quantity)
+	remaining, _ := decimal.Parse(m.RemainingQuantity)
+	order := entity.Order{
+		ID:                     m.ID,
+		AccountID:              m.AccountID,
+		InstrumentID:           m.InstrumentID,
+		Type:                   entity.OrderType(m.Type),
+		Kind:                   entity.OrderKind(m.Kind),
+		Status:                 entity.OrderStatus(m.Status),
+		Price:                  decimal.ParseOptional(m.Price),
+		StopPrice:              decimal.ParseOptional(m.StopPrice),
+		TrailAmount:            decimal.ParseOptional(m.TrailAmount),
+		TrailPercent:           decimal.ParseOptional(m.TrailPercent),
+		WaterMark:              decimal.ParseOptional(m.WaterMark),
+		Quantity:               quantity,
+		RemainingQuantity:      remaining,
+		QuoteQuantity:          decimal.ParseOptional(m.QuoteQuantity),
+		RemainingQuoteQuantity: decimal.ParseOptional(m.RemainingQuoteQuantity),
+		DisplayQuantity:        decimal.ParseOptional(m.DisplayQuantity),
+		VisibleQuantity:        decimal.ParseOptional(m.VisibleQuantity),
+		TimeInForce:            entity.TimeInForce(m.TimeInForce),
+		ExpiresAt:              m.ExpiresAt,
+		TriggeredAt:            m.TriggeredAt,
+		PostOnly:               m.PostOnly,
+		RepriceOnCross:         m.RepriceOnCross,
+		ClientOrderID:          m.ClientOrderID,
+		SelfTradePrevention:    entity.SelfTradePrevention(m.SelfTradePrevention),
+		OrderListID:            m.OrderListID,
+		CreatedAt:              m.CreatedAt,
+		UpdatedAt:              m.UpdatedAt,
+	}
+	if order.Kind == "" {
+		order.Kind = entity.OrderKindLimit
+	}
+	if order.TimeInForce == "" {
+		order.TimeInForce = entity.TimeInForceGTC
+	}
+	if order.SelfTradePrevention == "" {
+		order.SelfTradePrevention = entity.SelfTradePreventionNone
+	}
+	return order
+}
+
+// formatOptional renders a nullable decimal column; nil stays nil.
+func formatOptional(value *big.Float, decimals int) *string {
+	if value == nil {
+		return nil
+	}
+	s := value.Text('f', decimals)
+	return &s
+}
+
+// AmendModel is the queue message asking the engine to amend a working order.
+type AmendModel struct {
+	ID           string  `json:"id"`
+	OrderID      string  `json:"order_id"`
+	InstrumentID string  `json:"instrument_id"`
+	Price        *string `json:"price,omitempty"`
+	Quantity     *string `json:"quantity,omitempty"`
+	Reserved     string  `json:"reserved"`
+}
+
+func ToAmendModel(amendment entity.Amendment) *AmendModel {
+	return &AmendModel{
+		ID:           amendment.ID,
+		OrderID:      amendment.OrderID,
+		InstrumentID: amendment.InstrumentID,
+		Price:        formatOptional(amendment.Price, 10),
+		Quantity:     formatOptional(amendment.Quantity, 18),
+		Reserved:     amendment.Reserved.Text('f', 18),
+	}
+}
+
+func (m *AmendModel) ToEntity() entity.Amendment {
+	reserved, ok := decimal.Parse(m.Reserved)
+	if !ok {
+		reserved = new(big.Float)
+	}
+	return entity.Amendment{
+		ID:           m.ID,
+		OrderID:      m.OrderID,
+		InstrumentID: m.InstrumentID,
+		Price:        decimal.ParseOptional(m.Price),
+		Quantity:     decimal.ParseOptional(m.Quantity),
+		Reserved:     reserved,
+	}
+}
+
+// CancelModel is the queue message asking the engine to cancel an order.
+type CancelModel struct {
+	OrderID      string `json:"order_id"`
+	InstrumentID string `json:"instrument_id"`
+}
+
+// CancelResultModel is the engine's reply to a CancelModel.
+type CancelResultModel struct {
+	OrderID   string `json:"order_id"`
+	Cancelled bool   `json:"cancelled"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+func ToCancelResultModel(result entity.CancelResult) *CancelResultModel {
+	return &CancelResultModel{
+		OrderID:   result.OrderID,
+		Cancelled: result.Cancelled,
+		Status:    string(result.Status),
+		Reason:    result.Reason,
+	}
+}
+
+func (m *CancelResultModel) ToEntity() entity.CancelResult {
+	return entity.CancelResult{
+		OrderID:   m.OrderID,
+		Cancelled: m.Cancelled,
+		Status:    entity.OrderStatus(m.Status),
+		Reason:    m.Reason,
+	}
+}
+
+// CancelAllModel is the queue message asking the engine to cancel every active
+// order matching a filter.
+type CancelAllModel struct {
+	AccountID    string `json:"account_id,omitempty"`
+	InstrumentID string `json:"instrument_id,omitempty"`
+	Side         string `json:"side,omitempty"`
+}
+
+func ToCancelAllModel(filter entity.CancelFilter) *CancelAllModel {
+	return &CancelAllModel{
+		AccountID:    filter.AccountID,
+		InstrumentID: filter.InstrumentID,
+		Side:         string(filter.Side),
+	}
+}
+
+func (m *CancelAllModel) ToEntity() entity.CancelFilter {
+	return entity.CancelFilter{
+		AccountID:    m.AccountID,
+		InstrumentID: m.InstrumentID,
+		Side:         entity.OrderType(m.Side),
+	}
+}
+
+// CancelAllResultModel is the engine's reply to a CancelAllModel.
+type CancelAllResultModel struct {
+	CancelledOrderIDs []string `json:"cancelled_order_ids"`
+	Error             string   `json:"error,omitempty"`
+}
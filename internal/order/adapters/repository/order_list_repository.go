@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/db"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/port"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/decimal"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/ierr"
+)
+
+const orderListColumns = `id, account_id, instrument_id, contingency_type, status, reserved, shared_reserved, created_at, updated_at`
+
+type orderListRepository struct {
+	db     *pgxpool.Pool
+	orders *orderRepository
+}
+
+func NewOrderListRepository(db *pgxpool.Pool) port.OrderListRepository {
+	return &orderListRepository{db: db, orders: &orderRepository{db: db}}
+}
+
+func (r *orderListRepository) Create(ctx context.Context, list entity.OrderList) (string, error) {
+	query := `INSERT INTO order_lists (account_id, instrument_id, contingency_type, status, reserved, shared_reserved, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW()) RETURNING id`
+
+	var id string
+	err := db.Conn(ctx, r.db).QueryRow(ctx, query,
+		list.AccountID,
+		list.InstrumentID,
+		string(list.ContingencyType),
+		string(list.Status),
+		list.Reserved.Text('f', 18),
+		list.Shared.Text('f', 18),
+	).Scan(&id)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *orderListRepository) FindByID(ctx context.Context, id string) (entity.OrderList, error) {
+	query := `SELECT ` + orderListColumns + ` FROM order_lists WHERE id = $1`
+	return r.queryOne(ctx, query, id)
+}
+
+// FindForUpdate reads an order list and locks it until the surrounding
+// transaction ends, so only one caller at a time can finish it.
+func (r *orderListRepository) FindForUpdate(ctx context.Context, id string) (entity.OrderList, error) {
+	query := `SELECT ` + orderListColumns + ` FROM order_lists WHERE id = $1 FOR UPDATE`
+	return r.queryOne(ctx, query, id)
+}
+
+func (r *orderListRepository) UpdateStatus(ctx context.Context, id string, status entity.OrderListStatus) error {
+	query := `UPDATE order_lists SET status = $1, updated_at = NOW() WHERE id = $2`
+	result, err := db.Conn(ctx, r.db).Exec(ctx, query, string(status), id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("no order list found with id: %s", id)
+	}
+	return nil
+}
+
+// queryOne reads a single order list together with its orders.
+func (r *orderListRepository) queryOne(ctx context.Context, query string, args ...any) (entity.OrderList, error) {
+	var list entity.OrderList
+	var reservedStr, sharedStr string
+	err := db.Conn(ctx, r.db).QueryRow(ctx, query, args...).Scan(
+		&list.ID,
+		&list.AccountID,
+		&list.InstrumentID,
+		&list.ContingencyType,
+		&list.Status,
+		&reservedStr,
+		&sharedStr,
+		&list.CreatedAt,
+		&list.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.OrderList{}, ierr.ErrNotFound
+		}
+		return entity.OrderList{}, err
+	}
+	list.Reserved, _ = decimal.Parse(reservedStr)
+	list.Shared, _ = decimal.Parse(sharedStr)
+
+	if list.Orders, err = r.orders.FindByOrderListID(ctx, list.ID); err != nil {
+		return entity.OrderList{}, err
+	}
+	return list, nil
+}
@@ -4,15 +4,35 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math/big"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/db"
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/port"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/decimal"
 	"github.com/mthpedrosa/financial-exchange-challenge/pkg/ierr"
 )
 
+const orderColumns = `id, account_id, instrument_id, type, kind, status, price, stop_price, trail_amount, trail_percent, water_mark, quantity, remaining_quantity,
+	quote_quantity, remaining_quote_quantity, display_quantity, visible_quantity, time_in_force, expires_at, triggered_at, post_only, reprice_on_cross,
+	client_order_id, self_trade_prevention, order_list_id, book_sequence, amendment_id, created_at, updated_at`
+
+const insertOrder = `INSERT INTO orders (account_id, instrument_id, type, kind, status, price, stop_price, trail_amount, trail_percent, quantity, remaining_quantity,
+	quote_quantity, remaining_quote_quantity, display_quantity, visible_quantity, time_in_force, expires_at, post_only,
+	reprice_on_cross, client_order_id, idempotency_key, self_trade_prevention, order_list_id, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, NOW(), NOW()) RETURNING id`
+
+// Unique indexes whose violations are reported as conflicts.
+const (
+	clientOrderIDIndex  = "idx_orders_account_client_order_id"
+	idempotencyKeyIndex = "idx_orders_account_idempotency_key"
+)
+
+// uniqueViolationCode is the PostgreSQL error code of a unique_violation.
+const uniqueViolationCode = "23505"
+
 type orderRepository struct {
 	db *pgxpool.Pool
 }
@@ -22,92 +42,134 @@ func NewOrderRepository(db *pgxpool.Pool) port.OrderRepository {
 }
 
 func (r *orderRepository) Create(ctx context.Context, order entity.Order) (string, error) {
-	query := `INSERT INTO orders (account_id, instrument_id, type, status, price, quantity, remaining_quantity, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW()) RETURNING id`
 	var id string
-	err := r.db.QueryRow(ctx, query,
+	if err := db.Conn(ctx, r.db).QueryRow(ctx, insertOrder, insertArgs(order)...).Scan(&id); err != nil {
+		return "", conflict(err)
+	}
+	return id, nil
+}
+
+// CreateBatch inserts several orders in a single round trip and returns their
+// IDs in the same order.
+func (r *orderRepository) CreateBatch(ctx context.Context, orders []entity.Order) ([]string, error) {
+	batch := &pgx.Batch{}
+	for _, order := range orders {
+		batch.Queue(insertOrder, insertArgs(order)...)
+	}
+
+	results := db.Conn(ctx, r.db).SendBatch(ctx, batch)
+	ids := make([]string, len(orders))
+	for i := range orders {
+		if err := results.QueryRow().Scan(&ids[i]); err != nil {
+			_ = results.Close()
+			return nil, conflict(err)
+		}
+	}
+	if err := results.Close(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func insertArgs(order entity.Order) []any {
+	return []any{
 		order.AccountID,
 		order.InstrumentID,
 		string(order.Type),
+		string(order.Kind),
 		string(order.Status),
-		order.Price.Text('f', 10),
+		formatOptional(order.Price, 10),
+		formatOptional(order.StopPrice, 10),
+		formatOptional(order.TrailAmount, 10),
+		formatOptional(order.TrailPercent, 10),
 		order.Quantity.Text('f', 18),
 		order.RemainingQuantity.Text('f', 18),
-	).Scan(&id)
-	if err != nil {
-		return "", err
+		formatOptional(order.QuoteQuantity, 18),
+		formatOptional(order.RemainingQuoteQuantity, 18),
+		formatOptional(order.DisplayQuantity, 18),
+		formatOptional(order.VisibleQuantity, 18),
+		string(order.TimeInForce),
+		order.ExpiresAt,
+		order.PostOnly,
+		order.RepriceOnCross,
+		nullIfEmpty(order.ClientOrderID),
+		nullIfEmpty(order.IdempotencyKey),
+		string(order.SelfTradePrevention),
+		nullIfEmpty(order.OrderListID),
 	}
-	return id, nil
 }
 
-func (r *orderRepository) FindByID(ctx context.Context, id string) (entity.Order, error) {
-	query := `SELECT id, account_id, instrument_id, type, status, price, quantity, remaining_quantity, created_at, updated_at FROM orders WHERE id = $1`
-	var o entity.Order
-	var priceStr, quantityStr, remainingStr string
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&o.ID,
-		&o.AccountID,
-		&o.InstrumentID,
-		&o.Type,
-		&o.Status,
-		&priceStr,
-		&quantityStr,
-		&remainingStr,
-		&o.CreatedAt,
-		&o.UpdatedAt,
-	)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return entity.Order{}, ierr.ErrNotFound
-		}
-		return entity.Order{}, err
+// conflict reports a violation of the client order ID or idempotency key
+// indexes as ierr.ErrConflict. Other errors are returned unchanged.
+func conflict(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != uniqueViolationCode {
+		return err
 	}
-	o.Price, _ = new(big.Float).SetString(priceStr)
-	o.Quantity, _ = new(big.Float).SetString(quantityStr)
-	o.RemainingQuantity, _ = new(big.Float).SetString(remainingStr)
-	return o, nil
+	switch pgErr.ConstraintName {
+	case clientOrderIDIndex:
+		return fmt.Errorf("client_order_id already in use: %w", ierr.ErrConflict)
+	case idempotencyKeyIndex:
+		return fmt.Errorf("idempotency key already used: %w", ierr.ErrConflict)
+	}
+	return err
 }
 
-func (r *orderRepository) GetAll(ctx context.Context) ([]entity.Order, error) {
-	query := `SELECT id, account_id, instrument_id, type, status, price, quantity, remaining_quantity, created_at, updated_at FROM orders`
-	rows, err := r.db.Query(ctx, query)
-	if err != nil {
-		return nil, err
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
 	}
-	defer rows.Close()
+	return &s
+}
 
-	var orders []entity.Order
-	for rows.Next() {
-		var o entity.Order
-		var priceStr, quantityStr, remainingStr string
-		if err := rows.Scan(
-			&o.ID,
-			&o.AccountID,
-			&o.InstrumentID,
-			&o.Type,
-			&o.Status,
-			&priceStr,
-			&quantityStr,
-			&remainingStr,
-			&o.CreatedAt,
-			&o.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		o.Price, _ = new(big.Float).SetString(priceStr)
-		o.Quantity, _ = new(big.Float).SetString(quantityStr)
-		o.RemainingQuantity, _ = new(big.Float).SetString(remainingStr)
-		orders = append(orders, o)
+func nullIfZero(n int64) *int64 {
+	if n == 0 {
+		return nil
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return orders, nil
+	return &n
+}
+
+func (r *orderRepository) FindByID(ctx context.Context, id string) (entity.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE id = $1`
+	return r.queryOne(ctx, query, id)
+}
+
+// FindByClientOrderID finds an order by the ID its account assigned to it.
+func (r *orderRepository) FindByClientOrderID(ctx context.Context, accountID, clientOrderID string) (entity.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE account_id = $1 AND client_order_id = $2`
+	return r.queryOne(ctx, query, accountID, clientOrderID)
+}
+
+// FindByIdempotencyKey finds the order created by an account's request with
+// the given idempotency key.
+func (r *orderRepository) FindByIdempotencyKey(ctx context.Context, accountID, key string) (entity.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE account_id = $1 AND idempotency_key = $2`
+	return r.queryOne(ctx, query, accountID, key)
+}
+
+func (r *orderRepository) GetAll(ctx context.Context) ([]entity.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders`
+	return r.query(ctx, query)
 }
 
 func (r *orderRepository) Update(ctx context.Context, order entity.Order) error {
-	query := `UPDATE orders SET status=$1, updated_at=NOW() WHERE id=$2`
-	result, err := r.db.Exec(ctx, query, string(order.Status), order.ID)
+	query := `UPDATE orders SET status=$1, price=$2, stop_price=$3, water_mark=$4, quantity=$5, remaining_quantity=$6,
+		remaining_quote_quantity=$7, visible_quantity=$8, triggered_at=$9, book_sequence=$10, amendment_id=$11,
+		updated_at=NOW() WHERE id=$12`
+	result, err := db.Conn(ctx, r.db).Exec(ctx, query,
+		string(order.Status),
+		formatOptional(order.Price, 10),
+		formatOptional(order.StopPrice, 10),
+		formatOptional(order.WaterMark, 10),
+		order.Quantity.Text('f', 18),
+		order.RemainingQuantity.Text('f', 18),
+		formatOptional(order.RemainingQuoteQuantity, 18),
+		formatOptional(order.VisibleQuantity, 18),
+		order.TriggeredAt,
+		nullIfZero(order.BookSequence),
+		nullIfEmpty(order.AmendmentID),
+		order.ID,
+	)
 	if err != nil {
 		return err
 	}
@@ -117,9 +179,62 @@ func (r *orderRepository) Update(ctx context.Context, order entity.Order) error
 	return nil
 }
 
+// ClearIdempotencyKey frees the idempotency key of an order, so a retried
+// request with it creates a new order.
+func (r *orderRepository) ClearIdempotencyKey(ctx context.Context, id string) error {
+	query := `UPDATE orders SET idempotency_key = NULL, updated_at = NOW() WHERE id = $1`
+	result, err := db.Conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("no order found with id: %s", id)
+	}
+	return nil
+}
+
+// FindByOrderListID returns the orders of an order list.
+func (r *orderRepository) FindByOrderListID(ctx context.Context, orderListID string) ([]entity.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE order_list_id = $1 ORDER BY created_at, id`
+	return r.query(ctx, query, orderListID)
+}
+
+// FindActive returns every order that can still trade, the ones the engine
+// booked first in their order of time priority, then the others by arrival.
+func (r *orderRepository) FindActive(ctx context.Context) ([]entity.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders
+		WHERE status IN ('OPEN', 'TRIGGERED', 'PARTIALLY_FILLED')
+		ORDER BY book_sequence NULLS LAST, created_at, id`
+	return r.query(ctx, query)
+}
+
+// FindActiveByInstrumentID returns the orders of an instrument that can still
+// trade, in the same order as FindActive.
+func (r *orderRepository) FindActiveByInstrumentID(ctx context.Context, instrumentID string) ([]entity.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders
+		WHERE instrument_id = $1 AND status IN ('OPEN', 'TRIGGERED', 'PARTIALLY_FILLED')
+		ORDER BY book_sequence NULLS LAST, created_at, id`
+	return r.query(ctx, query, instrumentID)
+}
+
 func (r *orderRepository) FindByInstrumentID(ctx context.Context, id string) ([]entity.Order, error) {
-	query := `SELECT * FROM orders WHERE instrument_id = $1`
-	rows, err := r.db.Query(ctx, query, id)
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE instrument_id = $1`
+	return r.query(ctx, query, id)
+}
+
+func (r *orderRepository) queryOne(ctx context.Context, query string, args ...any) (entity.Order, error) {
+	o, err := scanOrder(db.Conn(ctx, r.db).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Order{}, ierr.ErrNotFound
+		}
+		return entity.Order{}, err
+	}
+	return o, nil
+}
+
+func (r *orderRepository) query(ctx context.Context, query string, args ...any) ([]entity.Order, error) {
+	rows, err := db.Conn(ctx, r.db).Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -127,25 +242,10 @@ func (r *orderRepository) FindByInstrumentID(ctx context.Context, id string) ([]
 
 	var orders []entity.Order
 	for rows.Next() {
-		var o entity.Order
-		var priceStr, quantityStr, remainingStr string
-		if err := rows.Scan(
-			&o.ID,
-			&o.AccountID,
-			&o.InstrumentID,
-			&o.Type,
-			&o.Status,
-			&priceStr,
-			&quantityStr,
-			&remainingStr,
-			&o.CreatedAt,
-			&o.UpdatedAt,
-		); err != nil {
+		o, err := scanOrder(rows)
+		if err != nil {
 			return nil, err
 		}
-		o.Price, _ = new(big.Float).SetString(priceStr)
-		o.Quantity, _ = new(big.Float).SetString(quantityStr)
-		o.RemainingQuantity, _ = new(big.Float).SetString(remainingStr)
 		orders = append(orders, o)
 	}
 	if err := rows.Err(); err != nil {
@@ -153,3 +253,68 @@ func (r *orderRepository) FindByInstrumentID(ctx context.Context, id string) ([]
 	}
 	return orders, nil
 }
+
+// scanOrder reads a row selected with orderColumns.
+func scanOrder(row pgx.Row) (entity.Order, error) {
+	var o entity.Order
+	var quantityStr, remainingStr string
+	var priceStr, stopPriceStr, trailAmountStr, trailPercentStr, waterMarkStr, quoteStr, remainingQuoteStr, displayStr, visibleStr, clientOrderID, orderListID, amendmentID *string
+	var bookSequence *int64
+	if err := row.Scan(
+		&o.ID,
+		&o.AccountID,
+		&o.InstrumentID,
+		&o.Type,
+		&o.Kind,
+		&o.Status,
+		&priceStr,
+		&stopPriceStr,
+		&trailAmountStr,
+		&trailPercentStr,
+		&waterMarkStr,
+		&quantityStr,
+		&remainingStr,
+		&quoteStr,
+		&remainingQuoteStr,
+		&displayStr,
+		&visibleStr,
+		&o.TimeInForce,
+		&o.ExpiresAt,
+		&o.TriggeredAt,
+		&o.PostOnly,
+		&o.RepriceOnCross,
+		&clientOrderID,
+		&o.SelfTradePrevention,
+		&orderListID,
+		&bookSequence,
+		&amendmentID,
+		&o.CreatedAt,
+		&o.UpdatedAt,
+	); err != nil {
+		return entity.Order{}, err
+	}
+	o.Price = decimal.ParseOptional(priceStr)
+	o.StopPrice = decimal.ParseOptional(stopPriceStr)
+	o.TrailAmount = decimal.ParseOptional(trailAmountStr)
+	o.TrailPercent = decimal.ParseOptional(trailPercentStr)
+	o.WaterMark = decimal.ParseOptional(waterMarkStr)
+	o.Quantity, _ = decimal.Parse(quantityStr)
+	o.RemainingQuantity, _ = decimal.Parse(remainingStr)
+	o.QuoteQuantity = decimal.ParseOptional(quoteStr)
+	o.RemainingQuoteQuantity = decimal.ParseOptional(remainingQuoteStr)
+	o.DisplayQuantity = decimal.ParseOptional(displayStr)
+	o.VisibleQuantity = decimal.ParseOptional(visibleStr)
+	if clientOrderID != nil {
+		o.ClientOrderID = *clientOrderID
+	}
+	if orderListID != nil {
+		o.OrderListID = *orderListID
+	}
+	if bookSequence != nil {
+		o.BookSequence = *bookSequence
+	}
+	if amendmentID != nil {
+		o.AmendmentID = *amendmentID
+	}
+	return o, nil
+}
@@ -3,39 +3,168 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"sync"
 
+	"github.com/google/uuid"
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+// Message types carried in the AMQP Type property of the orders queue.
+// Messages without a type are new orders.
+const (
+	MessageTypeOrder     = "order"
+	MessageTypeAmend     = "amend"
+	MessageTypeCancel    = "cancel"
+	MessageTypeCancelAll = "cancel_all"
+)
+
+// replyQueue is RabbitMQ's direct reply-to pseudo queue, used to wait for the
+// engine's answer to a command without declaring a queue per request.
+const replyQueue = "amq.rabbitmq.reply-to"
+
 type OrderQueueRepository struct {
 	channel *amqp.Channel
 	queue   string
+
+	listenOnce sync.Once
+	listenErr  error
+	mu         sync.Mutex
+	pending    map[string]chan amqp.Delivery
 }
 
 func NewOrderQueueRepository(channel *amqp.Channel, queue string) *OrderQueueRepository {
 	return &OrderQueueRepository{
 		channel: channel,
 		queue:   queue,
+		pending: make(map[string]chan amqp.Delivery),
 	}
 }
 
 // PublishOrder sends an order to the RabbitMQ queue
 func (r *OrderQueueRepository) PublishOrder(ctx context.Context, order entity.Order) error {
-	body, err := json.Marshal(ToModel(order))
+	return r.publish(ctx, amqp.Publishing{Type: MessageTypeOrder}, ToModel(order))
+}
+
+// PublishOrders sends several orders to the RabbitMQ queue back to back,
+// without waiting on anything between them. It returns how many were sent
+// before the first failure.
+func (r *OrderQueueRepository) PublishOrders(ctx context.Context, orders []entity.Order) (int, error) {
+	for i, order := range orders {
+		if err := r.PublishOrder(ctx, order); err != nil {
+			return i, err
+		}
+	}
+	return len(orders), nil
+}
+
+// PublishAmend sends an amendment of a working order to the RabbitMQ queue.
+// It travels on the same queue as new orders so the engine always sees an
+// order before any amendment to it.
+func (r *OrderQueueRepository) PublishAmend(ctx context.Context, amendment entity.Amendment) error {
+	return r.publish(ctx, amqp.Publishing{Type: MessageTypeAmend}, ToAmendModel(amendment))
+}
+
+// PublishCancel sends a cancel command to the engine and waits for its answer
+// until ctx is done.
+func (r *OrderQueueRepository) PublishCancel(ctx context.Context, order entity.Order) (entity.CancelResult, error) {
+	command := &CancelModel{OrderID: order.ID, InstrumentID: order.InstrumentID}
+	var reply CancelResultModel
+	if err := r.request(ctx, MessageTypeCancel, command, &reply); err != nil {
+		return entity.CancelResult{}, err
+	}
+	return reply.ToEntity(), nil
+}
+
+// PublishCancelAll sends a mass cancel command to the engine and waits for
+// the IDs of the orders it cancelled until ctx is done.
+func (r *OrderQueueRepository) PublishCancelAll(ctx context.Context, filter entity.CancelFilter) ([]string, error) {
+	var reply CancelAllResultModel
+	if err := r.request(ctx, MessageTypeCancelAll, ToCancelAllModel(filter), &reply); err != nil {
+		return nil, err
+	}
+	if reply.Error != "" {
+		return nil, fmt.Errorf("engine did not cancel orders: %s", reply.Error)
+	}
+	return reply.CancelledOrderIDs, nil
+}
+
+// request publishes a command and decodes the engine's reply into reply.
+func (r *OrderQueueRepository) request(ctx context.Context, messageType string, command, reply any) error {
+	r.listenOnce.Do(func() { r.listenErr = r.listenReplies() })
+	if r.listenErr != nil {
+		return r.listenErr
+	}
+
+	correlationID := uuid.NewString()
+	replies := make(chan amqp.Delivery, 1)
+	r.mu.Lock()
+	r.pending[correlationID] = replies
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.pending, correlationID)
+		r.mu.Unlock()
+	}()
+
+	publishing := amqp.Publishing{Type: messageType, CorrelationId: correlationID, ReplyTo: replyQueue}
+	if err := r.publish(ctx, publishing, command); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for %s confirmation: %w", messageType, ctx.Err())
+	case delivery := <-replies:
+		if err := json.Unmarshal(delivery.Body, reply); err != nil {
+			return fmt.Errorf("invalid %s reply: %w", messageType, err)
+		}
+		return nil
+	}
+}
+
+// listenReplies consumes the direct reply-to queue and hands every reply to
+// the request waiting for its correlation ID. Replies nobody waits for any
+// more are dropped.
+func (r *OrderQueueRepository) listenReplies() error {
+	deliveries, err := r.channel.Consume(replyQueue, "", true, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for delivery := range deliveries {
+			r.mu.Lock()
+			reply, ok := r.pending[delivery.CorrelationId]
+			r.mu.Unlock()
+			if !ok {
+				continue
+			}
+			select {
+			case reply <- delivery:
+			default:
+				// duplicate reply; the first one already answered the request
+			}
+		}
+	}()
+	return nil
+}
+
+func (r *OrderQueueRepository) publish(ctx context.Context, publishing amqp.Publishing, message any) error {
+	body, err := json.Marshal(message)
 	if err != nil {
 		return err
 	}
 
+	publishing.ContentType = "application/json"
+	publishing.Body = body
 	return r.channel.PublishWithContext(
 		ctx,
 		"",
 		r.queue,
 		false,
 		false,
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-		},
+		publishing,
 	)
 }
@@ -3,46 +3,73 @@ package app
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
 	"math/big"
+	"slices"
+	"time"
 
+	"github.com/google/uuid"
+	accountEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/account/domain/entity"
 	accountPort "github.com/mthpedrosa/financial-exchange-challenge/internal/account/domain/port"
 	balancePort "github.com/mthpedrosa/financial-exchange-challenge/internal/balance/domain/port"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/db"
+	instrumentEntity "github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/domain/entity"
 	instrumentPort "github.com/mthpedrosa/financial-exchange-challenge/internal/instrument/domain/port"
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/dto"
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/port"
+	tradePort "github.com/mthpedrosa/financial-exchange-challenge/internal/trade/domain/port"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/ierr"
 )
 
 type Order interface {
 	Create(ctx context.Context, req dto.CreateOrderRequest) (dto.CreateOrderResponse, error)
+	CreateBatch(ctx context.Context, req dto.BatchCreateOrderRequest) (dto.BatchCreateOrderResponse, error)
+	CreateOrderList(ctx context.Context, req dto.CreateOrderListRequest) (dto.CreateOrderListResponse, error)
+	FindOrderListByID(ctx context.Context, id string) (dto.OrderListDTO, error)
 	FindByID(ctx context.Context, id string) (dto.OrderDTO, error)
+	FindByClientOrderID(ctx context.Context, accountID, clientOrderID string) (dto.OrderDTO, error)
 	GetAll(ctx context.Context) ([]dto.OrderDTO, error)
-	Update(ctx context.Context, req dto.CreateOrderRequest) error
+	Amend(ctx context.Context, id string, req dto.AmendOrderRequest) error
 	CancelByID(ctx context.Context, id string) error
+	CancelAll(ctx context.Context, req dto.CancelAllRequest) (dto.CancelAllResponse, error)
 	FindByInstrument(ctx context.Context, id string) ([]dto.OrderDTO, error)
 }
 
+// cancelTimeout bounds how long a cancel or a mass cancel waits for the engine's confirmation.
+const cancelTimeout = 5 * time.Second
+
 type orderApp struct {
 	orderRepo      port.OrderRepository
+	orderListRepo  port.OrderListRepository
 	accountRepo    accountPort.AccountRepository
 	instrumentRepo instrumentPort.InstrumentRepository
 	balanceRepo    balancePort.BalanceRepository
+	tradeRepo      tradePort.TradeRepository
 	orderQueue     port.OrderQueue
+	txManager      db.TxManager
 }
 
 func NewOrderApp(
 	orderRepo port.OrderRepository,
+	orderListRepo port.OrderListRepository,
 	accountRepo accountPort.AccountRepository,
 	instrumentRepo instrumentPort.InstrumentRepository,
 	balanceRepo balancePort.BalanceRepository,
+	tradeRepo tradePort.TradeRepository,
 	orderQueue port.OrderQueue,
+	txManager db.TxManager,
 ) Order {
 	return &orderApp{
 		orderRepo:      orderRepo,
+		orderListRepo:  orderListRepo,
 		accountRepo:    accountRepo,
 		instrumentRepo: instrumentRepo,
 		balanceRepo:    balanceRepo,
+		tradeRepo:      tradeRepo,
 		orderQueue:     orderQueue,
+		txManager:      txManager,
 	}
 }
 
@@ -52,53 +79,402 @@ func (a *orderApp) Create(ctx context.Context, req dto.CreateOrderRequest) (dto.
 		return dto.CreateOrderResponse{}, err
 	}
 
+	// a retried request gets the order the first attempt created
+	if response, ok, err := a.replay(ctx, *orderEntity); err != nil || ok {
+		return response, err
+	}
+
 	// check if account exists
-	_, err = a.accountRepo.FindByID(ctx, orderEntity.AccountID)
+	account, err := a.accountRepo.FindByID(ctx, orderEntity.AccountID)
 	if err != nil {
 		return dto.CreateOrderResponse{}, errors.New("account not found")
 	}
+	orderEntity.DefaultSelfTradePrevention(account.SelfTradePrevention)
 
 	// check if instrument exists
 	instrument, err := a.instrumentRepo.FindByID(ctx, orderEntity.InstrumentID)
 	if err != nil {
 		return dto.CreateOrderResponse{}, errors.New("instrument not found")
 	}
+	if err := instrument.Status.CheckOrder(orderEntity.IsPostOnlyLimit(), orderEntity.IsRestingLimit()); err != nil {
+		return dto.CreateOrderResponse{}, err
+	}
+	if err := checkTradingRules(*orderEntity, instrument); err != nil {
+		return dto.CreateOrderResponse{}, err
+	}
+	lastPrice, err := a.lastPrice(ctx, instrument)
+	if err != nil {
+		return dto.CreateOrderResponse{}, err
+	}
+	if err := checkPriceBand(*orderEntity, instrument, lastPrice); err != nil {
+		return dto.CreateOrderResponse{}, err
+	}
+
+	// reserve the funds backing the order
+	asset := orderEntity.ReservedAsset(instrument.BaseAsset, instrument.QuoteAsset)
+	requiredAmount := orderEntity.ReservedAmount()
+
+	// persist the order and lock its funds in the same transaction
+	err = a.txManager.WithTx(ctx, func(ctx context.Context) error {
+		id, err := a.orderRepo.Create(ctx, *orderEntity)
+		if err != nil {
+			return err
+		}
+		orderEntity.ID = id
+
+		return a.balanceRepo.Reserve(ctx, orderEntity.AccountID, asset, requiredAmount)
+	})
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return dto.CreateOrderResponse{}, fmt.Errorf("balance not found for required asset: %w", err)
+		}
+		// a concurrent retry with the same key got there first
+		if errors.Is(err, ierr.ErrConflict) {
+			if response, ok, replayErr := a.replay(ctx, *orderEntity); replayErr == nil && ok {
+				return response, nil
+			}
+		}
+		return dto.CreateOrderResponse{}, err
+	}
+
+	// send order to queue for processing
+	if err := a.orderQueue.PublishOrder(ctx, *orderEntity); err != nil {
+		// the engine will never see this order, so give the funds back and
+		// free the idempotency key for a retry to place the order again
+		cancelErr := a.txManager.WithTx(ctx, func(ctx context.Context) error {
+			if err := a.cancel(ctx, *orderEntity, instrument); err != nil {
+				return err
+			}
+			if orderEntity.IdempotencyKey == "" {
+				return nil
+			}
+			return a.orderRepo.ClearIdempotencyKey(ctx, orderEntity.ID)
+		})
+		if cancelErr != nil {
+			slog.Error("error cancelling unpublished order", "order_id", orderEntity.ID, "error", cancelErr)
+		}
+		return dto.CreateOrderResponse{}, err
+	}
+
+	return dto.CreateOrderResponse{ID: orderEntity.ID}, nil
+}
+
+// replay looks up the order created by an earlier request with the same
+// idempotency key. ok is false when the request has no key or nothing was
+// created with it yet; a key that created a different order is a conflict.
+func (a *orderApp) replay(ctx context.Context, order entity.Order) (dto.CreateOrderResponse, bool, error) {
+	if order.IdempotencyKey == "" {
+		return dto.CreateOrderResponse{}, false, nil
+	}
+
+	existing, err := a.orderRepo.FindByIdempotencyKey(ctx, order.AccountID, order.IdempotencyKey)
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return dto.CreateOrderResponse{}, false, nil
+		}
+		return dto.CreateOrderResponse{}, false, err
+	}
+	if !existing.SameRequest(order) {
+		return dto.CreateOrderResponse{}, false, fmt.Errorf("idempotency key already used for a different order: %w", ierr.ErrConflict)
+	}
+	return dto.CreateOrderResponse{ID: existing.ID}, true, nil
+}
+
+// batchOrder is an order of a batch that passed validation, with the balance
+// backing it.
+type batchOrder struct {
+	index      int
+	order      *entity.Order
+	key        entity.ReservationKey
+	instrument *instrumentEntity.Instrument
+}
+
+// CreateBatch places several orders at once and reports the outcome of each
+// one in request order. Accounts and instruments are looked up once per batch
+// and every balance involved is locked once, so orders earlier in the batch
+// claim funds first and no two orders share them. The accepted orders are
+// inserted in a single round trip and published back to back.
+func (a *orderApp) CreateBatch(ctx context.Context, req dto.BatchCreateOrderRequest) (dto.BatchCreateOrderResponse, error) {
+	if err := req.Validate(); err != nil {
+		return dto.BatchCreateOrderResponse{}, fmt.Errorf("%s: %w", err.Error(), ierr.ErrInvalidInput)
+	}
+
+	results := make([]dto.BatchOrderResult, len(req.Orders))
+	accounts := make(map[string]*accountEntity.Account)
+	instruments := make(map[string]*instrumentEntity.Instrument)
+	lastPrices := make(map[string]*big.Float)
+	clientOrderIDs := make(map[[2]string]bool)
+	var candidates []batchOrder
+	var keys []entity.ReservationKey
+	for i, orderReq := range req.Orders {
+		orderEntity, err := entity.ToEntity(orderReq)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		if orderEntity.ClientOrderID != "" {
+			clientKey := [2]string{orderEntity.AccountID, orderEntity.ClientOrderID}
+			if clientOrderIDs[clientKey] {
+				results[i].Error = "duplicate client_order_id in batch"
+				continue
+			}
+			clientOrderIDs[clientKey] = true
+		}
+
+		account, ok := accounts[orderEntity.AccountID]
+		if !ok {
+			if found, err := a.accountRepo.FindByID(ctx, orderEntity.AccountID); err == nil {
+				account = &found
+			}
+			accounts[orderEntity.AccountID] = account
+		}
+		if account == nil {
+			results[i].Error = "account not found"
+			continue
+		}
+		orderEntity.DefaultSelfTradePrevention(account.SelfTradePrevention)
 
-	// check if account has sufficient balance for the order
-	var asset string
-	var requiredAmount *big.Float
+		instrument, ok := instruments[orderEntity.InstrumentID]
+		if !ok {
+			if instrument, err = a.instrumentRepo.FindByID(ctx, orderEntity.InstrumentID); err != nil {
+				instrument = nil
+			}
+			instruments[orderEntity.InstrumentID] = instrument
+		}
+		if instrument == nil {
+			results[i].Error = "instrument not found"
+			continue
+		}
+		if err := instrument.Status.CheckOrder(orderEntity.IsPostOnlyLimit(), orderEntity.IsRestingLimit()); err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		if err := checkTradingRules(*orderEntity, instrument); err != nil {
+			results[i].Error = err.Error()
+			results[i].Rejections = ToRejectionListDTO(err)
+			continue
+		}
+		lastPrice, ok := lastPrices[instrument.ID]
+		if !ok {
+			if lastPrice, err = a.lastPrice(ctx, instrument); err != nil {
+				return dto.BatchCreateOrderResponse{}, err
+			}
+			lastPrices[instrument.ID] = lastPrice
+		}
+		if err := checkPriceBand(*orderEntity, instrument, lastPrice); err != nil {
+			results[i].Error = err.Error()
+			results[i].Rejections = ToRejectionListDTO(err)
+			continue
+		}
 
-	if orderEntity.Type == entity.OrderTypeBuy {
-		asset = instrument.QuoteAsset
-		requiredAmount = new(big.Float).Mul(orderEntity.Price, orderEntity.Quantity)
-	} else {
-		asset = instrument.BaseAsset
-		requiredAmount = orderEntity.Quantity
+		key := entity.ReservationKey{
+			AccountID: orderEntity.AccountID,
+			Asset:     orderEntity.ReservedAsset(instrument.BaseAsset, instrument.QuoteAsset),
+		}
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+		candidates = append(candidates, batchOrder{index: i, order: orderEntity, key: key, instrument: instrument})
 	}
+	entity.SortReservationKeys(keys)
 
-	balance, err := a.balanceRepo.FindByAccountAndAsset(ctx, orderEntity.AccountID, asset)
+	// share the balances among the orders and persist the accepted ones
+	// together with their reservations in the same transaction
+	var accepted []batchOrder
+	var orders []entity.Order
+	err := a.txManager.WithTx(ctx, func(ctx context.Context) error {
+		allocation := entity.NewAllocation()
+		for _, key := range keys {
+			balance, err := a.balanceRepo.FindForUpdate(ctx, key.AccountID, key.Asset)
+			if errors.Is(err, ierr.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			allocation.Fund(key, balance.Available())
+		}
+
+		for _, candidate := range candidates {
+			if err := allocation.Claim(candidate.key, candidate.order.ReservedAmount()); err != nil {
+				results[candidate.index].Error = err.Error()
+				continue
+			}
+			accepted = append(accepted, candidate)
+		}
+		if len(accepted) == 0 {
+			return nil
+		}
+
+		orders = make([]entity.Order, len(accepted))
+		for i, candidate := range accepted {
+			orders[i] = *candidate.order
+		}
+		ids, err := a.orderRepo.CreateBatch(ctx, orders)
+		if err != nil {
+			return err
+		}
+		for i, id := range ids {
+			orders[i].ID = id
+		}
+
+		for key, amount := range allocation.Reserved() {
+			if err := a.balanceRepo.Reserve(ctx, key.AccountID, key.Asset, amount); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		return dto.CreateOrderResponse{}, errors.New("balance not found for required asset")
+		return dto.BatchCreateOrderResponse{}, err
 	}
 
-	if balance.Amount.Cmp(requiredAmount) < 0 {
-		return dto.CreateOrderResponse{}, errors.New("insufficient balance")
+	published, err := a.orderQueue.PublishOrders(ctx, orders)
+	if err != nil {
+		slog.Error("error publishing order batch", "published", published, "total", len(orders), "error", err)
 	}
+	for i, candidate := range accepted {
+		if i < published {
+			results[candidate.index].ID = orders[i].ID
+			continue
+		}
+		// the engine will never see this order, so give the funds back
+		if cancelErr := a.cancel(ctx, orders[i], candidate.instrument); cancelErr != nil {
+			slog.Error("error cancelling unpublished order", "order_id", orders[i].ID, "error", cancelErr)
+		}
+		results[candidate.index].Error = "order could not be queued"
+	}
+
+	return dto.BatchCreateOrderResponse{Results: results}, nil
+}
 
-	id, err := a.orderRepo.Create(ctx, *orderEntity)
+// CreateOrderList places an OCO order list. Both orders are checked like any
+// other order and inserted with the list, which reserves once what the more
+// demanding of them needs, in a single transaction. They are then published
+// one after the other; the engine cancels either one as soon as the other
+// trades or is triggered.
+func (a *orderApp) CreateOrderList(ctx context.Context, req dto.CreateOrderListRequest) (dto.CreateOrderListResponse, error) {
+	list, err := entity.ToOrderList(req)
 	if err != nil {
-		return dto.CreateOrderResponse{}, err
+		return dto.CreateOrderListResponse{}, fmt.Errorf("%s: %w", err.Error(), ierr.ErrInvalidInput)
+	}
+
+	account, err := a.accountRepo.FindByID(ctx, list.AccountID)
+	if err != nil {
+		return dto.CreateOrderListResponse{}, errors.New("account not found")
+	}
+	instrument, err := a.instrumentRepo.FindByID(ctx, list.InstrumentID)
+	if err != nil {
+		return dto.CreateOrderListResponse{}, errors.New("instrument not found")
+	}
+	lastPrice, err := a.lastPrice(ctx, instrument)
+	if err != nil {
+		return dto.CreateOrderListResponse{}, err
+	}
+	for i := range list.Orders {
+		order := &list.Orders[i]
+		order.DefaultSelfTradePrevention(account.SelfTradePrevention)
+		if err := instrument.Status.CheckOrder(order.IsPostOnlyLimit(), order.IsRestingLimit()); err != nil {
+			return dto.CreateOrderListResponse{}, err
+		}
+		if err := checkTradingRules(*order, instrument); err != nil {
+			return dto.CreateOrderListResponse{}, err
+		}
+		if err := checkPriceBand(*order, instrument, lastPrice); err != nil {
+			return dto.CreateOrderListResponse{}, err
+		}
 	}
 
-	orderEntity.ID = id
+	// persist the list and its orders and lock their funds in the same transaction
+	asset := list.Orders[0].ReservedAsset(instrument.BaseAsset, instrument.QuoteAsset)
+	err = a.txManager.WithTx(ctx, func(ctx context.Context) error {
+		id, err := a.orderListRepo.Create(ctx, *list)
+		if err != nil {
+			return err
+		}
+		list.ID = id
+		for i := range list.Orders {
+			list.Orders[i].OrderListID = id
+		}
 
-	// send order to queue for processing
-	if err := a.orderQueue.PublishOrder(ctx, *orderEntity); err != nil {
-		return dto.CreateOrderResponse{}, err
+		ids, err := a.orderRepo.CreateBatch(ctx, list.Orders)
+		if err != nil {
+			return err
+		}
+		for i, id := range ids {
+			list.Orders[i].ID = id
+		}
+
+		return a.balanceRepo.Reserve(ctx, list.AccountID, asset, list.Reserved)
+	})
+	if err != nil {
+		if errors.Is(err, ierr.ErrNotFound) {
+			return dto.CreateOrderListResponse{}, fmt.Errorf("balance not found for required asset: %w", err)
+		}
+		return dto.CreateOrderListResponse{}, err
 	}
 
-	orderEntity.ID = id
-	return dto.CreateOrderResponse{ID: id}, nil
+	published, err := a.orderQueue.PublishOrders(ctx, list.Orders)
+	if err != nil {
+		// the engine will never see some of the orders, so give their funds back
+		if cancelErr := a.abandonList(ctx, *list, published, instrument); cancelErr != nil {
+			slog.Error("error cancelling unpublished order list", "order_list_id", list.ID, "error", cancelErr)
+		}
+		return dto.CreateOrderListResponse{}, err
+	}
+
+	return dto.CreateOrderListResponse{ID: list.ID, OrderIDs: list.OrderIDs()}, nil
+}
+
+// abandonList cancels the orders of a list from index published on, which the
+// engine has never seen, and releases what they held of the list's
+// reservation. When nothing was published the whole reservation goes back.
+// Otherwise the engine may already have finished the list on its own, and
+// then there is nothing left to cancel.
+func (a *orderApp) abandonList(ctx context.Context, list entity.OrderList, published int, instrument *instrumentEntity.Instrument) error {
+	asset := list.Orders[0].ReservedAsset(instrument.BaseAsset, instrument.QuoteAsset)
+	return a.txManager.WithTx(ctx, func(ctx context.Context) error {
+		stored, err := a.orderListRepo.FindForUpdate(ctx, list.ID)
+		if err != nil {
+			return err
+		}
+		if !stored.IsExecuting() {
+			return nil
+		}
+
+		released := list.Reserved
+		if published > 0 {
+			// the published orders keep what they share with the rest
+			released = new(big.Float)
+			for _, order := range list.Orders[published:] {
+				released.Add(released, stored.Unshared(order.ReservedAmount()))
+			}
+		}
+		for _, order := range list.Orders[published:] {
+			if err := order.Cancel(); err != nil {
+				return err
+			}
+			if err := a.orderRepo.Update(ctx, order); err != nil {
+				return err
+			}
+		}
+		if released.Sign() > 0 {
+			if err := a.balanceRepo.Release(ctx, list.AccountID, asset, released); err != nil {
+				return err
+			}
+		}
+		return a.orderListRepo.UpdateStatus(ctx, list.ID, entity.OrderListStatusAllDone)
+	})
+}
+
+// FindOrderListByID finds an order list, with its orders, by its ID.
+func (a *orderApp) FindOrderListByID(ctx context.Context, id string) (dto.OrderListDTO, error) {
+	list, err := a.orderListRepo.FindByID(ctx, id)
+	if err != nil {
+		return dto.OrderListDTO{}, err
+	}
+	return list.ToDTO(), nil
 }
 
 // FindByID finds an order by its ID.
@@ -110,6 +486,15 @@ func (a *orderApp) FindByID(ctx context.Context, id string) (dto.OrderDTO, error
 	return order.ToDTO(), nil
 }
 
+// FindByClientOrderID finds an order by the ID its account assigned to it.
+func (a *orderApp) FindByClientOrderID(ctx context.Context, accountID, clientOrderID string) (dto.OrderDTO, error) {
+	order, err := a.orderRepo.FindByClientOrderID(ctx, accountID, clientOrderID)
+	if err != nil {
+		return dto.OrderDTO{}, err
+	}
+	return order.ToDTO(), nil
+}
+
 func (a *orderApp) FindByInstrument(ctx context.Context, id string) ([]dto.OrderDTO, error) {
 	orders, err := a.orderRepo.FindByInstrumentID(ctx, id)
 	if err != nil {
@@ -127,21 +512,188 @@ func (a *orderApp) GetAll(ctx context.Context) ([]dto.OrderDTO, error) {
 	return entity.ToListDTO(orders), nil
 }
 
-// Update updates an order.
-func (a *orderApp) Update(ctx context.Context, req dto.CreateOrderRequest) error {
-	orderEntity, err := entity.ToEntity(req)
+// Amend validates an amendment of a working order against its current state,
+// reserves any extra funds it needs and hands it to the engine, which applies
+// it and releases whatever the amended order no longer needs.
+func (a *orderApp) Amend(ctx context.Context, id string, req dto.AmendOrderRequest) error {
+	amendment, err := entity.ToAmendment(id, req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", err.Error(), ierr.ErrInvalidInput)
+	}
+
+	order, err := a.orderRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !order.IsActive() {
+		return fmt.Errorf("order is %s: %w", order.Status, ierr.ErrConflict)
+	}
+
+	amended, err := order.Amended(*amendment)
 	if err != nil {
+		return fmt.Errorf("%s: %w", err.Error(), ierr.ErrInvalidInput)
+	}
+	amendment.ID = uuid.NewString()
+	amendment.InstrumentID = order.InstrumentID
+
+	instrument, err := a.instrumentRepo.FindByID(ctx, order.InstrumentID)
+	if err != nil {
+		return err
+	}
+	if !instrument.Status.AcceptsOrders() {
+		return fmt.Errorf("instrument is %s and only accepts cancels: %w", instrument.Status, ierr.ErrConflict)
+	}
+	if err := checkTradingRules(amended, instrument); err != nil {
+		return err
+	}
+	lastPrice, err := a.lastPrice(ctx, instrument)
+	if err != nil {
+		return err
+	}
+	if err := checkPriceBand(amended, instrument, lastPrice); err != nil {
+		return err
+	}
+	asset := order.ReservedAsset(instrument.BaseAsset, instrument.QuoteAsset)
+
+	// reserve up front what the amended order needs on top of the original
+	increase := new(big.Float).Sub(amended.ReservedAmount(), order.ReservedAmount())
+	if increase.Sign() > 0 {
+		if err := a.balanceRepo.Reserve(ctx, order.AccountID, asset, increase); err != nil {
+			return err
+		}
+		amendment.Reserved = increase
+	}
+
+	if err := a.orderQueue.PublishAmend(ctx, *amendment); err != nil {
+		if amendment.Reserved.Sign() > 0 {
+			if releaseErr := a.balanceRepo.Release(ctx, order.AccountID, asset, amendment.Reserved); releaseErr != nil {
+				slog.Error("error releasing unpublished amendment", "order_id", id, "error", releaseErr)
+			}
+		}
 		return err
 	}
-	return a.orderRepo.Update(ctx, *orderEntity)
+	return nil
 }
 
-// DeleteByID deletes an order by its ID.
+// CancelByID asks the engine to cancel an order and waits for its answer. The
+// order only counts as cancelled, and its reservation as released, once the
+// engine confirms it; a cancel that lost the race against a fill is reported
+// as a conflict.
 func (a *orderApp) CancelByID(ctx context.Context, id string) error {
 	order, err := a.orderRepo.FindByID(ctx, id)
 	if err != nil {
 		return err
 	}
-	order.Status = entity.OrderStatusCancelled
-	return a.orderRepo.Update(ctx, order)
+	if order.IsTerminal() {
+		return fmt.Errorf("order is %s: %w", order.Status, ierr.ErrConflict)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cancelTimeout)
+	defer cancel()
+
+	result, err := a.orderQueue.PublishCancel(ctx, order)
+	if err != nil {
+		return err
+	}
+	if !result.Cancelled {
+		if result.Status == entity.OrderStatusFilled || result.Status == entity.OrderStatusCancelled {
+			return fmt.Errorf("order is %s: %w", result.Status, ierr.ErrConflict)
+		}
+		return fmt.Errorf("engine did not cancel order: %s", result.Reason)
+	}
+	return nil
+}
+
+// CancelAll asks the engine to cancel every active order matching the filter
+// in a single operation and returns the IDs it cancelled.
+func (a *orderApp) CancelAll(ctx context.Context, req dto.CancelAllRequest) (dto.CancelAllResponse, error) {
+	filter, err := entity.ToCancelFilter(req)
+	if err != nil {
+		return dto.CancelAllResponse{}, fmt.Errorf("%s: %w", err.Error(), ierr.ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cancelTimeout)
+	defer cancel()
+
+	ids, err := a.orderQueue.PublishCancelAll(ctx, filter)
+	if err != nil {
+		return dto.CancelAllResponse{}, err
+	}
+	if ids == nil {
+		ids = []string{}
+	}
+	return dto.CancelAllResponse{CancelledOrderIDs: ids}, nil
+}
+
+// cancel marks an order the engine has never seen as cancelled and releases
+// the reservation backing it in a single transaction.
+func (a *orderApp) cancel(ctx context.Context, order entity.Order, instrument *instrumentEntity.Instrument) error {
+	return a.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := order.Cancel(); err != nil {
+			return err
+		}
+		if err := a.orderRepo.Update(ctx, order); err != nil {
+			return err
+		}
+
+		reserved := order.ReservedAmount()
+		if reserved.Sign() <= 0 {
+			return nil
+		}
+		asset := order.ReservedAsset(instrument.BaseAsset, instrument.QuoteAsset)
+		return a.balanceRepo.Release(ctx, order.AccountID, asset, reserved)
+	})
+}
+
+// checkTradingRules checks the order against the trading rules of its
+// instrument. Quote-sized orders are only held to the minimum notional, as
+// their quantity is not known until they fill.
+func checkTradingRules(order entity.Order, instrument *instrumentEntity.Instrument) error {
+	terms := instrumentEntity.OrderTerms{
+		Price:           order.Price,
+		StopPrice:       order.StopPrice,
+		DisplayQuantity: order.DisplayQuantity,
+		QuoteQuantity:   order.QuoteQuantity,
+	}
+	if !order.IsQuoteSized() {
+		terms.Quantity = order.Quantity
+	}
+	return instrument.TradingRules.Check(terms)
+}
+
+// checkPriceBand rejects limit orders priced outside the instrument's price
+// band around lastPrice. Stop prices and the limit of stop-limit orders are
+// not held to it, as they are meant to be away from the market.
+func checkPriceBand(order entity.Order, instrument *instrumentEntity.Instrument, lastPrice *big.Float) error {
+	if order.Kind != entity.OrderKindLimit {
+		return nil
+	}
+	return instrument.CheckPriceBand(order.Price, lastPrice)
+}
+
+// lastPrice returns the price of the instrument's last trade, or nil when it
+// has not traded yet or has no price band to check against it.
+func (a *orderApp) lastPrice(ctx context.Context, instrument *instrumentEntity.Instrument) (*big.Float, error) {
+	if instrument.PriceBandPercent == nil {
+		return nil, nil
+	}
+	price, err := a.tradeRepo.LastPrice(ctx, instrument.BaseAsset, instrument.QuoteAsset)
+	if errors.Is(err, ierr.ErrNotFound) {
+		return nil, nil
+	}
+	return price, err
+}
+
+// ToRejectionListDTO lists the trading rules err reports as broken, or nil
+// when err is not a trading rule violation.
+func ToRejectionListDTO(err error) []dto.RuleRejection {
+	var violation *instrumentEntity.RuleViolationError
+	if !errors.As(err, &violation) {
+		return nil
+	}
+	rejections := make([]dto.RuleRejection, len(violation.Rejections))
+	for i, r := range violation.Rejections {
+		rejections[i] = dto.RuleRejection{Code: r.Code, Field: r.Field, Message: r.Message}
+	}
+	return rejections
 }
@@ -2,6 +2,8 @@ package dto
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
 	"time"
 
@@ -12,34 +14,373 @@ type BigFloat struct {
 	*big.Float
 }
 
+// CreateOrderRequest places a LIMIT (default) or MARKET order. Limit orders
+// need price and quantity. Market sells need quantity and market buys need a
+// quote_quantity budget (e.g. spend 100 USDT) instead.
+//
+// TimeInForce defaults to GTC for limit orders and IOC for market orders,
+// which only accept IOC and FOK. GTD orders need an ExpiresAt in the future.
+//
+// STOP_MARKET and STOP_LIMIT orders also need a StopPrice. They wait in the
+// trigger book until the last trade price reaches it (at or above for buys, at
+// or below for sells) and then behave as MARKET and LIMIT orders respectively.
+//
+// TRAILING_STOP_MARKET orders have no StopPrice but a TrailAmount or a
+// TrailPercent instead: their trigger price follows the best trade price since
+// they were placed, the trail below it for sells and above it for buys, and
+// they become MARKET orders once the price reverses to it.
+//
+// DisplayQuantity turns a limit order into an iceberg: only a slice of that
+// size is shown in the book and it is refilled from the hidden remainder each
+// time it is filled.
+//
+// PostOnly limit orders never take liquidity: one that would cross the book on
+// arrival is cancelled, or re-priced one tick behind the best opposite price
+// when RepriceOnCross is set.
+//
+// ClientOrderID is an optional reference chosen by the client, unique per
+// account. IdempotencyKey comes from the Idempotency-Key header: a retried
+// request with the same key returns the order the first one created.
+//
+// SelfTradePrevention picks what happens when the order would trade against
+// one of the same account; the account's default applies when it is empty.
 type CreateOrderRequest struct {
-	AccountID    string    `json:"account_id" validate:"required"`
-	InstrumentID string    `json:"instrument_id" validate:"required"`
-	Type         string    `json:"type" validate:"required,oneof=BUY SELL"`
-	Price        *BigFloat `json:"price" validate:"required"`
-	Quantity     *BigFloat `json:"quantity" validate:"required"`
+	AccountID           string     `json:"account_id" validate:"required"`
+	InstrumentID        string     `json:"instrument_id" validate:"required"`
+	Type                string     `json:"type" validate:"required,oneof=BUY SELL"`
+	Kind                string     `json:"kind,omitempty" validate:"omitempty,oneof=LIMIT MARKET STOP_MARKET STOP_LIMIT TRAILING_STOP_MARKET"`
+	Price               *BigFloat  `json:"price,omitempty"`
+	StopPrice           *BigFloat  `json:"stop_price,omitempty"`
+	TrailAmount         *BigFloat  `json:"trail_amount,omitempty"`
+	TrailPercent        *BigFloat  `json:"trail_percent,omitempty"`
+	Quantity            *BigFloat  `json:"quantity,omitempty"`
+	QuoteQuantity       *BigFloat  `json:"quote_quantity,omitempty"`
+	DisplayQuantity     *BigFloat  `json:"display_quantity,omitempty"`
+	TimeInForce         string     `json:"time_in_force,omitempty" validate:"omitempty,oneof=GTC IOC FOK GTD"`
+	ExpiresAt           *time.Time `json:"expires_at,omitempty"`
+	PostOnly            bool       `json:"post_only,omitempty"`
+	RepriceOnCross      bool       `json:"reprice_on_cross,omitempty"`
+	ClientOrderID       string     `json:"client_order_id,omitempty" validate:"omitempty,max=64"`
+	IdempotencyKey      string     `json:"-" validate:"omitempty,max=255"`
+	SelfTradePrevention string     `json:"self_trade_prevention,omitempty" validate:"omitempty,oneof=NONE CANCEL_NEWEST CANCEL_OLDEST CANCEL_BOTH DECREMENT_AND_CANCEL"`
+}
+
+// AmendOrderRequest changes the price and/or the total quantity of a working
+// order. Quantity is the new total quantity and must stay above what has
+// already been filled. Lowering the quantity keeps time priority; changing the
+// price or raising the quantity loses it.
+type AmendOrderRequest struct {
+	Price    *BigFloat `json:"price,omitempty"`
+	Quantity *BigFloat `json:"quantity,omitempty"`
+}
+
+// CancelAllRequest selects the active orders to cancel at once. At least one
+// of AccountID and InstrumentID is required; Side narrows it to BUY or SELL.
+type CancelAllRequest struct {
+	AccountID    string `json:"account_id,omitempty"`
+	InstrumentID string `json:"instrument_id,omitempty"`
+	Side         string `json:"side,omitempty" validate:"omitempty,oneof=BUY SELL"`
+}
+
+type CancelAllResponse struct {
+	CancelledOrderIDs []string `json:"cancelled_order_ids"`
 }
 
 type CreateOrderResponse struct {
 	ID string `json:"id"`
 }
 
+// MaxBatchOrders is the largest number of orders accepted in a single batch.
+const MaxBatchOrders = 50
+
+// BatchCreateOrderRequest places up to MaxBatchOrders orders at once. Each
+// order is validated on its own and a bad order does not reject the others.
+type BatchCreateOrderRequest struct {
+	Orders []CreateOrderRequest `json:"orders"`
+}
+
+// BatchCreateOrderResponse holds one result per order, in request order.
+type BatchCreateOrderResponse struct {
+	Results []BatchOrderResult `json:"results"`
+}
+
+// BatchOrderResult carries either the ID of the created order or the reason
+// it was rejected.
+type BatchOrderResult struct {
+	ID         string          `json:"id,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Rejections []RuleRejection `json:"rejections,omitempty"`
+}
+
+// RuleRejection is a trading rule of the instrument that an order breaks.
+type RuleRejection struct {
+	Code    string `json:"code"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// CreateOrderListRequest places a one-cancels-other (OCO) order list: a LIMIT
+// order and a STOP_MARKET or STOP_LIMIT order of the same account, instrument
+// and side, typically a take-profit and a stop-loss. As soon as either order
+// trades, or the stop order is triggered, the other one is cancelled.
+type CreateOrderListRequest struct {
+	Orders []CreateOrderRequest `json:"orders"`
+}
+
+type CreateOrderListResponse struct {
+	ID       string   `json:"id"`
+	OrderIDs []string `json:"order_ids"`
+}
+
+// OrderListDTO is an order list with its orders. Reserved is the single
+// reservation backing all of them.
+type OrderListDTO struct {
+	ID              string     `json:"id"`
+	AccountID       string     `json:"account_id"`
+	InstrumentID    string     `json:"instrument_id"`
+	ContingencyType string     `json:"contingency_type"`
+	Status          string     `json:"status"`
+	Reserved        big.Float  `json:"reserved"`
+	Orders          []OrderDTO `json:"orders"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// RejectedOrderResponse is returned when an order breaks the trading rules of
+// its instrument.
+type RejectedOrderResponse struct {
+	Message    string          `json:"message"`
+	Rejections []RuleRejection `json:"rejections"`
+}
+
 type OrderDTO struct {
-	ID                string    `json:"id"`
-	AccountID         string    `json:"account_id"`
-	InstrumentID      string    `json:"instrument_id"`
-	Type              string    `json:"type"`
-	Status            string    `json:"status"`
-	Price             big.Float `json:"price"`
-	Quantity          big.Float `json:"quantity"`
-	RemainingQuantity big.Float `json:"remaining_quantity"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	ID                     string     `json:"id"`
+	AccountID              string     `json:"account_id"`
+	InstrumentID           string     `json:"instrument_id"`
+	Type                   string     `json:"type"`
+	Kind                   string     `json:"kind"`
+	Status                 string     `json:"status"`
+	Price                  big.Float  `json:"price"`
+	StopPrice              *big.Float `json:"stop_price,omitempty"`
+	TrailAmount            *big.Float `json:"trail_amount,omitempty"`
+	TrailPercent           *big.Float `json:"trail_percent,omitempty"`
+	CurrentTriggerPrice    *big.Float `json:"current_trigger_price,omitempty"`
+	Quantity               big.Float  `json:"quantity"`
+	RemainingQuantity      big.Float  `json:"remaining_quantity"`
+	QuoteQuantity          *big.Float `json:"quote_quantity,omitempty"`
+	RemainingQuoteQuantity *big.Float `json:"remaining_quote_quantity,omitempty"`
+	DisplayQuantity        *big.Float `json:"display_quantity,omitempty"`
+	TimeInForce            string     `json:"time_in_force"`
+	ExpiresAt              *time.Time `json:"expires_at,omitempty"`
+	TriggeredAt            *time.Time `json:"triggered_at,omitempty"`
+	PostOnly               bool       `json:"post_only"`
+	RepriceOnCross         bool       `json:"reprice_on_cross,omitempty"`
+	ClientOrderID          string     `json:"client_order_id,omitempty"`
+	SelfTradePrevention    string     `json:"self_trade_prevention"`
+	OrderListID            string     `json:"order_list_id,omitempty"`
+	CreatedAt              time.Time  `json:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at"`
 }
 
 func (r *CreateOrderRequest) Validate() error {
 	validate := validator.New()
-	return validate.Struct(r)
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+	if err := r.validatePrice(); err != nil {
+		return err
+	}
+	if err := r.validateTrail(); err != nil {
+		return err
+	}
+	if err := r.validateSize(); err != nil {
+		return err
+	}
+	if err := r.validateTimeInForce(); err != nil {
+		return err
+	}
+	if err := r.validatePostOnly(); err != nil {
+		return err
+	}
+	return r.validateDisplayQuantity()
+}
+
+// isMarket reports whether the order executes at any price once active.
+func (r *CreateOrderRequest) isMarket() bool {
+	return r.Kind == "MARKET" || r.Kind == "STOP_MARKET" || r.Kind == "TRAILING_STOP_MARKET"
+}
+
+// validatePrice checks that a positive price is given for, and only for,
+// limit-priced orders and stop_price for, and only for, stop orders.
+func (r *CreateOrderRequest) validatePrice() error {
+	if r.isMarket() {
+		if r.Price != nil {
+			return errors.New("market orders cannot have a price")
+		}
+	} else if r.Price == nil || r.Price.Float == nil {
+		return errors.New("price is required")
+	} else if r.Price.Sign() <= 0 {
+		return errors.New("price must be positive")
+	}
+
+	isStop := r.Kind == "STOP_MARKET" || r.Kind == "STOP_LIMIT"
+	if !isStop {
+		if r.StopPrice != nil {
+			return errors.New("stop_price is only allowed on stop orders")
+		}
+		return nil
+	}
+	if r.StopPrice == nil || r.StopPrice.Float == nil || r.StopPrice.Sign() <= 0 {
+		return errors.New("stop orders require a positive stop_price")
+	}
+	return nil
+}
+
+// validateTrail checks that trailing stop orders, and only they, trail by
+// exactly one of a positive trail_amount and a trail_percent below 100.
+func (r *CreateOrderRequest) validateTrail() error {
+	if r.Kind != "TRAILING_STOP_MARKET" {
+		if r.TrailAmount != nil || r.TrailPercent != nil {
+			return errors.New("trail_amount and trail_percent are only allowed on trailing stop orders")
+		}
+		return nil
+	}
+	if (r.TrailAmount == nil) == (r.TrailPercent == nil) {
+		return errors.New("trailing stop orders require either trail_amount or trail_percent")
+	}
+	if r.TrailAmount != nil && (r.TrailAmount.Float == nil || r.TrailAmount.Sign() <= 0) {
+		return errors.New("trail_amount must be positive")
+	}
+	if r.TrailPercent != nil && (r.TrailPercent.Float == nil || r.TrailPercent.Sign() <= 0 || r.TrailPercent.Cmp(big.NewFloat(100)) >= 0) {
+		return errors.New("trail_percent must be between 0 and 100")
+	}
+	return nil
+}
+
+// validateSize checks that exactly one of quantity and quote_quantity sizes
+// the order, and that it is positive: quote_quantity for market buys,
+// quantity for everything else.
+func (r *CreateOrderRequest) validateSize() error {
+	if r.isMarket() && r.Type == "BUY" {
+		if r.QuoteQuantity == nil || r.QuoteQuantity.Float == nil || r.QuoteQuantity.Sign() <= 0 {
+			return errors.New("market buy orders require a positive quote_quantity")
+		}
+		if r.Quantity != nil {
+			return errors.New("market buy orders are sized by quote_quantity, not quantity")
+		}
+		return nil
+	}
+
+	if r.Quantity == nil || r.Quantity.Float == nil {
+		return errors.New("quantity is required")
+	}
+	if r.Quantity.Sign() <= 0 {
+		return errors.New("quantity must be positive")
+	}
+	if r.QuoteQuantity != nil {
+		return errors.New("quote_quantity is only allowed on market buy orders")
+	}
+	return nil
+}
+
+// validateTimeInForce checks that market orders never rest and that
+// expires_at is given for, and only for, GTD orders.
+func (r *CreateOrderRequest) validateTimeInForce() error {
+	if r.isMarket() && (r.TimeInForce == "GTC" || r.TimeInForce == "GTD") {
+		return errors.New("market orders only support IOC or FOK time_in_force")
+	}
+	if r.TimeInForce != "GTD" {
+		if r.ExpiresAt != nil {
+			return errors.New("expires_at is only allowed on GTD orders")
+		}
+		return nil
+	}
+	if r.ExpiresAt == nil {
+		return errors.New("GTD orders require expires_at")
+	}
+	if !r.ExpiresAt.After(time.Now()) {
+		return errors.New("expires_at must be in the future")
+	}
+	return nil
+}
+
+// validatePostOnly checks that post_only is only used on orders that can rest
+// and that reprice_on_cross is only used together with it.
+func (r *CreateOrderRequest) validatePostOnly() error {
+	if !r.PostOnly {
+		if r.RepriceOnCross {
+			return errors.New("reprice_on_cross requires post_only")
+		}
+		return nil
+	}
+	if r.isMarket() {
+		return errors.New("market orders cannot be post_only")
+	}
+	if r.TimeInForce == "IOC" || r.TimeInForce == "FOK" {
+		return errors.New("post_only orders must be GTC or GTD")
+	}
+	return nil
+}
+
+// validateDisplayQuantity checks that iceberg orders can rest and show less
+// than their full quantity.
+func (r *CreateOrderRequest) validateDisplayQuantity() error {
+	if r.DisplayQuantity == nil {
+		return nil
+	}
+	if r.isMarket() {
+		return errors.New("market orders cannot have a display_quantity")
+	}
+	if r.TimeInForce == "IOC" || r.TimeInForce == "FOK" {
+		return errors.New("iceberg orders must be GTC or GTD")
+	}
+	if r.DisplayQuantity.Float == nil || r.DisplayQuantity.Sign() <= 0 {
+		return errors.New("display_quantity must be positive")
+	}
+	if r.DisplayQuantity.Cmp(r.Quantity.Float) >= 0 {
+		return errors.New("display_quantity must be less than quantity")
+	}
+	return nil
+}
+
+func (r *AmendOrderRequest) Validate() error {
+	if r.Price == nil && r.Quantity == nil {
+		return errors.New("price or quantity is required")
+	}
+	if r.Price != nil && (r.Price.Float == nil || r.Price.Sign() <= 0) {
+		return errors.New("price must be positive")
+	}
+	if r.Quantity != nil && (r.Quantity.Float == nil || r.Quantity.Sign() <= 0) {
+		return errors.New("quantity must be positive")
+	}
+	return nil
+}
+
+func (r *BatchCreateOrderRequest) Validate() error {
+	if len(r.Orders) == 0 {
+		return errors.New("orders is required")
+	}
+	if len(r.Orders) > MaxBatchOrders {
+		return fmt.Errorf("a batch accepts at most %d orders", MaxBatchOrders)
+	}
+	return nil
+}
+
+func (r *CreateOrderListRequest) Validate() error {
+	if len(r.Orders) != 2 {
+		return errors.New("an OCO order list takes exactly two orders")
+	}
+	return nil
+}
+
+func (r *CancelAllRequest) Validate() error {
+	if err := validator.New().Struct(r); err != nil {
+		return err
+	}
+	if r.AccountID == "" && r.InstrumentID == "" {
+		return errors.New("account_id or instrument_id is required")
+	}
+	return nil
 }
 
 func (b *BigFloat) UnmarshalJSON(data []byte) error {
@@ -2,7 +2,9 @@ package dto_test
 
 import (
 	"math/big"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/dto"
 	"github.com/stretchr/testify/assert"
@@ -98,6 +100,45 @@ func TestCreateOrderRequest_Validate_MissingQuantity(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestCreateOrderRequest_Validate_NonPositive(t *testing.T) {
+	limit := func(price, quantity string) dto.CreateOrderRequest {
+		return dto.CreateOrderRequest{
+			AccountID:    "acc-123",
+			InstrumentID: "inst-456",
+			Type:         "BUY",
+			Price:        newBigFloat(price),
+			Quantity:     newBigFloat(quantity),
+		}
+	}
+	marketSell := dto.CreateOrderRequest{
+		AccountID:    "acc-123",
+		InstrumentID: "inst-456",
+		Type:         "SELL",
+		Kind:         "MARKET",
+		TimeInForce:  "IOC",
+		Quantity:     newBigFloat("-1"),
+	}
+
+	testCases := []struct {
+		name    string
+		request dto.CreateOrderRequest
+		message string
+	}{
+		{name: "negative price", request: limit("-150", "10"), message: "price must be positive"},
+		{name: "zero price", request: limit("0", "10"), message: "price must be positive"},
+		{name: "negative quantity", request: limit("150", "-10"), message: "quantity must be positive"},
+		{name: "zero quantity", request: limit("150", "0"), message: "quantity must be positive"},
+		{name: "negative market sell quantity", request: marketSell, message: "quantity must be positive"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.request.Validate()
+			assert.EqualError(t, err, tc.message)
+		})
+	}
+}
+
 func TestBigFloat_UnmarshalJSON(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -156,3 +197,430 @@ func TestBigFloat_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateOrderRequest_Validate_Market(t *testing.T) {
+	testCases := []struct {
+		name        string
+		request     dto.CreateOrderRequest
+		expectError bool
+	}{
+		{
+			name:    "market sell with quantity",
+			request: dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "SELL", Kind: "MARKET", Quantity: newBigFloat("1")},
+		},
+		{
+			name:    "market buy with quote quantity",
+			request: dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "BUY", Kind: "MARKET", QuoteQuantity: newBigFloat("100")},
+		},
+		{
+			name:        "market order with price",
+			request:     dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "SELL", Kind: "MARKET", Price: newBigFloat("10"), Quantity: newBigFloat("1")},
+			expectError: true,
+		},
+		{
+			name:        "market buy without quote quantity",
+			request:     dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "BUY", Kind: "MARKET", Quantity: newBigFloat("1")},
+			expectError: true,
+		},
+		{
+			name:        "limit order with quote quantity",
+			request:     dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "BUY", Price: newBigFloat("10"), Quantity: newBigFloat("1"), QuoteQuantity: newBigFloat("10")},
+			expectError: true,
+		},
+		{
+			name:        "unknown kind",
+			request:     dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "BUY", Kind: "STOP", Price: newBigFloat("10"), Quantity: newBigFloat("1")},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.request.Validate()
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCreateOrderRequest_Validate_TimeInForce(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	limit := func(tif string, expiresAt *time.Time) dto.CreateOrderRequest {
+		return dto.CreateOrderRequest{
+			AccountID:    "acc-123",
+			InstrumentID: "inst-456",
+			Type:         "BUY",
+			Price:        newBigFloat("10"),
+			Quantity:     newBigFloat("1"),
+			TimeInForce:  tif,
+			ExpiresAt:    expiresAt,
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		request     dto.CreateOrderRequest
+		expectError bool
+	}{
+		{name: "IOC limit order", request: limit("IOC", nil)},
+		{name: "FOK limit order", request: limit("FOK", nil)},
+		{name: "GTD with future expires_at", request: limit("GTD", &future)},
+		{name: "GTD without expires_at", request: limit("GTD", nil), expectError: true},
+		{name: "GTD with past expires_at", request: limit("GTD", &past), expectError: true},
+		{name: "expires_at without GTD", request: limit("GTC", &future), expectError: true},
+		{name: "unknown time in force", request: limit("DAY", nil), expectError: true},
+		{
+			name:        "GTC market order",
+			request:     dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "SELL", Kind: "MARKET", Quantity: newBigFloat("1"), TimeInForce: "GTC"},
+			expectError: true,
+		},
+		{
+			name:    "FOK market order",
+			request: dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "SELL", Kind: "MARKET", Quantity: newBigFloat("1"), TimeInForce: "FOK"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.request.Validate()
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCreateOrderRequest_Validate_PostOnly(t *testing.T) {
+	limit := func(tif string, postOnly, reprice bool) dto.CreateOrderRequest {
+		return dto.CreateOrderRequest{
+			AccountID:      "acc-123",
+			InstrumentID:   "inst-456",
+			Type:           "BUY",
+			Price:          newBigFloat("10"),
+			Quantity:       newBigFloat("1"),
+			TimeInForce:    tif,
+			PostOnly:       postOnly,
+			RepriceOnCross: reprice,
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		request     dto.CreateOrderRequest
+		expectError bool
+	}{
+		{name: "post-only limit order", request: limit("", true, false)},
+		{name: "post-only with reprice", request: limit("GTC", true, true)},
+		{name: "reprice without post-only", request: limit("", false, true), expectError: true},
+		{name: "post-only IOC", request: limit("IOC", true, false), expectError: true},
+		{name: "post-only FOK", request: limit("FOK", true, false), expectError: true},
+		{
+			name:        "post-only market order",
+			request:     dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "SELL", Kind: "MARKET", Quantity: newBigFloat("1"), PostOnly: true},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.request.Validate()
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCreateOrderRequest_Validate_Stop(t *testing.T) {
+	testCases := []struct {
+		name        string
+		request     dto.CreateOrderRequest
+		expectError bool
+	}{
+		{
+			name:    "stop-limit sell",
+			request: dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "SELL", Kind: "STOP_LIMIT", StopPrice: newBigFloat("95"), Price: newBigFloat("94"), Quantity: newBigFloat("1")},
+		},
+		{
+			name:    "stop-market buy with quote quantity",
+			request: dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "BUY", Kind: "STOP_MARKET", StopPrice: newBigFloat("105"), QuoteQuantity: newBigFloat("100")},
+		},
+		{
+			name:        "stop-limit without stop price",
+			request:     dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "SELL", Kind: "STOP_LIMIT", Price: newBigFloat("94"), Quantity: newBigFloat("1")},
+			expectError: true,
+		},
+		{
+			name:        "stop-limit without price",
+			request:     dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "SELL", Kind: "STOP_LIMIT", StopPrice: newBigFloat("95"), Quantity: newBigFloat("1")},
+			expectError: true,
+		},
+		{
+			name:        "stop-market with price",
+			request:     dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "SELL", Kind: "STOP_MARKET", StopPrice: newBigFloat("95"), Price: newBigFloat("94"), Quantity: newBigFloat("1")},
+			expectError: true,
+		},
+		{
+			name:        "stop price on a limit order",
+			request:     dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "SELL", StopPrice: newBigFloat("95"), Price: newBigFloat("94"), Quantity: newBigFloat("1")},
+			expectError: true,
+		},
+		{
+			name:        "GTC stop-market",
+			request:     dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "SELL", Kind: "STOP_MARKET", StopPrice: newBigFloat("95"), Quantity: newBigFloat("1"), TimeInForce: "GTC"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.request.Validate()
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCreateOrderRequest_Validate_TrailingStop(t *testing.T) {
+	testCases := []struct {
+		name        string
+		request     dto.CreateOrderRequest
+		expectError bool
+	}{
+		{
+			name:    "sell with trail amount",
+			request: dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "SELL", Kind: "TRAILING_STOP_MARKET", TrailAmount: newBigFloat("5"), Quantity: newBigFloat("1")},
+		},
+		{
+			name:    "buy with trail percent and quote quantity",
+			request: dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "BUY", Kind: "TRAILING_STOP_MARKET", TrailPercent: newBigFloat("2.5"), QuoteQuantity: newBigFloat("100")},
+		},
+		{
+			name:        "without trail",
+			request:     dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "SELL", Kind: "TRAILING_STOP_MARKET", Quantity: newBigFloat("1")},
+			expectError: true,
+		},
+		{
+			name:        "with both trails",
+			request:     dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "SELL", Kind: "TRAILING_STOP_MARKET", TrailAmount: newBigFloat("5"), TrailPercent: newBigFloat("1"), Quantity: newBigFloat("1")},
+			expectError: true,
+		},
+		{
+			name:        "non-positive trail amount",
+			request:     dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "SELL", Kind: "TRAILING_STOP_MARKET", TrailAmount: newBigFloat("0"), Quantity: newBigFloat("1")},
+			expectError: true,
+		},
+		{
+			name:        "trail percent of 100",
+			request:     dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "SELL", Kind: "TRAILING_STOP_MARKET", TrailPercent: newBigFloat("100"), Quantity: newBigFloat("1")},
+			expectError: true,
+		},
+		{
+			name:        "with stop price",
+			request:     dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "SELL", Kind: "TRAILING_STOP_MARKET", StopPrice: newBigFloat("95"), TrailAmount: newBigFloat("5"), Quantity: newBigFloat("1")},
+			expectError: true,
+		},
+		{
+			name:        "trail on a stop-market order",
+			request:     dto.CreateOrderRequest{AccountID: "acc-123", InstrumentID: "inst-456", Type: "SELL", Kind: "STOP_MARKET", StopPrice: newBigFloat("95"), TrailAmount: newBigFloat("5"), Quantity: newBigFloat("1")},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.request.Validate()
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCreateOrderRequest_Validate_DisplayQuantity(t *testing.T) {
+	iceberg := func(kind, tif, display string) dto.CreateOrderRequest {
+		request := dto.CreateOrderRequest{
+			AccountID:       "acc-123",
+			InstrumentID:    "inst-456",
+			Type:            "SELL",
+			Kind:            kind,
+			Quantity:        newBigFloat("10"),
+			TimeInForce:     tif,
+			DisplayQuantity: newBigFloat(display),
+		}
+		if kind != "MARKET" {
+			request.Price = newBigFloat("100")
+		}
+		return request
+	}
+
+	testCases := []struct {
+		name        string
+		request     dto.CreateOrderRequest
+		expectError bool
+	}{
+		{name: "iceberg limit order", request: iceberg("", "", "2")},
+		{name: "display equal to quantity", request: iceberg("", "", "10"), expectError: true},
+		{name: "zero display quantity", request: iceberg("", "", "0"), expectError: true},
+		{name: "iceberg IOC", request: iceberg("", "IOC", "2"), expectError: true},
+		{name: "iceberg market order", request: iceberg("MARKET", "", "2"), expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.request.Validate()
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAmendOrderRequest_Validate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		request     dto.AmendOrderRequest
+		expectError bool
+	}{
+		{name: "price only", request: dto.AmendOrderRequest{Price: newBigFloat("10")}},
+		{name: "quantity only", request: dto.AmendOrderRequest{Quantity: newBigFloat("1")}},
+		{name: "price and quantity", request: dto.AmendOrderRequest{Price: newBigFloat("10"), Quantity: newBigFloat("1")}},
+		{name: "empty", request: dto.AmendOrderRequest{}, expectError: true},
+		{name: "zero price", request: dto.AmendOrderRequest{Price: newBigFloat("0")}, expectError: true},
+		{name: "negative quantity", request: dto.AmendOrderRequest{Quantity: newBigFloat("-1")}, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.request.Validate()
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCancelAllRequest_Validate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		request     dto.CancelAllRequest
+		expectError bool
+	}{
+		{name: "by account", request: dto.CancelAllRequest{AccountID: "acc-123"}},
+		{name: "by instrument and side", request: dto.CancelAllRequest{InstrumentID: "inst-456", Side: "SELL"}},
+		{name: "no filter", request: dto.CancelAllRequest{}, expectError: true},
+		{name: "side only", request: dto.CancelAllRequest{Side: "BUY"}, expectError: true},
+		{name: "unknown side", request: dto.CancelAllRequest{AccountID: "acc-123", Side: "LONG"}, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.request.Validate()
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBatchCreateOrderRequest_Validate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		size        int
+		expectError bool
+	}{
+		{name: "single order", size: 1},
+		{name: "full batch", size: dto.MaxBatchOrders},
+		{name: "empty batch", size: 0, expectError: true},
+		{name: "too many orders", size: dto.MaxBatchOrders + 1, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := dto.BatchCreateOrderRequest{Orders: make([]dto.CreateOrderRequest, tc.size)}
+			err := request.Validate()
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCreateOrderListRequest_Validate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		size        int
+		expectError bool
+	}{
+		{name: "two orders", size: 2},
+		{name: "single order", size: 1, expectError: true},
+		{name: "three orders", size: 3, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := dto.CreateOrderListRequest{Orders: make([]dto.CreateOrderRequest, tc.size)}
+			err := request.Validate()
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCreateOrderRequest_Validate_ClientOrderID(t *testing.T) {
+	testCases := []struct {
+		name           string
+		clientOrderID  string
+		idempotencyKey string
+		expectError    bool
+	}{
+		{name: "none"},
+		{name: "client order id and idempotency key", clientOrderID: "ladder-1", idempotencyKey: "retry-key"},
+		{name: "client order id too long", clientOrderID: strings.Repeat("x", 65), expectError: true},
+		{name: "idempotency key too long", idempotencyKey: strings.Repeat("x", 256), expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &dto.CreateOrderRequest{
+				AccountID:      "acc-123",
+				InstrumentID:   "inst-456",
+				Type:           "BUY",
+				Price:          newBigFloat("150.50"),
+				Quantity:       newBigFloat("10.5"),
+				ClientOrderID:  tc.clientOrderID,
+				IdempotencyKey: tc.idempotencyKey,
+			}
+			err := req.Validate()
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
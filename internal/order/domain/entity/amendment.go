@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/dto"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/decimal"
+)
+
+// Amendment is a request to change the price and/or total quantity of a
+// working order. Reserved is how much was reserved up front because the
+// amended order needs more funds than the original one. ID tells deliveries of
+// the same amendment apart from new ones.
+type Amendment struct {
+	ID           string
+	OrderID      string
+	InstrumentID string
+	Price        *big.Float
+	Quantity     *big.Float
+	Reserved     *big.Float
+}
+
+// ToAmendment converts an AmendOrderRequest DTO to an Amendment entity.
+func ToAmendment(orderID string, request dto.AmendOrderRequest) (*Amendment, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	amendment := &Amendment{OrderID: orderID, Reserved: new(big.Float)}
+	if request.Price != nil {
+		amendment.Price = decimal.Round(request.Price.Float, decimal.PriceScale)
+	}
+	if request.Quantity != nil {
+		amendment.Quantity = decimal.Round(request.Quantity.Float, decimal.AmountScale)
+	}
+	return amendment, nil
+}
+
+// Amended returns a copy of the order with the amendment applied. Only limit
+// priced orders sized in the base asset can be amended, and the new quantity
+// must stay above what has already been filled. Orders of an order list share
+// their reservation and cannot be amended.
+func (o *Order) Amended(amendment Amendment) (Order, error) {
+	if o.Price == nil || o.IsQuoteSized() {
+		return Order{}, errors.New("only limit orders can be amended")
+	}
+	if o.OrderListID != "" {
+		return Order{}, errors.New("orders of an order list cannot be amended")
+	}
+
+	amended := *o
+	if amendment.Price != nil {
+		amended.Price = new(big.Float).Set(amendment.Price)
+	}
+	if amendment.Quantity != nil {
+		filled := new(big.Float).Sub(o.Quantity, o.RemainingQuantity)
+		if amendment.Quantity.Cmp(filled) <= 0 {
+			return Order{}, errors.New("quantity must be greater than the filled quantity")
+		}
+		amended.Quantity = new(big.Float).Set(amendment.Quantity)
+		amended.RemainingQuantity = new(big.Float).Sub(amendment.Quantity, filled)
+		if amended.VisibleQuantity != nil {
+			amended.VisibleQuantity = minFloat(amended.VisibleQuantity, amended.RemainingQuantity)
+		}
+	}
+	return amended, nil
+}
+
+// KeepsPriority reports whether replacing the order with amended keeps its
+// place in the queue: only a quantity reduction at the same price does.
+func (o *Order) KeepsPriority(amended Order) bool {
+	return o.Price.Cmp(amended.Price) == 0 && amended.Quantity.Cmp(o.Quantity) <= 0
+}
@@ -0,0 +1,113 @@
+package entity_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/dto"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func newWorkingOrder() *entity.Order {
+	return &entity.Order{
+		ID:                "order-1",
+		Type:              entity.OrderTypeBuy,
+		Kind:              entity.OrderKindLimit,
+		Status:            entity.OrderStatusPartiallyFilled,
+		Price:             big.NewFloat(100),
+		Quantity:          big.NewFloat(10),
+		RemainingQuantity: big.NewFloat(6),
+	}
+}
+
+func TestToAmendment(t *testing.T) {
+	// act
+	amendment, err := entity.ToAmendment("order-1", dto.AmendOrderRequest{Price: newBigFloat("99")})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "order-1", amendment.OrderID)
+	assert.Zero(t, big.NewFloat(99).Cmp(amendment.Price))
+	assert.Nil(t, amendment.Quantity)
+	assert.Zero(t, amendment.Reserved.Sign())
+
+	_, err = entity.ToAmendment("order-1", dto.AmendOrderRequest{})
+	assert.Error(t, err)
+}
+
+func TestOrder_Amended(t *testing.T) {
+	t.Run("quantity reduction keeps priority", func(t *testing.T) {
+		// arrange
+		order := newWorkingOrder()
+
+		// act
+		amended, err := order.Amended(entity.Amendment{Quantity: big.NewFloat(8)})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Zero(t, big.NewFloat(8).Cmp(amended.Quantity))
+		assert.Zero(t, big.NewFloat(4).Cmp(amended.RemainingQuantity))
+		assert.Zero(t, big.NewFloat(400).Cmp(amended.ReservedAmount()))
+		assert.True(t, order.KeepsPriority(amended))
+		assert.Zero(t, big.NewFloat(6).Cmp(order.RemainingQuantity), "the original order is left untouched")
+	})
+
+	t.Run("quantity increase loses priority", func(t *testing.T) {
+		order := newWorkingOrder()
+
+		amended, err := order.Amended(entity.Amendment{Quantity: big.NewFloat(12)})
+
+		assert.NoError(t, err)
+		assert.Zero(t, big.NewFloat(8).Cmp(amended.RemainingQuantity))
+		assert.False(t, order.KeepsPriority(amended))
+	})
+
+	t.Run("price change loses priority", func(t *testing.T) {
+		order := newWorkingOrder()
+
+		amended, err := order.Amended(entity.Amendment{Price: big.NewFloat(90), Quantity: big.NewFloat(9)})
+
+		assert.NoError(t, err)
+		assert.Zero(t, big.NewFloat(90).Cmp(amended.Price))
+		assert.False(t, order.KeepsPriority(amended))
+	})
+
+	t.Run("quantity must stay above the filled quantity", func(t *testing.T) {
+		order := newWorkingOrder()
+
+		_, err := order.Amended(entity.Amendment{Quantity: big.NewFloat(4)})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("market orders cannot be amended", func(t *testing.T) {
+		order := newWorkingOrder()
+		order.Kind = entity.OrderKindMarket
+		order.Price = nil
+
+		_, err := order.Amended(entity.Amendment{Quantity: big.NewFloat(8)})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("orders of an order list cannot be amended", func(t *testing.T) {
+		order := newWorkingOrder()
+		order.OrderListID = "list-1"
+
+		_, err := order.Amended(entity.Amendment{Quantity: big.NewFloat(8)})
+
+		assert.EqualError(t, err, "orders of an order list cannot be amended")
+	})
+
+	t.Run("iceberg slice never exceeds the new remainder", func(t *testing.T) {
+		order := newWorkingOrder()
+		order.DisplayQuantity = big.NewFloat(5)
+		order.VisibleQuantity = big.NewFloat(5)
+
+		amended, err := order.Amended(entity.Amendment{Quantity: big.NewFloat(7)})
+
+		assert.NoError(t, err)
+		assert.Zero(t, big.NewFloat(3).Cmp(amended.VisibleQuantity))
+	})
+}
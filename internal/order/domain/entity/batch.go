@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+)
+
+// ReservationKey identifies the balance a reservation is taken from.
+type ReservationKey struct {
+	AccountID string
+	Asset     string
+}
+
+// Allocation shares the available balances among the orders of a batch. Orders
+// claim their reservations in batch order, so two orders can never both be
+// backed by the same funds.
+type Allocation struct {
+	available map[ReservationKey]*big.Float
+	reserved  map[ReservationKey]*big.Float
+}
+
+func NewAllocation() *Allocation {
+	return &Allocation{
+		available: make(map[ReservationKey]*big.Float),
+		reserved:  make(map[ReservationKey]*big.Float),
+	}
+}
+
+// Fund makes amount of a balance available to the batch.
+func (a *Allocation) Fund(key ReservationKey, amount *big.Float) {
+	a.available[key] = new(big.Float).Set(amount)
+}
+
+// Claim takes amount from the balance when what is left of it covers it.
+func (a *Allocation) Claim(key ReservationKey, amount *big.Float) error {
+	available, ok := a.available[key]
+	if !ok {
+		return errors.New("balance not found for required asset")
+	}
+	if available.Cmp(amount) < 0 {
+		return errors.New("insufficient balance")
+	}
+
+	available.Sub(available, amount)
+	if reserved, ok := a.reserved[key]; ok {
+		reserved.Add(reserved, amount)
+	} else {
+		a.reserved[key] = new(big.Float).Set(amount)
+	}
+	return nil
+}
+
+// Reserved returns the total claimed from each balance.
+func (a *Allocation) Reserved() map[ReservationKey]*big.Float {
+	return a.reserved
+}
+
+// SortReservationKeys orders keys by account and asset, the order in which a
+// batch locks balances so concurrent batches cannot deadlock.
+func SortReservationKeys(keys []ReservationKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].AccountID != keys[j].AccountID {
+			return keys[i].AccountID < keys[j].AccountID
+		}
+		return keys[i].Asset < keys[j].Asset
+	})
+}
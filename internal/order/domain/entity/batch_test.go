@@ -0,0 +1,73 @@
+package entity_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllocation_Claim(t *testing.T) {
+	// arrange
+	usdt := entity.ReservationKey{AccountID: "acc-1", Asset: "USDT"}
+	allocation := entity.NewAllocation()
+	allocation.Fund(usdt, big.NewFloat(1000))
+
+	// act
+	first := allocation.Claim(usdt, big.NewFloat(600))
+	second := allocation.Claim(usdt, big.NewFloat(600))
+	third := allocation.Claim(usdt, big.NewFloat(400))
+
+	// assert
+	assert.NoError(t, first)
+	assert.EqualError(t, second, "insufficient balance")
+	assert.NoError(t, third)
+	assert.Equal(t, 0, allocation.Reserved()[usdt].Cmp(big.NewFloat(1000)))
+}
+
+func TestAllocation_Claim_Unfunded(t *testing.T) {
+	// arrange
+	allocation := entity.NewAllocation()
+
+	// act
+	err := allocation.Claim(entity.ReservationKey{AccountID: "acc-1", Asset: "BTC"}, big.NewFloat(1))
+
+	// assert
+	assert.EqualError(t, err, "balance not found for required asset")
+	assert.Empty(t, allocation.Reserved())
+}
+
+func TestAllocation_Fund_CopiesAmount(t *testing.T) {
+	// arrange
+	key := entity.ReservationKey{AccountID: "acc-1", Asset: "BTC"}
+	amount := big.NewFloat(2)
+	allocation := entity.NewAllocation()
+	allocation.Fund(key, amount)
+
+	// act
+	err := allocation.Claim(key, big.NewFloat(2))
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, 0, amount.Cmp(big.NewFloat(2)))
+}
+
+func TestSortReservationKeys(t *testing.T) {
+	// arrange
+	keys := []entity.ReservationKey{
+		{AccountID: "acc-2", Asset: "BTC"},
+		{AccountID: "acc-1", Asset: "USDT"},
+		{AccountID: "acc-1", Asset: "BTC"},
+	}
+
+	// act
+	entity.SortReservationKeys(keys)
+
+	// assert
+	assert.Equal(t, []entity.ReservationKey{
+		{AccountID: "acc-1", Asset: "BTC"},
+		{AccountID: "acc-1", Asset: "USDT"},
+		{AccountID: "acc-2", Asset: "BTC"},
+	}, keys)
+}
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"fmt"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/dto"
+)
+
+// CancelResult is the engine's answer to a cancel command. Cancelled is false
+// when the order had already reached a terminal state, e.g. because a fill
+// won the race; Status then holds that state.
+type CancelResult struct {
+	OrderID   string
+	Cancelled bool
+	Status    OrderStatus
+	Reason    string
+}
+
+// IsTerminal reports whether the order can no longer change state.
+func (o *Order) IsTerminal() bool {
+	return o.Status == OrderStatusFilled || o.Status == OrderStatusCancelled
+}
+
+// Cancel moves an active order to CANCELLED. Terminal orders are rejected.
+func (o *Order) Cancel() error {
+	if o.IsTerminal() {
+		return fmt.Errorf("order is %s", o.Status)
+	}
+	o.Status = OrderStatusCancelled
+	return nil
+}
+
+// CancelFilter selects the orders of a mass cancel. Empty fields match
+// everything.
+type CancelFilter struct {
+	AccountID    string
+	InstrumentID string
+	Side         OrderType
+}
+
+// ToCancelFilter converts a CancelAllRequest DTO to a CancelFilter.
+func ToCancelFilter(request dto.CancelAllRequest) (CancelFilter, error) {
+	if err := request.Validate(); err != nil {
+		return CancelFilter{}, err
+	}
+	return CancelFilter{
+		AccountID:    request.AccountID,
+		InstrumentID: request.InstrumentID,
+		Side:         OrderType(request.Side),
+	}, nil
+}
+
+// Matches reports whether the order is selected by the filter.
+func (f CancelFilter) Matches(order *Order) bool {
+	return (f.AccountID == "" || order.AccountID == f.AccountID) &&
+		(f.InstrumentID == "" || order.InstrumentID == f.InstrumentID) &&
+		(f.Side == "" || order.Type == f.Side)
+}
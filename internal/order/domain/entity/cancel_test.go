@@ -0,0 +1,57 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/dto"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrder_Cancel(t *testing.T) {
+	testCases := []struct {
+		status      entity.OrderStatus
+		expectError bool
+	}{
+		{status: entity.OrderStatusOpen},
+		{status: entity.OrderStatusTriggered},
+		{status: entity.OrderStatusPartiallyFilled},
+		{status: entity.OrderStatusFilled, expectError: true},
+		{status: entity.OrderStatusCancelled, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.status), func(t *testing.T) {
+			// arrange
+			order := &entity.Order{Status: tc.status}
+
+			// act
+			err := order.Cancel()
+
+			// assert
+			if tc.expectError {
+				assert.Error(t, err)
+				assert.True(t, order.IsTerminal())
+				assert.Equal(t, tc.status, order.Status)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, entity.OrderStatusCancelled, order.Status)
+			}
+		})
+	}
+}
+
+func TestCancelFilter_Matches(t *testing.T) {
+	// arrange
+	filter, err := entity.ToCancelFilter(dto.CancelAllRequest{AccountID: "acc-1", Side: "BUY"})
+	assert.NoError(t, err)
+
+	// act & assert
+	assert.True(t, filter.Matches(&entity.Order{AccountID: "acc-1", InstrumentID: "inst-1", Type: entity.OrderTypeBuy}))
+	assert.True(t, filter.Matches(&entity.Order{AccountID: "acc-1", InstrumentID: "inst-2", Type: entity.OrderTypeBuy}))
+	assert.False(t, filter.Matches(&entity.Order{AccountID: "acc-1", InstrumentID: "inst-1", Type: entity.OrderTypeSell}))
+	assert.False(t, filter.Matches(&entity.Order{AccountID: "acc-2", InstrumentID: "inst-1", Type: entity.OrderTypeBuy}))
+
+	_, err = entity.ToCancelFilter(dto.CancelAllRequest{})
+	assert.Error(t, err)
+}
@@ -2,67 +2,453 @@ package entity
 
 import (
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/dto"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/decimal"
 )
 
 type OrderType string
+type OrderKind string
 type OrderStatus string
+type TimeInForce string
 
 const (
 	OrderTypeBuy  OrderType = "BUY"
 	OrderTypeSell OrderType = "SELL"
 
+	OrderKindLimit  OrderKind = "LIMIT"
+	OrderKindMarket OrderKind = "MARKET"
+	// OrderKindStopMarket becomes a MARKET order once its stop price is reached.
+	OrderKindStopMarket OrderKind = "STOP_MARKET"
+	// OrderKindStopLimit becomes a LIMIT order once its stop price is reached.
+	OrderKindStopLimit OrderKind = "STOP_LIMIT"
+	// OrderKindTrailingStopMarket becomes a MARKET order once the price
+	// reverses by its trail from the best price since it was placed.
+	OrderKindTrailingStopMarket OrderKind = "TRAILING_STOP_MARKET"
+
 	OrderStatusOpen            OrderStatus = "OPEN"
+	OrderStatusTriggered       OrderStatus = "TRIGGERED"
 	OrderStatusPartiallyFilled OrderStatus = "PARTIALLY_FILLED"
 	OrderStatusFilled          OrderStatus = "FILLED"
 	OrderStatusCancelled       OrderStatus = "CANCELLED"
+
+	// TimeInForceGTC rests until filled or cancelled.
+	TimeInForceGTC TimeInForce = "GTC"
+	// TimeInForceIOC fills what it can immediately and cancels the rest.
+	TimeInForceIOC TimeInForce = "IOC"
+	// TimeInForceFOK fills completely on arrival or not at all.
+	TimeInForceFOK TimeInForce = "FOK"
+	// TimeInForceGTD rests until filled, cancelled or ExpiresAt.
+	TimeInForceGTD TimeInForce = "GTD"
 )
 
+// Order is a LIMIT or MARKET order, or a stop order that becomes one when the
+// last trade price reaches StopPrice. Market orders have no Price. Market buys
+// are sized by a QuoteQuantity budget instead of a base quantity; for them
+// Quantity accumulates the executed base quantity and RemainingQuantity stays
+// at zero.
+//
+// Trailing stops trail the best trade price by TrailAmount or TrailPercent.
+// WaterMark is that best price, the highest for sells and the lowest for buys,
+// and StopPrice the trigger price that follows it.
+//
+// Iceberg orders set DisplayQuantity: while resting only VisibleQuantity, a
+// slice of at most DisplayQuantity, is shown and matched as a maker.
+//
+// ClientOrderID is the account's own, unique reference to the order and
+// IdempotencyKey the key of the request that created it, if any.
+// SelfTradePrevention is the policy applied when it would trade against an
+// order of the same account.
+//
+// OrderListID links the order to the other orders of its order list, if any.
+//
+// BookSequence is the order's place in the time priority of its book: it
+// rests behind every order at its price with a lower BookSequence. It is zero
+// until the engine books the order.
+//
+// AmendmentID is the ID of the last amendment the engine applied to the
+// order, so an amendment delivered again is not applied twice.
 type Order struct {
-	ID                string
-	AccountID         string
-	InstrumentID      string
-	Type              OrderType
-	Status            OrderStatus
-	Price             *big.Float
-	Quantity          *big.Float
-	RemainingQuantity *big.Float
-	CreatedAt         time.Time
-	UpdatedAt         time.Time
-}
-
-// ToEntity converts a CreateOrderRequest DTO to an Order entity.
+	ID                     string
+	AccountID              string
+	InstrumentID           string
+	Type                   OrderType
+	Kind                   OrderKind
+	Status                 OrderStatus
+	Price                  *big.Float
+	StopPrice              *big.Float
+	TrailAmount            *big.Float
+	TrailPercent           *big.Float
+	WaterMark              *big.Float
+	Quantity               *big.Float
+	RemainingQuantity      *big.Float
+	QuoteQuantity          *big.Float
+	RemainingQuoteQuantity *big.Float
+	DisplayQuantity        *big.Float
+	VisibleQuantity        *big.Float
+	TimeInForce            TimeInForce
+	ExpiresAt              *time.Time
+	TriggeredAt            *time.Time
+	PostOnly               bool
+	RepriceOnCross         bool
+	ClientOrderID          string
+	IdempotencyKey         string
+	SelfTradePrevention    SelfTradePrevention
+	OrderListID            string
+	BookSequence           int64
+	AmendmentID            string
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
+}
+
+// ToEntity converts a CreateOrderRequest DTO to an Order entity. Prices and
+// quantities are rounded to the scales the orders table stores them with, so
+// the order reserves what it will reserve once read back.
 func ToEntity(request dto.CreateOrderRequest) (*Order, error) {
 	if err := request.Validate(); err != nil {
 		return nil, err
 	}
-	return &Order{
-		AccountID:         request.AccountID,
-		InstrumentID:      request.InstrumentID,
-		Type:              OrderType(request.Type),
-		Status:            OrderStatusOpen,
-		Price:             request.Price.Float,
-		Quantity:          request.Quantity.Float,
-		RemainingQuantity: request.Quantity.Float,
-	}, nil
+
+	order := &Order{
+		AccountID:           request.AccountID,
+		InstrumentID:        request.InstrumentID,
+		Type:                OrderType(request.Type),
+		Kind:                OrderKindLimit,
+		Status:              OrderStatusOpen,
+		TimeInForce:         TimeInForceGTC,
+		ExpiresAt:           request.ExpiresAt,
+		PostOnly:            request.PostOnly,
+		RepriceOnCross:      request.RepriceOnCross,
+		ClientOrderID:       request.ClientOrderID,
+		IdempotencyKey:      request.IdempotencyKey,
+		SelfTradePrevention: SelfTradePrevention(request.SelfTradePrevention),
+	}
+	if request.Kind != "" {
+		order.Kind = OrderKind(request.Kind)
+	}
+	if order.IsMarket() {
+		order.TimeInForce = TimeInForceIOC
+	}
+	if request.TimeInForce != "" {
+		order.TimeInForce = TimeInForce(request.TimeInForce)
+	}
+	if request.Price != nil {
+		order.Price = decimal.Round(request.Price.Float, decimal.PriceScale)
+	}
+	if request.StopPrice != nil {
+		order.StopPrice = decimal.Round(request.StopPrice.Float, decimal.PriceScale)
+	}
+	if request.TrailAmount != nil {
+		order.TrailAmount = decimal.Round(request.TrailAmount.Float, decimal.PriceScale)
+	}
+	if request.TrailPercent != nil {
+		order.TrailPercent = decimal.Round(request.TrailPercent.Float, decimal.PriceScale)
+	}
+	if request.DisplayQuantity != nil {
+		order.DisplayQuantity = decimal.Round(request.DisplayQuantity.Float, decimal.AmountScale)
+	}
+
+	if request.QuoteQuantity != nil {
+		order.Quantity = new(big.Float)
+		order.RemainingQuantity = new(big.Float)
+		order.QuoteQuantity = decimal.Round(request.QuoteQuantity.Float, decimal.AmountScale)
+		order.RemainingQuoteQuantity = order.QuoteQuantity
+		return order, nil
+	}
+
+	order.Quantity = decimal.Round(request.Quantity.Float, decimal.AmountScale)
+	order.RemainingQuantity = order.Quantity
+	return order, nil
 }
 
 // ToDTO converts an Order entity to an OrderDTO.
 func (o *Order) ToDTO() dto.OrderDTO {
-	return dto.OrderDTO{
-		ID:                o.ID,
-		AccountID:         o.AccountID,
-		InstrumentID:      o.InstrumentID,
-		Type:              string(o.Type),
-		Status:            string(o.Status),
-		Price:             *o.Price,
-		Quantity:          *o.Quantity,
-		RemainingQuantity: *o.RemainingQuantity,
-		CreatedAt:         o.CreatedAt,
-		UpdatedAt:         o.UpdatedAt,
+	orderDTO := dto.OrderDTO{
+		ID:                     o.ID,
+		AccountID:              o.AccountID,
+		InstrumentID:           o.InstrumentID,
+		Type:                   string(o.Type),
+		Kind:                   string(o.Kind),
+		Status:                 string(o.Status),
+		Quantity:               *o.Quantity,
+		RemainingQuantity:      *o.RemainingQuantity,
+		QuoteQuantity:          o.QuoteQuantity,
+		RemainingQuoteQuantity: o.RemainingQuoteQuantity,
+		DisplayQuantity:        o.DisplayQuantity,
+		TimeInForce:            string(o.TimeInForce),
+		TrailAmount:            o.TrailAmount,
+		TrailPercent:           o.TrailPercent,
+		ExpiresAt:              o.ExpiresAt,
+		TriggeredAt:            o.TriggeredAt,
+		PostOnly:               o.PostOnly,
+		RepriceOnCross:         o.RepriceOnCross,
+		ClientOrderID:          o.ClientOrderID,
+		SelfTradePrevention:    string(o.SelfTradePrevention),
+		OrderListID:            o.OrderListID,
+		CreatedAt:              o.CreatedAt,
+		UpdatedAt:              o.UpdatedAt,
+	}
+	if o.Price != nil {
+		orderDTO.Price = *o.Price
+	}
+	if o.IsStop() {
+		orderDTO.CurrentTriggerPrice = o.StopPrice
+	}
+	if !o.IsTrailing() {
+		orderDTO.StopPrice = o.StopPrice
+	}
+	return orderDTO
+}
+
+// IsMarket reports whether the order executes at any available price once
+// active.
+func (o *Order) IsMarket() bool {
+	return o.Kind == OrderKindMarket || o.Kind == OrderKindStopMarket || o.Kind == OrderKindTrailingStopMarket
+}
+
+// IsStop reports whether the order is a stop order.
+func (o *Order) IsStop() bool {
+	return o.Kind == OrderKindStopMarket || o.Kind == OrderKindStopLimit || o.Kind == OrderKindTrailingStopMarket
+}
+
+// IsTrailing reports whether the order is a trailing stop order.
+func (o *Order) IsTrailing() bool {
+	return o.Kind == OrderKindTrailingStopMarket
+}
+
+// IsPostOnlyLimit reports whether the order is a post-only limit order, one
+// that never takes liquidity.
+func (o *Order) IsPostOnlyLimit() bool {
+	return o.PostOnly && o.Kind == OrderKindLimit
+}
+
+// IsRestingLimit reports whether the order is a limit order whose unfilled
+// part may rest in the book.
+func (o *Order) IsRestingLimit() bool {
+	return o.Kind == OrderKindLimit && o.CanRest()
+}
+
+// AwaitsTrigger reports whether the order is a stop order that has not been
+// triggered yet.
+func (o *Order) AwaitsTrigger() bool {
+	return o.IsStop() && o.TriggeredAt == nil
+}
+
+// ShouldTrigger reports whether lastPrice reaches the stop price: at or above
+// it for buys, at or below it for sells. Trailing stops have no stop price
+// until the first trade sets their water mark.
+func (o *Order) ShouldTrigger(lastPrice *big.Float) bool {
+	if !o.AwaitsTrigger() || lastPrice == nil || o.StopPrice == nil {
+		return false
+	}
+	if o.Type == OrderTypeBuy {
+		return lastPrice.Cmp(o.StopPrice) >= 0
+	}
+	return lastPrice.Cmp(o.StopPrice) <= 0
+}
+
+// Trail follows a trade at price with a waiting trailing stop. A price better
+// than the water mark, higher for sells and lower for buys, becomes the new
+// mark and the trigger price moves with it, the trail below it for sells and
+// above it for buys, rounded to the 10 decimals prices are stored with. It
+// reports whether the trigger price moved.
+func (o *Order) Trail(price *big.Float) bool {
+	if !o.IsTrailing() || !o.AwaitsTrigger() || price == nil {
+		return false
+	}
+	if o.WaterMark != nil {
+		cmp := price.Cmp(o.WaterMark)
+		if (o.Type == OrderTypeSell && cmp <= 0) || (o.Type == OrderTypeBuy && cmp >= 0) {
+			return false
+		}
+	}
+
+	o.WaterMark = new(big.Float).Set(price)
+	trail := o.TrailAmount
+	if o.TrailPercent != nil {
+		trail = new(big.Float).Quo(new(big.Float).Mul(price, o.TrailPercent), big.NewFloat(100))
+	}
+	trigger := new(big.Float).Add(price, trail)
+	if o.Type == OrderTypeSell {
+		trigger = new(big.Float).Sub(price, trail)
+	}
+	o.StopPrice = decimal.Round(trigger, decimal.PriceScale)
+	return true
+}
+
+// Trigger activates a stop order so it enters the order book flow.
+func (o *Order) Trigger(now time.Time) {
+	o.Status = OrderStatusTriggered
+	o.TriggeredAt = &now
+}
+
+// SameRequest reports whether other asks for the same order: the same
+// account, instrument, side, kind, prices and size. A request retried with an
+// idempotency key must match the order the key created. Trailing stops are
+// compared by their trail, as their stop price moves.
+func (o *Order) SameRequest(other Order) bool {
+	same := o.AccountID == other.AccountID &&
+		o.InstrumentID == other.InstrumentID &&
+		o.Type == other.Type &&
+		o.Kind == other.Kind &&
+		sameOptional(o.Price, other.Price) &&
+		sameOptional(o.QuoteQuantity, other.QuoteQuantity) &&
+		(o.IsQuoteSized() || o.Quantity.Cmp(other.Quantity) == 0)
+	if o.IsTrailing() {
+		return same && sameOptional(o.TrailAmount, other.TrailAmount) && sameOptional(o.TrailPercent, other.TrailPercent)
+	}
+	return same && sameOptional(o.StopPrice, other.StopPrice)
+}
+
+func sameOptional(a, b *big.Float) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(b) == 0
+}
+
+// IsQuoteSized reports whether the order is sized by a quote budget.
+func (o *Order) IsQuoteSized() bool {
+	return o.QuoteQuantity != nil
+}
+
+// ExecutableQuantity returns the most the order can still execute at price.
+// For quote-sized orders it is what the remaining budget affords, truncated
+// to the 18 decimals the orders table stores.
+func (o *Order) ExecutableQuantity(price *big.Float) *big.Float {
+	if !o.IsQuoteSized() {
+		return new(big.Float).Set(o.RemainingQuantity)
+	}
+	affordable := new(big.Float).SetPrec(256).Quo(o.RemainingQuoteQuantity, price)
+	truncated, _ := decimal.Parse(truncate(affordable.Text('f', 30), 18))
+	return truncated
+}
+
+// Fill records an execution of quantity at price and moves the status to
+// PARTIALLY_FILLED or FILLED accordingly. A quote-sized order is FILLED once
+// its remaining budget cannot afford anything more at price. It returns the
+// part of the reservation the execution used, the difference between what the
+// order reserved before and after it, so the parts used by successive fills
+// always add up to what was reserved.
+func (o *Order) Fill(quantity, price *big.Float) *big.Float {
+	before := o.ReservedAmount()
+	o.fill(quantity, price)
+	return new(big.Float).Sub(before, o.ReservedAmount())
+}
+
+func (o *Order) fill(quantity, price *big.Float) {
+	if o.IsQuoteSized() {
+		notional := decimal.Round(new(big.Float).SetPrec(decimal.Precision).Mul(price, quantity), decimal.AmountScale)
+		o.Quantity = new(big.Float).Add(o.Quantity, quantity)
+		o.RemainingQuoteQuantity = new(big.Float).Sub(o.RemainingQuoteQuantity, notional)
+		if o.ExecutableQuantity(price).Sign() <= 0 {
+			o.Status = OrderStatusFilled
+			return
+		}
+		o.Status = OrderStatusPartiallyFilled
+		return
+	}
+
+	o.RemainingQuantity = new(big.Float).Sub(o.RemainingQuantity, quantity)
+	if o.VisibleQuantity != nil {
+		o.VisibleQuantity = new(big.Float).Sub(o.VisibleQuantity, quantity)
+		if o.VisibleQuantity.Sign() < 0 {
+			o.VisibleQuantity = new(big.Float)
+		}
+	}
+	if o.RemainingQuantity.Sign() <= 0 {
+		o.RemainingQuantity = new(big.Float)
+		o.Status = OrderStatusFilled
+		return
+	}
+	o.Status = OrderStatusPartiallyFilled
+}
+
+// CanRest reports whether the unfilled part of the order may wait in the
+// book. Market, IOC and FOK orders never rest.
+func (o *Order) CanRest() bool {
+	return !o.IsMarket() && o.TimeInForce != TimeInForceIOC && o.TimeInForce != TimeInForceFOK
+}
+
+// IsExpired reports whether a GTD order has reached its deadline at now.
+func (o *Order) IsExpired(now time.Time) bool {
+	return o.TimeInForce == TimeInForceGTD && o.ExpiresAt != nil && !now.Before(*o.ExpiresAt)
+}
+
+// IsIceberg reports whether the order hides part of its size.
+func (o *Order) IsIceberg() bool {
+	return o.DisplayQuantity != nil
+}
+
+// Refill shows the next slice of an iceberg order: DisplayQuantity, or what
+// is left when less remains. It reports whether a new slice was shown.
+func (o *Order) Refill() bool {
+	if !o.IsIceberg() || o.RemainingQuantity.Sign() <= 0 {
+		return false
+	}
+	if o.VisibleQuantity != nil && o.VisibleQuantity.Sign() > 0 {
+		return false
+	}
+	o.VisibleQuantity = minFloat(o.DisplayQuantity, o.RemainingQuantity)
+	return true
+}
+
+// ShownQuantity returns the quantity the public book shows for the order
+// while it rests: the visible slice for icebergs, everything otherwise.
+func (o *Order) ShownQuantity() *big.Float {
+	if o.IsIceberg() && o.VisibleQuantity != nil {
+		return new(big.Float).Set(o.VisibleQuantity)
+	}
+	return new(big.Float).Set(o.RemainingQuantity)
+}
+
+// IsActive reports whether the order can still trade.
+func (o *Order) IsActive() bool {
+	return o.Status == OrderStatusOpen || o.Status == OrderStatusTriggered || o.Status == OrderStatusPartiallyFilled
+}
+
+// ReservedAsset returns the asset an order locks while it is active: the quote
+// asset for buys and the base asset for sells.
+func (o *Order) ReservedAsset(baseAsset, quoteAsset string) string {
+	if o.Type == OrderTypeBuy {
+		return quoteAsset
+	}
+	return baseAsset
+}
+
+// ReservedAmount returns how much of the reserved asset still backs the
+// unfilled part of the order. Limit buys reserve their notional at the limit
+// price, computed at decimal.Precision and rounded to the scale balances are
+// stored with, so an order read back from the database reserves exactly what
+// it reserved when it was created.
+func (o *Order) ReservedAmount() *big.Float {
+	switch {
+	case o.IsQuoteSized():
+		return new(big.Float).Set(o.RemainingQuoteQuantity)
+	case o.Type == OrderTypeBuy:
+		notional := new(big.Float).SetPrec(decimal.Precision).Mul(o.Price, o.RemainingQuantity)
+		return decimal.Round(notional, decimal.AmountScale)
+	default:
+		return new(big.Float).Set(o.RemainingQuantity)
+	}
+}
+
+func minFloat(a, b *big.Float) *big.Float {
+	if a.Cmp(b) <= 0 {
+		return new(big.Float).Set(a)
+	}
+	return new(big.Float).Set(b)
+}
+
+// truncate cuts a decimal string to at most the given number of decimals.
+func truncate(s string, decimals int) string {
+	dot := strings.IndexByte(s, '.')
+	if dot < 0 || len(s)-dot-1 <= decimals {
+		return s
 	}
+	return s[:dot+1+decimals]
 }
 
 // ToListDTO converts a slice of Order entities to a slice of OrderDTOs.
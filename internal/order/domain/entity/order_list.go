@@ -0,0 +1,149 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/dto"
+)
+
+type ContingencyType string
+type OrderListStatus string
+
+const (
+	// ContingencyOCO links two orders so that either one cancels the other.
+	ContingencyOCO ContingencyType = "OCO"
+
+	// OrderListStatusExecuting is a list whose orders all still work.
+	OrderListStatusExecuting OrderListStatus = "EXECUTING"
+	// OrderListStatusAllDone is a list one of whose orders traded, was
+	// triggered or ended. The other order was cancelled; what is left of the
+	// first one works on as a plain order.
+	OrderListStatusAllDone OrderListStatus = "ALL_DONE"
+)
+
+// OrderList is a set of linked orders. The two orders of an OCO list never
+// work at the same time, so they share a single reservation: Reserved, the
+// larger of the two orders' reservations. Shared is the part of it both orders
+// count as theirs, the smaller one. The order cancelled when the list is done
+// releases what it holds less Shared, so what the list locked is released
+// exactly once.
+type OrderList struct {
+	ID              string
+	AccountID       string
+	InstrumentID    string
+	ContingencyType ContingencyType
+	Status          OrderListStatus
+	Reserved        *big.Float
+	Shared          *big.Float
+	Orders          []Order
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// ToOrderList converts a CreateOrderListRequest DTO to an OCO order list. Its
+// orders must be a LIMIT order that can rest and a stop order of the same
+// account, instrument and side, with the limit price on the profitable side
+// of the stop price: above it for sells and below it for buys.
+func ToOrderList(request dto.CreateOrderListRequest) (*OrderList, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	orders := make([]Order, len(request.Orders))
+	for i, orderReq := range request.Orders {
+		order, err := ToEntity(orderReq)
+		if err != nil {
+			return nil, fmt.Errorf("orders[%d]: %w", i, err)
+		}
+		orders[i] = *order
+	}
+
+	limit, stop := orders[0], orders[1]
+	if limit.IsStop() {
+		limit, stop = stop, limit
+	}
+	switch {
+	case limit.Kind != OrderKindLimit || (stop.Kind != OrderKindStopMarket && stop.Kind != OrderKindStopLimit):
+		return nil, errors.New("an OCO order list takes a LIMIT order and a STOP_MARKET or STOP_LIMIT order")
+	case limit.AccountID != stop.AccountID || limit.InstrumentID != stop.InstrumentID || limit.Type != stop.Type:
+		return nil, errors.New("the orders of an OCO order list must share account, instrument and side")
+	case !limit.CanRest():
+		return nil, errors.New("the LIMIT order of an OCO order list must be GTC or GTD")
+	case limit.ClientOrderID != "" && limit.ClientOrderID == stop.ClientOrderID:
+		return nil, errors.New("the orders of an OCO order list need distinct client_order_ids")
+	case limit.Type == OrderTypeSell && limit.Price.Cmp(stop.StopPrice) <= 0:
+		return nil, errors.New("the LIMIT price of a sell OCO order list must be above its stop_price")
+	case limit.Type == OrderTypeBuy && limit.Price.Cmp(stop.StopPrice) >= 0:
+		return nil, errors.New("the LIMIT price of a buy OCO order list must be below its stop_price")
+	}
+
+	reserved, shared := orders[0].ReservedAmount(), orders[1].ReservedAmount()
+	if reserved.Cmp(shared) < 0 {
+		reserved, shared = shared, reserved
+	}
+	return &OrderList{
+		AccountID:       limit.AccountID,
+		InstrumentID:    limit.InstrumentID,
+		ContingencyType: ContingencyOCO,
+		Status:          OrderListStatusExecuting,
+		Reserved:        reserved,
+		Shared:          shared,
+		Orders:          orders,
+	}, nil
+}
+
+// IsExecuting reports whether every order of the list still works.
+func (l *OrderList) IsExecuting() bool {
+	return l.Status == OrderListStatusExecuting
+}
+
+// Sibling returns the ID of the order of the list other than orderID.
+func (l *OrderList) Sibling(orderID string) string {
+	for _, order := range l.Orders {
+		if order.ID != orderID {
+			return order.ID
+		}
+	}
+	return ""
+}
+
+// Unshared returns what an order of the list holding amount releases when it
+// is cancelled because the list is done: amount less the shared part of the
+// reservation.
+func (l *OrderList) Unshared(amount *big.Float) *big.Float {
+	unshared := new(big.Float).Sub(amount, l.Shared)
+	if unshared.Sign() < 0 {
+		return new(big.Float)
+	}
+	return unshared
+}
+
+// OrderIDs returns the IDs of the orders of the list.
+func (l *OrderList) OrderIDs() []string {
+	ids := make([]string, len(l.Orders))
+	for i, order := range l.Orders {
+		ids[i] = order.ID
+	}
+	return ids
+}
+
+// ToDTO converts an OrderList entity to an OrderListDTO.
+func (l *OrderList) ToDTO() dto.OrderListDTO {
+	listDTO := dto.OrderListDTO{
+		ID:              l.ID,
+		AccountID:       l.AccountID,
+		InstrumentID:    l.InstrumentID,
+		ContingencyType: string(l.ContingencyType),
+		Status:          string(l.Status),
+		Orders:          ToListDTO(l.Orders),
+		CreatedAt:       l.CreatedAt,
+		UpdatedAt:       l.UpdatedAt,
+	}
+	if l.Reserved != nil {
+		listDTO.Reserved = *l.Reserved
+	}
+	return listDTO
+}
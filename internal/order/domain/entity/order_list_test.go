@@ -0,0 +1,129 @@
+package entity_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/dto"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+// newOCORequest returns a sell OCO list: a take-profit at 110 and a
+// stop-loss triggered at 90 that sells at 89.
+func newOCORequest() dto.CreateOrderListRequest {
+	return dto.CreateOrderListRequest{Orders: []dto.CreateOrderRequest{
+		{
+			AccountID:    "acc-1",
+			InstrumentID: "inst-1",
+			Type:         "SELL",
+			Price:        newBigFloat("110"),
+			Quantity:     newBigFloat("2"),
+		},
+		{
+			AccountID:    "acc-1",
+			InstrumentID: "inst-1",
+			Type:         "SELL",
+			Kind:         "STOP_LIMIT",
+			Price:        newBigFloat("89"),
+			StopPrice:    newBigFloat("90"),
+			Quantity:     newBigFloat("2"),
+		},
+	}}
+}
+
+func TestToOrderList(t *testing.T) {
+	// act
+	list, err := entity.ToOrderList(newOCORequest())
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ContingencyOCO, list.ContingencyType)
+	assert.Equal(t, entity.OrderListStatusExecuting, list.Status)
+	assert.Equal(t, "acc-1", list.AccountID)
+	assert.Equal(t, "inst-1", list.InstrumentID)
+	assert.Len(t, list.Orders, 2)
+	assert.Zero(t, big.NewFloat(2).Cmp(list.Reserved))
+	assert.Zero(t, big.NewFloat(2).Cmp(list.Shared))
+}
+
+func TestToOrderList_BuyReservesTheLargerOrder(t *testing.T) {
+	// arrange: a take-profit at 90 and a stop-loss triggered at 110 buying up to 111
+	request := dto.CreateOrderListRequest{Orders: []dto.CreateOrderRequest{
+		{AccountID: "acc-1", InstrumentID: "inst-1", Type: "BUY", Kind: "STOP_LIMIT", Price: newBigFloat("111"), StopPrice: newBigFloat("110"), Quantity: newBigFloat("2")},
+		{AccountID: "acc-1", InstrumentID: "inst-1", Type: "BUY", Price: newBigFloat("90"), Quantity: newBigFloat("2")},
+	}}
+
+	// act
+	list, err := entity.ToOrderList(request)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Zero(t, big.NewFloat(222).Cmp(list.Reserved))
+	assert.Zero(t, big.NewFloat(180).Cmp(list.Shared))
+}
+
+func TestToOrderList_Invalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		modify func(r *dto.CreateOrderListRequest)
+	}{
+		{name: "single order", modify: func(r *dto.CreateOrderListRequest) { r.Orders = r.Orders[:1] }},
+		{name: "invalid order", modify: func(r *dto.CreateOrderListRequest) { r.Orders[0].Quantity = nil }},
+		{name: "two limit orders", modify: func(r *dto.CreateOrderListRequest) {
+			r.Orders[1].Kind = ""
+			r.Orders[1].StopPrice = nil
+		}},
+		{name: "market order", modify: func(r *dto.CreateOrderListRequest) {
+			r.Orders[0].Kind = "MARKET"
+			r.Orders[0].Price = nil
+		}},
+		{name: "different accounts", modify: func(r *dto.CreateOrderListRequest) { r.Orders[1].AccountID = "acc-2" }},
+		{name: "different instruments", modify: func(r *dto.CreateOrderListRequest) { r.Orders[1].InstrumentID = "inst-2" }},
+		{name: "different sides", modify: func(r *dto.CreateOrderListRequest) {
+			r.Orders[1].Type = "BUY"
+			r.Orders[1].StopPrice = newBigFloat("120")
+		}},
+		{name: "limit order cannot rest", modify: func(r *dto.CreateOrderListRequest) { r.Orders[0].TimeInForce = "IOC" }},
+		{name: "same client order ID", modify: func(r *dto.CreateOrderListRequest) {
+			r.Orders[0].ClientOrderID = "tp-1"
+			r.Orders[1].ClientOrderID = "tp-1"
+		}},
+		{name: "sell limit below the stop price", modify: func(r *dto.CreateOrderListRequest) { r.Orders[0].Price = newBigFloat("85") }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			request := newOCORequest()
+			tc.modify(&request)
+
+			// act
+			list, err := entity.ToOrderList(request)
+
+			// assert
+			assert.Error(t, err)
+			assert.Nil(t, list)
+		})
+	}
+}
+
+func TestOrderList_Sibling(t *testing.T) {
+	// arrange
+	list := entity.OrderList{Orders: []entity.Order{{ID: "order-1"}, {ID: "order-2"}}}
+
+	// act & assert
+	assert.Equal(t, "order-2", list.Sibling("order-1"))
+	assert.Equal(t, "order-1", list.Sibling("order-2"))
+	assert.Equal(t, []string{"order-1", "order-2"}, list.OrderIDs())
+}
+
+func TestOrderList_Unshared(t *testing.T) {
+	// arrange
+	list := entity.OrderList{Reserved: big.NewFloat(222), Shared: big.NewFloat(180)}
+
+	// act & assert
+	assert.Zero(t, big.NewFloat(42).Cmp(list.Unshared(big.NewFloat(222))), "the larger order releases what the other does not share")
+	assert.Zero(t, new(big.Float).Cmp(list.Unshared(big.NewFloat(180))), "the smaller order releases nothing")
+	assert.Zero(t, new(big.Float).Cmp(list.Unshared(big.NewFloat(100))), "never negative")
+}
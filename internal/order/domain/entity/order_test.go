@@ -1,12 +1,14 @@
 package entity_test
 
 import (
+	"encoding/json"
 	"math/big"
 	"testing"
 	"time"
 
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/dto"
 	"github.com/mthpedrosa/financial-exchange-challenge/internal/order/domain/entity"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -127,3 +129,482 @@ func TestToListDTO(t *testing.T) {
 		assert.Empty(t, dtos)
 	})
 }
+
+func TestOrder_Fill(t *testing.T) {
+	t.Run("should move to PARTIALLY_FILLED when quantity remains", func(t *testing.T) {
+		// arrange
+		quantity, _ := new(big.Float).SetString("10")
+		order := &entity.Order{Status: entity.OrderStatusOpen, Quantity: quantity, RemainingQuantity: quantity}
+
+		// act
+		order.Fill(big.NewFloat(4), big.NewFloat(100))
+
+		// assert
+		assert.Equal(t, entity.OrderStatusPartiallyFilled, order.Status)
+		assert.Zero(t, big.NewFloat(6).Cmp(order.RemainingQuantity))
+		assert.Zero(t, big.NewFloat(10).Cmp(order.Quantity), "Quantity should not be mutated")
+	})
+
+	t.Run("should move to FILLED when nothing remains", func(t *testing.T) {
+		// arrange
+		quantity, _ := new(big.Float).SetString("10")
+		order := &entity.Order{Status: entity.OrderStatusPartiallyFilled, Quantity: quantity, RemainingQuantity: big.NewFloat(3)}
+
+		// act
+		order.Fill(big.NewFloat(3), big.NewFloat(100))
+
+		// assert
+		assert.Equal(t, entity.OrderStatusFilled, order.Status)
+		assert.Zero(t, order.RemainingQuantity.Sign())
+	})
+}
+
+func TestOrder_IsActive(t *testing.T) {
+	assert.True(t, (&entity.Order{Status: entity.OrderStatusOpen}).IsActive())
+	assert.True(t, (&entity.Order{Status: entity.OrderStatusPartiallyFilled}).IsActive())
+	assert.False(t, (&entity.Order{Status: entity.OrderStatusFilled}).IsActive())
+	assert.False(t, (&entity.Order{Status: entity.OrderStatusCancelled}).IsActive())
+}
+
+func TestOrder_Reservation(t *testing.T) {
+	t.Run("limit buys reserve the quote asset at the limit price", func(t *testing.T) {
+		order := &entity.Order{Type: entity.OrderTypeBuy, Kind: entity.OrderKindLimit, Price: big.NewFloat(100), RemainingQuantity: big.NewFloat(3)}
+
+		assert.Equal(t, "USDT", order.ReservedAsset("BTC", "USDT"))
+		assert.Zero(t, big.NewFloat(300).Cmp(order.ReservedAmount()))
+		assert.Zero(t, big.NewFloat(250).Cmp(order.Fill(big.NewFloat(2.5), big.NewFloat(90))), "a fill below the limit uses the reservation at the limit")
+	})
+
+	t.Run("sells reserve the base quantity", func(t *testing.T) {
+		order := &entity.Order{Type: entity.OrderTypeSell, Kind: entity.OrderKindLimit, Price: big.NewFloat(100), RemainingQuantity: big.NewFloat(3)}
+
+		assert.Equal(t, "BTC", order.ReservedAsset("BTC", "USDT"))
+		assert.Zero(t, big.NewFloat(3).Cmp(order.ReservedAmount()))
+		assert.Zero(t, big.NewFloat(2.5).Cmp(order.Fill(big.NewFloat(2.5), big.NewFloat(90))))
+	})
+
+	t.Run("market buys reserve their quote budget and spend the notional", func(t *testing.T) {
+		order := &entity.Order{Type: entity.OrderTypeBuy, Kind: entity.OrderKindMarket, Quantity: new(big.Float), QuoteQuantity: big.NewFloat(400), RemainingQuoteQuantity: big.NewFloat(240)}
+
+		assert.Equal(t, "USDT", order.ReservedAsset("BTC", "USDT"))
+		assert.Zero(t, big.NewFloat(240).Cmp(order.ReservedAmount()))
+		assert.Zero(t, big.NewFloat(180).Cmp(order.Fill(big.NewFloat(2), big.NewFloat(90))))
+	})
+}
+
+func TestOrder_ReservationSurvivesReload(t *testing.T) {
+	// arrange
+	var request dto.CreateOrderRequest
+	body := `{"account_id":"acc-123","instrument_id":"inst-456","type":"BUY","price":"30000.1234567891","quantity":"0.123456789012345678"}`
+	assert.NoError(t, json.Unmarshal([]byte(body), &request))
+	created, err := entity.ToEntity(request)
+	assert.NoError(t, err)
+	reserved := created.ReservedAmount()
+
+	// act: read back the way the repository does
+	price, _ := decimal.Parse(created.Price.Text('f', decimal.PriceScale))
+	quantity, _ := decimal.Parse(created.RemainingQuantity.Text('f', decimal.AmountScale))
+	reloaded := entity.Order{Type: entity.OrderTypeBuy, Kind: entity.OrderKindLimit, Price: price, Quantity: quantity, RemainingQuantity: quantity}
+
+	// assert
+	assert.Equal(t, "3703.718911949134400358", reserved.Text('f', decimal.AmountScale))
+	assert.Zero(t, reserved.Cmp(reloaded.ReservedAmount()), "the release must match the reservation")
+
+	used := new(big.Float)
+	for _, part := range []string{"0.1", "0.02", "0.003456789012345678"} {
+		q, _ := decimal.Parse(part)
+		used.Add(used, reloaded.Fill(q, big.NewFloat(30000)))
+	}
+	assert.Equal(t, entity.OrderStatusFilled, reloaded.Status)
+	assert.Zero(t, reserved.Cmp(used), "partial fills use exactly what was reserved")
+}
+
+func TestOrder_SameRequest(t *testing.T) {
+	request := dto.CreateOrderRequest{
+		AccountID:    "acc-123",
+		InstrumentID: "inst-456",
+		Type:         "BUY",
+		Price:        newBigFloat("100.5"),
+		Quantity:     newBigFloat("2"),
+	}
+	stored, err := entity.ToEntity(request)
+	assert.NoError(t, err)
+	stored.ID = "order-1"
+	stored.Status = entity.OrderStatusCancelled
+
+	t.Run("a retry of the same request matches", func(t *testing.T) {
+		retried, _ := entity.ToEntity(request)
+		assert.True(t, stored.SameRequest(*retried))
+	})
+
+	t.Run("a different price or size does not match", func(t *testing.T) {
+		cheaper := request
+		cheaper.Price = newBigFloat("100.4")
+		retried, _ := entity.ToEntity(cheaper)
+		assert.False(t, stored.SameRequest(*retried))
+
+		larger := request
+		larger.Quantity = newBigFloat("3")
+		retried, _ = entity.ToEntity(larger)
+		assert.False(t, stored.SameRequest(*retried))
+	})
+
+	t.Run("a different side or instrument does not match", func(t *testing.T) {
+		sell := request
+		sell.Type = "SELL"
+		retried, _ := entity.ToEntity(sell)
+		assert.False(t, stored.SameRequest(*retried))
+
+		other := request
+		other.InstrumentID = "inst-789"
+		retried, _ = entity.ToEntity(other)
+		assert.False(t, stored.SameRequest(*retried))
+	})
+}
+
+func TestToEntity_Market(t *testing.T) {
+	t.Run("market buy is sized by quote quantity", func(t *testing.T) {
+		// arrange
+		request := dto.CreateOrderRequest{
+			AccountID:     "acc-123",
+			InstrumentID:  "inst-456",
+			Type:          "BUY",
+			Kind:          "MARKET",
+			QuoteQuantity: newBigFloat("100"),
+		}
+
+		// act
+		order, err := entity.ToEntity(request)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, entity.OrderKindMarket, order.Kind)
+		assert.True(t, order.IsMarket())
+		assert.True(t, order.IsQuoteSized())
+		assert.Nil(t, order.Price)
+		assert.Zero(t, order.Quantity.Sign())
+		assert.Zero(t, big.NewFloat(100).Cmp(order.RemainingQuoteQuantity))
+	})
+
+	t.Run("kind defaults to LIMIT", func(t *testing.T) {
+		request := dto.CreateOrderRequest{
+			AccountID:    "acc-123",
+			InstrumentID: "inst-456",
+			Type:         "SELL",
+			Price:        newBigFloat("10"),
+			Quantity:     newBigFloat("1"),
+		}
+
+		order, err := entity.ToEntity(request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.OrderKindLimit, order.Kind)
+		assert.False(t, order.IsQuoteSized())
+	})
+}
+
+func TestOrder_Fill_QuoteSized(t *testing.T) {
+	// arrange
+	order := &entity.Order{
+		Type:                   entity.OrderTypeBuy,
+		Kind:                   entity.OrderKindMarket,
+		Status:                 entity.OrderStatusOpen,
+		Quantity:               new(big.Float),
+		RemainingQuantity:      new(big.Float),
+		QuoteQuantity:          big.NewFloat(100),
+		RemainingQuoteQuantity: big.NewFloat(100),
+	}
+
+	// act & assert
+	assert.Zero(t, big.NewFloat(2.5).Cmp(order.ExecutableQuantity(big.NewFloat(40))))
+
+	order.Fill(big.NewFloat(1), big.NewFloat(40))
+	assert.Equal(t, entity.OrderStatusPartiallyFilled, order.Status)
+	assert.Zero(t, big.NewFloat(60).Cmp(order.RemainingQuoteQuantity))
+	assert.Zero(t, big.NewFloat(1).Cmp(order.Quantity))
+
+	order.Fill(big.NewFloat(1.5), big.NewFloat(40))
+	assert.Equal(t, entity.OrderStatusFilled, order.Status)
+	assert.Zero(t, order.RemainingQuoteQuantity.Sign())
+	assert.Zero(t, big.NewFloat(2.5).Cmp(order.Quantity))
+}
+
+func TestToEntity_TimeInForce(t *testing.T) {
+	t.Run("limit orders default to GTC", func(t *testing.T) {
+		order, err := entity.ToEntity(dto.CreateOrderRequest{
+			AccountID: "acc-123", InstrumentID: "inst-456", Type: "BUY",
+			Price: newBigFloat("10"), Quantity: newBigFloat("1"),
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.TimeInForceGTC, order.TimeInForce)
+		assert.True(t, order.CanRest())
+	})
+
+	t.Run("market orders default to IOC", func(t *testing.T) {
+		order, err := entity.ToEntity(dto.CreateOrderRequest{
+			AccountID: "acc-123", InstrumentID: "inst-456", Type: "SELL", Kind: "MARKET",
+			Quantity: newBigFloat("1"),
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.TimeInForceIOC, order.TimeInForce)
+		assert.False(t, order.CanRest())
+	})
+
+	t.Run("GTD keeps its deadline", func(t *testing.T) {
+		expiresAt := time.Now().Add(time.Hour)
+		order, err := entity.ToEntity(dto.CreateOrderRequest{
+			AccountID: "acc-123", InstrumentID: "inst-456", Type: "BUY",
+			Price: newBigFloat("10"), Quantity: newBigFloat("1"),
+			TimeInForce: "GTD", ExpiresAt: &expiresAt,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.TimeInForceGTD, order.TimeInForce)
+		assert.True(t, order.CanRest())
+		assert.False(t, order.IsExpired(time.Now()))
+		assert.True(t, order.IsExpired(expiresAt))
+	})
+}
+
+func TestOrder_CanRest(t *testing.T) {
+	assert.False(t, (&entity.Order{Kind: entity.OrderKindLimit, TimeInForce: entity.TimeInForceIOC}).CanRest())
+	assert.False(t, (&entity.Order{Kind: entity.OrderKindLimit, TimeInForce: entity.TimeInForceFOK}).CanRest())
+	assert.True(t, (&entity.Order{Kind: entity.OrderKindLimit, TimeInForce: entity.TimeInForceGTD}).CanRest())
+}
+
+func TestToEntity_PostOnly(t *testing.T) {
+	// arrange
+	request := dto.CreateOrderRequest{
+		AccountID: "acc-123", InstrumentID: "inst-456", Type: "BUY",
+		Price: newBigFloat("10"), Quantity: newBigFloat("1"),
+		PostOnly: true, RepriceOnCross: true,
+	}
+
+	// act
+	order, err := entity.ToEntity(request)
+
+	// assert
+	assert.NoError(t, err)
+	assert.True(t, order.PostOnly)
+	assert.True(t, order.RepriceOnCross)
+	assert.True(t, order.ToDTO().PostOnly)
+}
+
+func TestOrder_IsPostOnlyLimit(t *testing.T) {
+	assert.True(t, (&entity.Order{Kind: entity.OrderKindLimit, PostOnly: true}).IsPostOnlyLimit())
+	assert.False(t, (&entity.Order{Kind: entity.OrderKindLimit}).IsPostOnlyLimit())
+	assert.False(t, (&entity.Order{Kind: entity.OrderKindStopLimit, PostOnly: true}).IsPostOnlyLimit())
+}
+
+func TestToEntity_ClientOrderID(t *testing.T) {
+	// arrange
+	request := dto.CreateOrderRequest{
+		AccountID: "acc-123", InstrumentID: "inst-456", Type: "BUY",
+		Price: newBigFloat("10"), Quantity: newBigFloat("1"),
+		ClientOrderID: "ladder-1", IdempotencyKey: "retry-key",
+	}
+
+	// act
+	order, err := entity.ToEntity(request)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "ladder-1", order.ClientOrderID)
+	assert.Equal(t, "retry-key", order.IdempotencyKey)
+	assert.Equal(t, "ladder-1", order.ToDTO().ClientOrderID)
+}
+
+func TestOrder_StopTrigger(t *testing.T) {
+	t.Run("sell stop triggers at or below the stop price", func(t *testing.T) {
+		order := &entity.Order{Type: entity.OrderTypeSell, Kind: entity.OrderKindStopMarket, Status: entity.OrderStatusOpen, StopPrice: big.NewFloat(95)}
+
+		assert.True(t, order.IsStop())
+		assert.True(t, order.IsMarket())
+		assert.True(t, order.AwaitsTrigger())
+		assert.False(t, order.ShouldTrigger(nil))
+		assert.False(t, order.ShouldTrigger(big.NewFloat(96)))
+		assert.True(t, order.ShouldTrigger(big.NewFloat(95)))
+		assert.True(t, order.ShouldTrigger(big.NewFloat(90)))
+	})
+
+	t.Run("buy stop triggers at or above the stop price", func(t *testing.T) {
+		order := &entity.Order{Type: entity.OrderTypeBuy, Kind: entity.OrderKindStopLimit, Status: entity.OrderStatusOpen, StopPrice: big.NewFloat(105)}
+
+		assert.False(t, order.IsMarket())
+		assert.False(t, order.ShouldTrigger(big.NewFloat(104)))
+		assert.True(t, order.ShouldTrigger(big.NewFloat(105)))
+	})
+
+	t.Run("triggered orders are active and do not trigger again", func(t *testing.T) {
+		// arrange
+		order := &entity.Order{Type: entity.OrderTypeBuy, Kind: entity.OrderKindStopLimit, Status: entity.OrderStatusOpen, StopPrice: big.NewFloat(105)}
+		now := time.Now()
+
+		// act
+		order.Trigger(now)
+
+		// assert
+		assert.Equal(t, entity.OrderStatusTriggered, order.Status)
+		assert.Equal(t, &now, order.TriggeredAt)
+		assert.True(t, order.IsActive())
+		assert.False(t, order.AwaitsTrigger())
+		assert.False(t, order.ShouldTrigger(big.NewFloat(110)))
+	})
+}
+
+func TestOrder_Trail(t *testing.T) {
+	t.Run("sell trails the highest price by an amount", func(t *testing.T) {
+		order := &entity.Order{Type: entity.OrderTypeSell, Kind: entity.OrderKindTrailingStopMarket, Status: entity.OrderStatusOpen, TrailAmount: big.NewFloat(5)}
+
+		assert.True(t, order.IsTrailing())
+		assert.True(t, order.IsMarket())
+		assert.False(t, order.ShouldTrigger(big.NewFloat(1)), "no trigger price before the first trade")
+
+		assert.True(t, order.Trail(big.NewFloat(100)))
+		assert.Zero(t, big.NewFloat(95).Cmp(order.StopPrice))
+		assert.False(t, order.Trail(big.NewFloat(98)), "a lower price leaves the trigger where it is")
+		assert.Zero(t, big.NewFloat(95).Cmp(order.StopPrice))
+		assert.True(t, order.Trail(big.NewFloat(110)))
+		assert.Zero(t, big.NewFloat(110).Cmp(order.WaterMark))
+		assert.Zero(t, big.NewFloat(105).Cmp(order.StopPrice))
+
+		assert.False(t, order.ShouldTrigger(big.NewFloat(106)))
+		assert.True(t, order.ShouldTrigger(big.NewFloat(105)))
+	})
+
+	t.Run("buy trails the lowest price by a percentage", func(t *testing.T) {
+		order := &entity.Order{Type: entity.OrderTypeBuy, Kind: entity.OrderKindTrailingStopMarket, Status: entity.OrderStatusOpen, TrailPercent: big.NewFloat(10)}
+
+		assert.True(t, order.Trail(big.NewFloat(100)))
+		assert.Zero(t, big.NewFloat(110).Cmp(order.StopPrice))
+		assert.False(t, order.Trail(big.NewFloat(101)))
+		assert.True(t, order.Trail(big.NewFloat(90)))
+		assert.Zero(t, big.NewFloat(99).Cmp(order.StopPrice))
+
+		assert.False(t, order.ShouldTrigger(big.NewFloat(98)))
+		assert.True(t, order.ShouldTrigger(big.NewFloat(99)))
+	})
+
+	t.Run("fixed and triggered stops do not trail", func(t *testing.T) {
+		stop := &entity.Order{Type: entity.OrderTypeSell, Kind: entity.OrderKindStopMarket, Status: entity.OrderStatusOpen, StopPrice: big.NewFloat(95)}
+		triggered := &entity.Order{Type: entity.OrderTypeSell, Kind: entity.OrderKindTrailingStopMarket, Status: entity.OrderStatusOpen, TrailAmount: big.NewFloat(5)}
+		triggered.Trigger(time.Now())
+
+		assert.False(t, stop.Trail(big.NewFloat(120)))
+		assert.Zero(t, big.NewFloat(95).Cmp(stop.StopPrice))
+		assert.False(t, triggered.Trail(big.NewFloat(120)))
+		assert.Nil(t, triggered.StopPrice)
+	})
+
+	t.Run("DTO shows the current trigger price", func(t *testing.T) {
+		order := &entity.Order{Type: entity.OrderTypeSell, Kind: entity.OrderKindTrailingStopMarket, Status: entity.OrderStatusOpen, TrailAmount: big.NewFloat(5), Quantity: big.NewFloat(1), RemainingQuantity: big.NewFloat(1)}
+		order.Trail(big.NewFloat(100))
+
+		orderDTO := order.ToDTO()
+
+		assert.Nil(t, orderDTO.StopPrice)
+		assert.Zero(t, big.NewFloat(95).Cmp(orderDTO.CurrentTriggerPrice))
+		assert.Zero(t, big.NewFloat(5).Cmp(orderDTO.TrailAmount))
+	})
+}
+
+func TestOrder_Iceberg(t *testing.T) {
+	// arrange
+	order := &entity.Order{
+		Type:              entity.OrderTypeSell,
+		Kind:              entity.OrderKindLimit,
+		Status:            entity.OrderStatusOpen,
+		Price:             big.NewFloat(100),
+		Quantity:          big.NewFloat(5),
+		RemainingQuantity: big.NewFloat(5),
+		DisplayQuantity:   big.NewFloat(2),
+	}
+
+	// act & assert
+	assert.True(t, order.IsIceberg())
+	assert.True(t, order.Refill())
+	assert.Zero(t, big.NewFloat(2).Cmp(order.ShownQuantity()))
+	assert.False(t, order.Refill(), "a slice that is still visible is not refilled")
+
+	order.Fill(big.NewFloat(2), big.NewFloat(100))
+	assert.Zero(t, order.VisibleQuantity.Sign())
+	assert.True(t, order.Refill())
+	assert.Zero(t, big.NewFloat(2).Cmp(order.VisibleQuantity))
+
+	order.Fill(big.NewFloat(2), big.NewFloat(100))
+	assert.True(t, order.Refill())
+	assert.Zero(t, big.NewFloat(1).Cmp(order.VisibleQuantity), "the last slice is what is left")
+}
+
+func TestOrder_DefaultSelfTradePrevention(t *testing.T) {
+	testCases := []struct {
+		name           string
+		order          entity.SelfTradePrevention
+		accountDefault string
+		expected       entity.SelfTradePrevention
+	}{
+		{name: "order choice wins", order: entity.SelfTradePreventionCancelOldest, accountDefault: "CANCEL_BOTH", expected: entity.SelfTradePreventionCancelOldest},
+		{name: "account default", accountDefault: "CANCEL_BOTH", expected: entity.SelfTradePreventionCancelBoth},
+		{name: "none", expected: entity.SelfTradePreventionNone},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			order := &entity.Order{SelfTradePrevention: tc.order}
+			order.DefaultSelfTradePrevention(tc.accountDefault)
+			assert.Equal(t, tc.expected, order.SelfTradePrevention)
+		})
+	}
+}
+
+func TestOrder_Decrement(t *testing.T) {
+	t.Run("limit buy releases its price for the decremented quantity", func(t *testing.T) {
+		// arrange
+		order := &entity.Order{
+			Type: entity.OrderTypeBuy, Kind: entity.OrderKindLimit, Status: entity.OrderStatusOpen,
+			Price: big.NewFloat(100), Quantity: big.NewFloat(5), RemainingQuantity: big.NewFloat(5),
+		}
+
+		// act
+		released := order.Decrement(big.NewFloat(2), big.NewFloat(99))
+
+		// assert
+		assert.Equal(t, 0, released.Cmp(big.NewFloat(200)))
+		assert.Equal(t, 0, order.Quantity.Cmp(big.NewFloat(3)))
+		assert.Equal(t, 0, order.RemainingQuantity.Cmp(big.NewFloat(3)))
+		assert.Equal(t, entity.OrderStatusOpen, order.Status)
+	})
+
+	t.Run("order with nothing left is cancelled", func(t *testing.T) {
+		// arrange
+		order := &entity.Order{
+			Type: entity.OrderTypeSell, Kind: entity.OrderKindLimit, Status: entity.OrderStatusOpen,
+			Price: big.NewFloat(100), Quantity: big.NewFloat(2), RemainingQuantity: big.NewFloat(2),
+		}
+
+		// act
+		released := order.Decrement(big.NewFloat(2), big.NewFloat(100))
+
+		// assert
+		assert.Equal(t, 0, released.Cmp(big.NewFloat(2)))
+		assert.Equal(t, entity.OrderStatusCancelled, order.Status)
+	})
+
+	t.Run("quote-sized order loses the notional", func(t *testing.T) {
+		// arrange
+		order := &entity.Order{
+			Type: entity.OrderTypeBuy, Kind: entity.OrderKindMarket, Status: entity.OrderStatusOpen,
+			Quantity: new(big.Float), RemainingQuantity: new(big.Float),
+			QuoteQuantity: big.NewFloat(500), RemainingQuoteQuantity: big.NewFloat(500),
+		}
+
+		// act
+		released := order.Decrement(big.NewFloat(2), big.NewFloat(100))
+
+		// assert
+		assert.Equal(t, 0, released.Cmp(big.NewFloat(200)))
+		assert.Equal(t, 0, order.RemainingQuoteQuantity.Cmp(big.NewFloat(300)))
+	})
+}
@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"math/big"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/decimal"
+)
+
+// SelfTradePrevention decides what happens when an incoming order would trade
+// against a resting order of the same account. The incoming order's policy
+// applies.
+type SelfTradePrevention string
+
+const (
+	// SelfTradePreventionNone lets the orders trade with each other.
+	SelfTradePreventionNone SelfTradePrevention = "NONE"
+	// SelfTradePreventionCancelNewest cancels the rest of the incoming order.
+	SelfTradePreventionCancelNewest SelfTradePrevention = "CANCEL_NEWEST"
+	// SelfTradePreventionCancelOldest cancels the resting order and keeps
+	// matching the incoming one.
+	SelfTradePreventionCancelOldest SelfTradePrevention = "CANCEL_OLDEST"
+	// SelfTradePreventionCancelBoth cancels both orders.
+	SelfTradePreventionCancelBoth SelfTradePrevention = "CANCEL_BOTH"
+	// SelfTradePreventionDecrementAndCancel reduces both orders by the
+	// quantity they would have traded without trading it; whichever has
+	// nothing left is cancelled.
+	SelfTradePreventionDecrementAndCancel SelfTradePrevention = "DECREMENT_AND_CANCEL"
+)
+
+// DefaultSelfTradePrevention applies the account's policy to an order that did
+// not choose one. Orders end up with NONE when neither did.
+func (o *Order) DefaultSelfTradePrevention(accountDefault string) {
+	if o.SelfTradePrevention == "" {
+		o.SelfTradePrevention = SelfTradePrevention(accountDefault)
+	}
+	if o.SelfTradePrevention == "" {
+		o.SelfTradePrevention = SelfTradePreventionNone
+	}
+}
+
+// PreventsSelfTrade reports whether the order must not trade against maker.
+func (o *Order) PreventsSelfTrade(maker *Order) bool {
+	return o.AccountID == maker.AccountID &&
+		o.SelfTradePrevention != "" &&
+		o.SelfTradePrevention != SelfTradePreventionNone
+}
+
+// Decrement takes quantity off the open size of the order without executing
+// it, as decrement-and-cancel does, and cancels the order once nothing is left.
+// Quote-sized orders lose the notional of quantity at price. It returns the
+// part of the reservation the order no longer needs.
+func (o *Order) Decrement(quantity, price *big.Float) *big.Float {
+	before := o.ReservedAmount()
+
+	if o.IsQuoteSized() {
+		notional := decimal.Round(new(big.Float).SetPrec(decimal.Precision).Mul(price, quantity), decimal.AmountScale)
+		o.QuoteQuantity = new(big.Float).Sub(o.QuoteQuantity, notional)
+		o.RemainingQuoteQuantity = new(big.Float).Sub(o.RemainingQuoteQuantity, notional)
+		return new(big.Float).Sub(before, o.ReservedAmount())
+	}
+
+	o.Quantity = new(big.Float).Sub(o.Quantity, quantity)
+	o.RemainingQuantity = new(big.Float).Sub(o.RemainingQuantity, quantity)
+	if o.VisibleQuantity != nil {
+		o.VisibleQuantity = new(big.Float).Sub(o.VisibleQuantity, quantity)
+		if o.VisibleQuantity.Sign() < 0 {
+			o.VisibleQuantity = new(big.Float)
+		}
+	}
+	if o.RemainingQuantity.Sign() <= 0 {
+		o.RemainingQuantity = new(big.Float)
+		o.Status = OrderStatusCancelled
+	}
+	return new(big.Float).Sub(before, o.ReservedAmount())
+}
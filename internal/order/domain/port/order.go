@@ -8,12 +8,33 @@ import (
 
 type OrderRepository interface {
 	Create(ctx context.Context, order entity.Order) (string, error)
+	CreateBatch(ctx context.Context, orders []entity.Order) ([]string, error)
 	FindByID(ctx context.Context, id string) (entity.Order, error)
+	FindByClientOrderID(ctx context.Context, accountID, clientOrderID string) (entity.Order, error)
+	FindByIdempotencyKey(ctx context.Context, accountID, key string) (entity.Order, error)
 	GetAll(ctx context.Context) ([]entity.Order, error)
 	Update(ctx context.Context, order entity.Order) error
+	ClearIdempotencyKey(ctx context.Context, id string) error
 	FindByInstrumentID(ctx context.Context, instrumentID string) ([]entity.Order, error)
+	FindActive(ctx context.Context) ([]entity.Order, error)
+	FindActiveByInstrumentID(ctx context.Context, instrumentID string) ([]entity.Order, error)
+	FindByOrderListID(ctx context.Context, orderListID string) ([]entity.Order, error)
+}
+
+// OrderListRepository persists order lists. Lists are created without their
+// orders, which are inserted through OrderRepository; they are read back with
+// them.
+type OrderListRepository interface {
+	Create(ctx context.Context, list entity.OrderList) (string, error)
+	FindByID(ctx context.Context, id string) (entity.OrderList, error)
+	FindForUpdate(ctx context.Context, id string) (entity.OrderList, error)
+	UpdateStatus(ctx context.Context, id string, status entity.OrderListStatus) error
 }
 
 type OrderQueue interface {
 	PublishOrder(ctx context.Context, order entity.Order) error
+	PublishOrders(ctx context.Context, orders []entity.Order) (int, error)
+	PublishAmend(ctx context.Context, amendment entity.Amendment) error
+	PublishCancel(ctx context.Context, order entity.Order) (entity.CancelResult, error)
+	PublishCancelAll(ctx context.Context, filter entity.CancelFilter) ([]string, error)
 }
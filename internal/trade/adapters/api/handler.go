@@ -0,0 +1,52 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/trade/app"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/trade/domain/dto"
+)
+
+type Trade interface {
+	GetTrades(ctx echo.Context) error
+	RegisterRoutes(g *echo.Group)
+}
+
+type trade struct {
+	tradeApp app.Trade
+}
+
+func NewTradeHandler(tradeApp app.Trade) Trade {
+	return &trade{
+		tradeApp: tradeApp,
+	}
+}
+
+func (h *trade) RegisterRoutes(g *echo.Group) {
+	g.GET("", h.GetTrades)
+}
+
+// GetTrades godoc
+// @Summary      Lista as execuções (trades)
+// @Tags         trades
+// @Produce      json
+// @Param        instrument_id  query   string  false  "Instrument ID"
+// @Param        account_id     query   string  false  "Account ID (maker ou taker)"
+// @Success      200  {array}   dto.TradeDTO
+// @Router       /v1/trades [get]
+func (h *trade) GetTrades(ctx echo.Context) error {
+	filter := dto.TradeFilter{
+		InstrumentID: ctx.QueryParam("instrument_id"),
+		AccountID:    ctx.QueryParam("account_id"),
+	}
+
+	trades, err := h.tradeApp.GetTrades(ctx.Request().Context(), filter)
+	if err != nil {
+		slog.Error("error getting trades", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "an unexpected error occurred")
+	}
+
+	return ctx.JSON(http.StatusOK, trades)
+}
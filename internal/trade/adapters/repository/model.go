@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/trade/domain/entity"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/decimal"
+)
+
+type TradeModel struct {
+	ID             string    `db:"id"`
+	InstrumentID   string    `db:"instrument_id"`
+	MakerOrderID   string    `db:"maker_order_id"`
+	TakerOrderID   string    `db:"taker_order_id"`
+	MakerAccountID string    `db:"maker_account_id"`
+	TakerAccountID string    `db:"taker_account_id"`
+	AggressorSide  string    `db:"aggressor_side"`
+	Price          string    `db:"price"`
+	Quantity       string    `db:"quantity"`
+	Fee            string    `db:"fee"`
+	FeeAsset       *string   `db:"fee_asset"`
+	MakerFee       string    `db:"maker_fee"`
+	MakerFeeAsset  *string   `db:"maker_fee_asset"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+func ToModel(trade entity.Trade) *TradeModel {
+	return &TradeModel{
+		ID:             trade.ID,
+		InstrumentID:   trade.InstrumentID,
+		MakerOrderID:   trade.MakerOrderID,
+		TakerOrderID:   trade.TakerOrderID,
+		MakerAccountID: trade.MakerAccountID,
+		TakerAccountID: trade.TakerAccountID,
+		AggressorSide:  string(trade.AggressorSide),
+		Price:          trade.Price.Text('f', 10),
+		Quantity:       trade.Quantity.Text('f', 18),
+		Fee:            formatFee(trade.Fee),
+		FeeAsset:       nullIfEmpty(trade.FeeAsset),
+		MakerFee:       formatFee(trade.MakerFee),
+		MakerFeeAsset:  nullIfEmpty(trade.MakerFeeAsset),
+		CreatedAt:      trade.CreatedAt,
+	}
+}
+
+func (m *TradeModel) ToEntity() entity.Trade {
+	price, _ := decimal.Parse(m.Price)
+	quantity, _ := decimal.Parse(m.Quantity)
+	fee, ok := decimal.Parse(m.Fee)
+	if !ok {
+		fee = new(big.Float)
+	}
+	makerFee, ok := decimal.Parse(m.MakerFee)
+	if !ok {
+		makerFee = new(big.Float)
+	}
+	return entity.Trade{
+		ID:             m.ID,
+		InstrumentID:   m.InstrumentID,
+		MakerOrderID:   m.MakerOrderID,
+		TakerOrderID:   m.TakerOrderID,
+		MakerAccountID: m.MakerAccountID,
+		TakerAccountID: m.TakerAccountID,
+		AggressorSide:  entity.Side(m.AggressorSide),
+		Price:          price,
+		Quantity:       quantity,
+		Fee:            fee,
+		FeeAsset:       valueOrEmpty(m.FeeAsset),
+		MakerFee:       makerFee,
+		MakerFeeAsset:  valueOrEmpty(m.MakerFeeAsset),
+		CreatedAt:      m.CreatedAt,
+	}
+}
+
+func formatFee(fee *big.Float) string {
+	if fee == nil {
+		return "0"
+	}
+	return fee.Text('f', 18)
+}
+
+func nullIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
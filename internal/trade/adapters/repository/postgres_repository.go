@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/db"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/trade/domain/entity"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/trade/domain/port"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/decimal"
+	"github.com/mthpedrosa/financial-exchange-challenge/pkg/ierr"
+)
+
+type tradeRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTradeRepository(db *pgxpool.Pool) port.TradeRepository {
+	return &tradeRepository{db: db}
+}
+
+func (r *tradeRepository) Create(ctx context.Context, trade entity.Trade) (string, error) {
+	m := ToModel(trade)
+	query := `INSERT INTO trades (instrument_id, maker_order_id, taker_order_id, maker_account_id, taker_account_id, aggressor_side, price, quantity, fee, fee_asset, maker_fee, maker_fee_asset, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW()) RETURNING id`
+	var id string
+	err := db.Conn(ctx, r.db).QueryRow(ctx, query,
+		m.InstrumentID,
+		m.MakerOrderID,
+		m.TakerOrderID,
+		m.MakerAccountID,
+		m.TakerAccountID,
+		m.AggressorSide,
+		m.Price,
+		m.Quantity,
+		m.Fee,
+		m.FeeAsset,
+		m.MakerFee,
+		m.MakerFeeAsset,
+	).Scan(&id)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *tradeRepository) FindAll(ctx context.Context, filter entity.TradeFilter) ([]entity.Trade, error) {
+	query := `SELECT id, instrument_id, maker_order_id, taker_order_id, maker_account_id, taker_account_id, aggressor_side, price, quantity, fee, fee_asset, maker_fee, maker_fee_asset, created_at FROM trades`
+	var args []interface{}
+	var conditions []string
+	argID := 1
+
+	if filter.InstrumentID != "" {
+		conditions = append(conditions, fmt.Sprintf("instrument_id = $%d", argID))
+		args = append(args, filter.InstrumentID)
+		argID++
+	}
+
+	if filter.AccountID != "" {
+		conditions = append(conditions, fmt.Sprintf("(maker_account_id = $%d OR taker_account_id = $%d)", argID, argID))
+		args = append(args, filter.AccountID)
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += " ORDER BY created_at DESC"
+
+	rows, err := db.Conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var trades []entity.Trade
+	for rows.Next() {
+		var m TradeModel
+		if err := rows.Scan(
+			&m.ID,
+			&m.InstrumentID,
+			&m.MakerOrderID,
+			&m.TakerOrderID,
+			&m.MakerAccountID,
+			&m.TakerAccountID,
+			&m.AggressorSide,
+			&m.Price,
+			&m.Quantity,
+			&m.Fee,
+			&m.FeeAsset,
+			&m.MakerFee,
+			&m.MakerFeeAsset,
+			&m.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		trades = append(trades, m.ToEntity())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return trades, nil
+}
+
+// NotionalByAccount sums the notional each account traded since the given
+// time, per quote asset. Trades count for both the maker and the taker, once
+// when they are the same account.
+func (r *tradeRepository) NotionalByAccount(ctx context.Context, since time.Time) ([]entity.AccountNotional, error) {
+	query := `
+        SELECT t.account_id, i.quote_asset, SUM(t.price * t.quantity)::TEXT
+        FROM (
+            SELECT maker_account_id AS account_id, instrument_id, price, quantity FROM trades WHERE created_at >= $1
+            UNION ALL
+            SELECT taker_account_id, instrument_id, price, quantity FROM trades
+            WHERE created_at >= $1 AND taker_account_id <> maker_account_id
+        ) t
+        JOIN instruments i ON i.id = t.instrument_id
+        GROUP BY t.account_id, i.quote_asset`
+
+	rows, err := db.Conn(ctx, r.db).Query(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notionals []entity.AccountNotional
+	for rows.Next() {
+		var n entity.AccountNotional
+		var notional string
+		if err := rows.Scan(&n.AccountID, &n.QuoteAsset, &notional); err != nil {
+			return nil, err
+		}
+		n.Notional, _ = decimal.Parse(notional)
+		notionals = append(notionals, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return notionals, nil
+}
+
+// LastPrice returns the price of the most recent trade on the instrument
+// quoting baseAsset in quoteAsset, or ErrNotFound when there is none.
+func (r *tradeRepository) LastPrice(ctx context.Context, baseAsset, quoteAsset string) (*big.Float, error) {
+	query := `
+        SELECT t.price::TEXT
+        FROM trades t
+        JOIN instruments i ON i.id = t.instrument_id
+        WHERE i.base_asset = $1 AND i.quote_asset = $2
+        ORDER BY t.created_at DESC
+        LIMIT 1`
+
+	var price string
+	err := db.Conn(ctx, r.db).QueryRow(ctx, query, baseAsset, quoteAsset).Scan(&price)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ierr.ErrNotFound
+		}
+		return nil, err
+	}
+	last, _ := decimal.Parse(price)
+	return last, nil
+}
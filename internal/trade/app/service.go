@@ -0,0 +1,31 @@
+package app
+
+import (
+	"context"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/trade/domain/dto"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/trade/domain/entity"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/trade/domain/port"
+)
+
+type Trade interface {
+	GetTrades(ctx context.Context, filter dto.TradeFilter) ([]dto.TradeDTO, error)
+}
+
+type trade struct {
+	tradePort port.TradeRepository
+}
+
+func NewTradeApp(tradePort port.TradeRepository) Trade {
+	return &trade{
+		tradePort: tradePort,
+	}
+}
+
+func (t *trade) GetTrades(ctx context.Context, filter dto.TradeFilter) ([]dto.TradeDTO, error) {
+	trades, err := t.tradePort.FindAll(ctx, entity.ToEntityFilter(filter))
+	if err != nil {
+		return nil, err
+	}
+	return entity.ToListDTO(trades), nil
+}
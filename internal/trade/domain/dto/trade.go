@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"math/big"
+	"time"
+)
+
+type TradeDTO struct {
+	ID             string    `json:"id"`
+	InstrumentID   string    `json:"instrument_id"`
+	MakerOrderID   string    `json:"maker_order_id"`
+	TakerOrderID   string    `json:"taker_order_id"`
+	MakerAccountID string    `json:"maker_account_id"`
+	TakerAccountID string    `json:"taker_account_id"`
+	AggressorSide  string    `json:"aggressor_side"`
+	Price          big.Float `json:"price"`
+	Quantity       big.Float `json:"quantity"`
+	Fee            big.Float `json:"fee"`
+	FeeAsset       string    `json:"fee_asset,omitempty"`
+	MakerFee       big.Float `json:"maker_fee"`
+	MakerFeeAsset  string    `json:"maker_fee_asset,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type TradeFilter struct {
+	InstrumentID string `query:"instrument_id"`
+	AccountID    string `query:"account_id"`
+}
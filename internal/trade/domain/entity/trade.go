@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/trade/domain/dto"
+)
+
+type Side string
+
+const (
+	SideBuy  Side = "BUY"
+	SideSell Side = "SELL"
+)
+
+// Trade is an execution between a resting maker order and an incoming taker
+// order. AggressorSide is the side of the taker. Fee is what the taker paid,
+// in FeeAsset, and MakerFee what the maker paid, in MakerFeeAsset.
+type Trade struct {
+	ID             string
+	InstrumentID   string
+	MakerOrderID   string
+	TakerOrderID   string
+	MakerAccountID string
+	TakerAccountID string
+	AggressorSide  Side
+	Price          *big.Float
+	Quantity       *big.Float
+	Fee            *big.Float
+	FeeAsset       string
+	MakerFee       *big.Float
+	MakerFeeAsset  string
+	CreatedAt      time.Time
+}
+
+// AccountNotional is the notional an account traded in one quote asset, on
+// either side of its trades.
+type AccountNotional struct {
+	AccountID  string
+	QuoteAsset string
+	Notional   *big.Float
+}
+
+type TradeFilter struct {
+	InstrumentID string
+	AccountID    string
+}
+
+func (t *Trade) ToDTO() dto.TradeDTO {
+	return dto.TradeDTO{
+		ID:             t.ID,
+		InstrumentID:   t.InstrumentID,
+		MakerOrderID:   t.MakerOrderID,
+		TakerOrderID:   t.TakerOrderID,
+		MakerAccountID: t.MakerAccountID,
+		TakerAccountID: t.TakerAccountID,
+		AggressorSide:  string(t.AggressorSide),
+		Price:          *t.Price,
+		Quantity:       *t.Quantity,
+		Fee:            *orZero(t.Fee),
+		FeeAsset:       t.FeeAsset,
+		MakerFee:       *orZero(t.MakerFee),
+		MakerFeeAsset:  t.MakerFeeAsset,
+		CreatedAt:      t.CreatedAt,
+	}
+}
+
+func orZero(amount *big.Float) *big.Float {
+	if amount == nil {
+		return new(big.Float)
+	}
+	return amount
+}
+
+func ToListDTO(trades []Trade) []dto.TradeDTO {
+	dtos := make([]dto.TradeDTO, len(trades))
+	for i, t := range trades {
+		dtos[i] = t.ToDTO()
+	}
+	return dtos
+}
+
+func ToEntityFilter(dto dto.TradeFilter) TradeFilter {
+	return TradeFilter{
+		InstrumentID: dto.InstrumentID,
+		AccountID:    dto.AccountID,
+	}
+}
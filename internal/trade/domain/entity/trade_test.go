@@ -0,0 +1,63 @@
+package entity_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/trade/domain/dto"
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/trade/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrade_ToDTO(t *testing.T) {
+	// arrange
+	trade := entity.Trade{
+		ID:             "trade-1",
+		InstrumentID:   "inst-1",
+		MakerOrderID:   "order-maker",
+		TakerOrderID:   "order-taker",
+		MakerAccountID: "acc-maker",
+		TakerAccountID: "acc-taker",
+		AggressorSide:  entity.SideBuy,
+		Price:          big.NewFloat(100.5),
+		Quantity:       big.NewFloat(2),
+		CreatedAt:      time.Now(),
+	}
+
+	// act
+	tradeDTO := trade.ToDTO()
+
+	// assert
+	assert.Equal(t, "trade-1", tradeDTO.ID)
+	assert.Equal(t, "inst-1", tradeDTO.InstrumentID)
+	assert.Equal(t, "order-maker", tradeDTO.MakerOrderID)
+	assert.Equal(t, "order-taker", tradeDTO.TakerOrderID)
+	assert.Equal(t, "acc-maker", tradeDTO.MakerAccountID)
+	assert.Equal(t, "acc-taker", tradeDTO.TakerAccountID)
+	assert.Equal(t, "BUY", tradeDTO.AggressorSide)
+	assert.Zero(t, trade.Price.Cmp(&tradeDTO.Price))
+	assert.Zero(t, trade.Quantity.Cmp(&tradeDTO.Quantity))
+	assert.Equal(t, trade.CreatedAt, tradeDTO.CreatedAt)
+}
+
+func TestToListDTO(t *testing.T) {
+	trades := []entity.Trade{
+		{ID: "trade-1", Price: big.NewFloat(1), Quantity: big.NewFloat(1)},
+		{ID: "trade-2", Price: big.NewFloat(2), Quantity: big.NewFloat(2)},
+	}
+	dtos := entity.ToListDTO(trades)
+	assert.Len(t, dtos, 2)
+	assert.Equal(t, "trade-1", dtos[0].ID)
+	assert.Equal(t, "trade-2", dtos[1].ID)
+}
+
+func TestToEntityFilter(t *testing.T) {
+	filter := dto.TradeFilter{
+		InstrumentID: "inst-1",
+		AccountID:    "acc-1",
+	}
+	entityFilter := entity.ToEntityFilter(filter)
+	assert.Equal(t, "inst-1", entityFilter.InstrumentID)
+	assert.Equal(t, "acc-1", entityFilter.AccountID)
+}
@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/mthpedrosa/financial-exchange-challenge/internal/trade/domain/entity"
+)
+
+type TradeRepository interface {
+	Create(ctx context.Context, trade entity.Trade) (string, error)
+	FindAll(ctx context.Context, filter entity.TradeFilter) ([]entity.Trade, error)
+	NotionalByAccount(ctx context.Context, since time.Time) ([]entity.AccountNotional, error)
+	LastPrice(ctx context.Context, baseAsset, quoteAsset string) (*big.Float, error)
+}
@@ -0,0 +1,44 @@
+package decimal
+
+import "math/big"
+
+// Precision is the mantissa precision, in bits, stored decimals are parsed
+// and reservations computed at, so the same amount always comes out the same
+// way whether it was read from a request or from the database.
+const Precision = 256
+
+// Scales of the NUMERIC columns: prices are stored with 10 decimals, amounts
+// and quantities with 18.
+const (
+	PriceScale  = 10
+	AmountScale = 18
+)
+
+// Parse parses a decimal string at Precision. ok is false when s is not a
+// number.
+func Parse(s string) (f *big.Float, ok bool) {
+	f, _, err := big.ParseFloat(s, 10, Precision, big.ToNearestEven)
+	if err != nil {
+		return nil, false
+	}
+	return f, true
+}
+
+// ParseOptional parses a nullable decimal column; nil stays nil.
+func ParseOptional(s *string) *big.Float {
+	if s == nil {
+		return nil
+	}
+	f, _ := Parse(*s)
+	return f
+}
+
+// Round rounds x to the given number of decimals, as a NUMERIC column of that
+// scale stores it, and returns it at Precision. nil stays nil.
+func Round(x *big.Float, decimals int) *big.Float {
+	if x == nil {
+		return nil
+	}
+	f, _ := Parse(x.Text('f', decimals))
+	return f
+}